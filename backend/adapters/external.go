@@ -1,7 +1,9 @@
 package adapters
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
@@ -33,6 +35,12 @@ type FetchDataRequest struct {
 	Path     string            `json:"path"` // JSON path to extract
 	Obscured bool              `json:"obscured"` // Obscura Mode
 	Retries  int               `json:"retries"`
+	Timeout  time.Duration     `json:"timeout"` // Per-attempt deadline, client default if zero
+	Exact    bool              `json:"exact"`   // Return numbers as json.Number instead of float64
+
+	// Redirect, if set, must accept every redirect target before it is
+	// followed, since the request's headers go along with it
+	Redirect func(target string) bool `json:"-"`
 }
 
 // Fetch executes the external request with retries
@@ -70,7 +78,28 @@ func (am *AdapterManager) exec(req FetchDataRequest) (interface{}, error) {
 		}
 	}
 
-	httpReq, err := http.NewRequest(req.Method, req.URL, nil)
+	if req.Redirect != nil {
+		checked := *client
+		checked.CheckRedirect = func(next *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			if !req.Redirect(next.URL.String()) {
+				return fmt.Errorf("redirect to %s not allowed", next.URL.Redacted())
+			}
+			return nil
+		}
+		client = &checked
+	}
+
+	ctx := context.Background()
+	if req.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, req.Timeout)
+		defer cancel()
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +119,11 @@ func (am *AdapterManager) exec(req FetchDataRequest) (interface{}, error) {
 	}
 
 	var result interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	decoder := json.NewDecoder(resp.Body)
+	if req.Exact {
+		decoder.UseNumber()
+	}
+	if err := decoder.Decode(&result); err != nil {
 		return nil, fmt.Errorf("json decode error: %w", err)
 	}
 
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// SetOperatorToken enables the operator endpoints, which require it as a
+// bearer token. Without a token they are refused.
+func (ms *MetricsServer) SetOperatorToken(token string) {
+	ms.operatorToken = token
+}
+
+// operatorOnly guards endpoints that change node state. They are left out of
+// the public CORS policy, so browsers on other origins cannot call them.
+func (ms *MetricsServer) operatorOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Del("Access-Control-Allow-Origin")
+		w.Header().Del("Access-Control-Allow-Methods")
+		w.Header().Del("Access-Control-Allow-Headers")
+
+		if !ms.isOperator(r) {
+			if ms.operatorToken == "" {
+				writeError(w, http.StatusForbidden, "operator endpoints are disabled; set api_token")
+				return
+			}
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "missing or invalid operator token")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// isOperator reports whether r carries the operator bearer token
+func (ms *MetricsServer) isOperator(r *http.Request) bool {
+	if ms.operatorToken == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(ms.operatorToken)) == 1
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/obscura-network/obscura-node/oracle"
+)
+
+type stubDeadLetters struct{ requeued []string }
+
+func (s *stubDeadLetters) ListDeadLetters() []oracle.DeadLetter { return nil }
+func (s *stubDeadLetters) GetDeadLetter(id string) (oracle.DeadLetter, bool) {
+	return oracle.DeadLetter{}, true
+}
+func (s *stubDeadLetters) RequeueDeadLetter(id string) error {
+	s.requeued = append(s.requeued, id)
+	return nil
+}
+func (s *stubDeadLetters) DiscardDeadLetter(id string) error { return nil }
+
+func TestDeadLetterActionsRequireOperatorToken(t *testing.T) {
+	ms := NewMetricsServer(NewMetricsCollector(), nil, "0")
+	dlq := &stubDeadLetters{}
+	ms.SetDeadLetterQueue(dlq)
+
+	requeue := func(auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/dead-letters/job-1/requeue", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		ms.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := requeue("Bearer anything"); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 without a configured token, got %d", rec.Code)
+	}
+
+	ms.SetOperatorToken("s3cret")
+	if rec := requeue(""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a token, got %d", rec.Code)
+	}
+	if rec := requeue("Bearer wrong"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 with a wrong token, got %d", rec.Code)
+	}
+	rec := requeue("Bearer s3cret")
+	if rec.Code != http.StatusOK || len(dlq.requeued) != 1 {
+		t.Fatalf("expected requeue with the operator token, got %d", rec.Code)
+	}
+	if rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatal("operator endpoints must not be exposed through CORS")
+	}
+
+	// Browsers cannot preflight operator endpoints
+	preflight := httptest.NewRequest(http.MethodOptions, "/api/dead-letters/job-1", nil)
+	rec = httptest.NewRecorder()
+	ms.router.ServeHTTP(rec, preflight)
+	if rec.Code == http.StatusOK {
+		t.Fatal("expected preflight for an operator endpoint to be refused")
+	}
+}
+
+type stubDisclosures struct{ requested int }
+
+func (s *stubDisclosures) RequestDisclosure(req oracle.DisclosureRequest) (string, error) {
+	s.requested++
+	return "disclosure_1", nil
+}
+func (s *stubDisclosures) GetDisclosure(id string) (oracle.DisclosureResult, bool) {
+	return oracle.DisclosureResult{ID: id}, true
+}
+
+func TestDisclosuresRequireOperatorToken(t *testing.T) {
+	ms := NewMetricsServer(NewMetricsCollector(), nil, "0")
+	ds := &stubDisclosures{}
+	ms.SetDisclosureService(ds)
+	ms.SetOperatorToken("s3cret")
+
+	serve := func(method, path, auth string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(`{"url": "https://bureau.example/score"}`))
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		ms.router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := serve(http.MethodPost, "/api/disclosures", ""); code != http.StatusUnauthorized || ds.requested != 0 {
+		t.Fatalf("expected an anonymous disclosure request to be refused, got %d", code)
+	}
+	if code := serve(http.MethodGet, "/api/disclosures/disclosure_1", ""); code != http.StatusUnauthorized {
+		t.Fatalf("expected anonymous callers not to read disclosure results, got %d", code)
+	}
+	if code := serve(http.MethodPost, "/api/disclosures", "Bearer s3cret"); code != http.StatusAccepted || ds.requested != 1 {
+		t.Fatalf("expected the operator to queue a disclosure, got %d", code)
+	}
+	if code := serve(http.MethodGet, "/api/disclosures/disclosure_1", "Bearer s3cret"); code != http.StatusOK {
+		t.Fatalf("expected the operator to read a disclosure result, got %d", code)
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/obscura-network/obscura-node/crosschain"
+)
+
+// maxCosignRequest bounds a batch of bridge messages sent for cosigning
+const maxCosignRequest = 4 << 20
+
+// BridgeCosigner signs batches relayed by other committee members
+type BridgeCosigner interface {
+	Cosign(ctx context.Context, req crosschain.CosignRequest) (*crosschain.CosignResponse, error)
+}
+
+// SetBridgeCosigner enables the /api/bridge/cosign endpoint
+func (ms *MetricsServer) SetBridgeCosigner(bc BridgeCosigner) {
+	ms.bridge = bc
+}
+
+func (ms *MetricsServer) bridgeCosignHandler(w http.ResponseWriter, r *http.Request) {
+	if ms.bridge == nil {
+		writeError(w, http.StatusNotFound, "bridge cosigning is not enabled")
+		return
+	}
+
+	var req crosschain.CosignRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxCosignRequest)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	resp, err := ms.bridge.Cosign(r.Context(), req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, crosschain.ErrUnverifiedMessage) {
+			status = http.StatusForbidden
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
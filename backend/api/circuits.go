@@ -0,0 +1,27 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+// CircuitRegistry lists circuit versions and their deployed verifiers
+type CircuitRegistry interface {
+	Circuits() []zkp.CircuitInfo
+}
+
+// SetCircuitRegistry enables the /api/circuits endpoint
+func (ms *MetricsServer) SetCircuitRegistry(cr CircuitRegistry) {
+	ms.circuits = cr
+}
+
+func (ms *MetricsServer) circuitsHandler(w http.ResponseWriter, r *http.Request) {
+	if ms.circuits == nil {
+		writeError(w, http.StatusNotFound, "circuit registry is not enabled")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ms.circuits.Circuits())
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	ocr "github.com/obscura-network/obscura-node/consensus"
+)
+
+// maxComputeMessage bounds a commit or reveal; outputs are a few bytes
+const maxComputeMessage = 64 << 10
+
+// ComputeConsensus receives commits and reveals from the compute committee
+type ComputeConsensus interface {
+	HandleComputeMessage(msg ocr.ComputeMessage) error
+}
+
+// SetComputeConsensus enables the /api/compute/consensus endpoint
+func (ms *MetricsServer) SetComputeConsensus(cc ComputeConsensus) {
+	ms.consensus = cc
+}
+
+func (ms *MetricsServer) computeConsensusHandler(w http.ResponseWriter, r *http.Request) {
+	if ms.consensus == nil {
+		writeError(w, http.StatusNotFound, "compute consensus is not enabled")
+		return
+	}
+
+	var msg ocr.ComputeMessage
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxComputeMessage)).Decode(&msg); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := ms.consensus.HandleComputeMessage(msg); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, ocr.ErrInvalidMessage) {
+			status = http.StatusForbidden
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
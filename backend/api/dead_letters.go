@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/obscura-network/obscura-node/oracle"
+)
+
+// DeadLetterQueue exposes jobs that exhausted their retries for operator action
+type DeadLetterQueue interface {
+	ListDeadLetters() []oracle.DeadLetter
+	GetDeadLetter(jobID string) (oracle.DeadLetter, bool)
+	RequeueDeadLetter(jobID string) error
+	DiscardDeadLetter(jobID string) error
+}
+
+// SetDeadLetterQueue enables the /api/dead-letters endpoints
+func (ms *MetricsServer) SetDeadLetterQueue(dlq DeadLetterQueue) {
+	ms.deadLetters = dlq
+}
+
+func (ms *MetricsServer) deadLettersHandler(w http.ResponseWriter, r *http.Request) {
+	if ms.deadLetters == nil {
+		writeError(w, http.StatusNotFound, "retries are not enabled")
+		return
+	}
+
+	letters := ms.deadLetters.ListDeadLetters()
+	if letters == nil {
+		letters = []oracle.DeadLetter{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(letters)
+}
+
+func (ms *MetricsServer) requeueDeadLetterHandler(w http.ResponseWriter, r *http.Request) {
+	ms.releaseDeadLetter(w, r, DeadLetterQueue.RequeueDeadLetter, "requeued")
+}
+
+func (ms *MetricsServer) discardDeadLetterHandler(w http.ResponseWriter, r *http.Request) {
+	ms.releaseDeadLetter(w, r, DeadLetterQueue.DiscardDeadLetter, "discarded")
+}
+
+// releaseDeadLetter applies an operator action to a single dead-lettered job
+func (ms *MetricsServer) releaseDeadLetter(w http.ResponseWriter, r *http.Request, action func(DeadLetterQueue, string) error, result string) {
+	if ms.deadLetters == nil {
+		writeError(w, http.StatusNotFound, "retries are not enabled")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if _, ok := ms.deadLetters.GetDeadLetter(id); !ok {
+		writeError(w, http.StatusNotFound, "dead letter not found")
+		return
+	}
+
+	if err := action(ms.deadLetters, id); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"id": id, "status": result})
+}
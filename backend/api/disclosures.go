@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/obscura-network/obscura-node/oracle"
+)
+
+// DisclosureService queues selective-disclosure attestations and returns their results
+type DisclosureService interface {
+	RequestDisclosure(req oracle.DisclosureRequest) (string, error)
+	GetDisclosure(id string) (oracle.DisclosureResult, bool)
+}
+
+// SetDisclosureService enables the /api/disclosures endpoints. Both need the
+// operator token: requests reach private sources with vault credentials, and
+// whether a predicate held is itself private.
+func (ms *MetricsServer) SetDisclosureService(ds DisclosureService) {
+	ms.disclosures = ds
+}
+
+func (ms *MetricsServer) requestDisclosureHandler(w http.ResponseWriter, r *http.Request) {
+	if ms.disclosures == nil {
+		writeError(w, http.StatusNotFound, "selective disclosure is not enabled")
+		return
+	}
+
+	var req oracle.DisclosureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	id, err := ms.disclosures.RequestDisclosure(req)
+	if err != nil {
+		status := http.StatusServiceUnavailable
+		if errors.Is(err, oracle.ErrInvalidRequest) {
+			status = http.StatusBadRequest
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"id": id, "status": "queued"})
+}
+
+func (ms *MetricsServer) disclosureHandler(w http.ResponseWriter, r *http.Request) {
+	if ms.disclosures == nil {
+		writeError(w, http.StatusNotFound, "selective disclosure is not enabled")
+		return
+	}
+
+	// Pending and failed jobs have no result yet; their state is under /api/jobs/{id}
+	result, ok := ms.disclosures.GetDisclosure(mux.Vars(r)["id"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "no result for this disclosure yet")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
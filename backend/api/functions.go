@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/obscura-network/obscura-node/functions"
+)
+
+// FunctionRegistry stores the WASM modules compute jobs run
+type FunctionRegistry interface {
+	Register(ctx context.Context, code []byte, owner, signature string) (functions.Module, error)
+	Module(hash string) (functions.Module, bool)
+	List() []functions.Module
+	Delete(ctx context.Context, hash, signature string) error
+}
+
+// SetFunctionRegistry enables the /api/functions endpoints
+func (ms *MetricsServer) SetFunctionRegistry(fr FunctionRegistry) {
+	ms.functions = fr
+}
+
+// registerFunctionRequest carries a base64 module and its owner's signature
+// over functions.RegisterMessage
+type registerFunctionRequest struct {
+	Module    string `json:"module"`
+	Owner     string `json:"owner"`
+	Signature string `json:"signature"`
+}
+
+func (ms *MetricsServer) registerFunctionHandler(w http.ResponseWriter, r *http.Request) {
+	if ms.functions == nil {
+		writeError(w, http.StatusNotFound, "function registry is not enabled")
+		return
+	}
+
+	var req registerFunctionRequest
+	body := http.MaxBytesReader(w, r.Body, int64(base64.StdEncoding.EncodedLen(functions.MaxModuleSize))+4096)
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	code, err := base64.StdEncoding.DecodeString(req.Module)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "module must be base64")
+		return
+	}
+
+	module, err := ms.functions.Register(r.Context(), code, req.Owner, req.Signature)
+	if err != nil {
+		writeError(w, functionErrorStatus(err), err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(module)
+}
+
+func (ms *MetricsServer) functionsHandler(w http.ResponseWriter, r *http.Request) {
+	if ms.functions == nil {
+		writeError(w, http.StatusNotFound, "function registry is not enabled")
+		return
+	}
+
+	modules := ms.functions.List()
+	if modules == nil {
+		modules = []functions.Module{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(modules)
+}
+
+func (ms *MetricsServer) functionHandler(w http.ResponseWriter, r *http.Request) {
+	if ms.functions == nil {
+		writeError(w, http.StatusNotFound, "function registry is not enabled")
+		return
+	}
+
+	module, ok := ms.functions.Module(mux.Vars(r)["hash"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "function not found")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(module)
+}
+
+func (ms *MetricsServer) deleteFunctionHandler(w http.ResponseWriter, r *http.Request) {
+	if ms.functions == nil {
+		writeError(w, http.StatusNotFound, "function registry is not enabled")
+		return
+	}
+
+	var req struct {
+		Signature string `json:"signature"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	hash := mux.Vars(r)["hash"]
+	if err := ms.functions.Delete(r.Context(), hash, req.Signature); err != nil {
+		writeError(w, functionErrorStatus(err), err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"hash": hash, "status": "deleted"})
+}
+
+func functionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, functions.ErrModuleNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, functions.ErrNotOwner):
+		return http.StatusForbidden
+	case errors.Is(err, functions.ErrInvalidModule):
+		return http.StatusBadRequest
+	case errors.Is(err, functions.ErrQuotaExceeded):
+		return http.StatusTooManyRequests
+	}
+	return http.StatusServiceUnavailable
+}
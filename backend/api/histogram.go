@@ -0,0 +1,51 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// provingBuckets are the upper bounds, in seconds, of the proving time histogram
+var provingBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// histogram is a Prometheus-style histogram with cumulative buckets
+type histogram struct {
+	bounds []float64
+	counts []uint64 // per bucket, not cumulative; the last entry is +Inf
+	sum    float64
+	count  uint64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{bounds: bounds, counts: make([]uint64, len(bounds)+1)}
+}
+
+func (h *histogram) observe(v float64) {
+	i := sort.SearchFloat64s(h.bounds, v)
+	h.counts[i]++
+	h.sum += v
+	h.count++
+}
+
+// writeHistograms renders one labelled series per key in exposition format
+func writeHistograms(b *strings.Builder, name, label string, series map[string]*histogram) {
+	keys := make([]string, 0, len(series))
+	for k := range series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		h := series[k]
+		var cumulative uint64
+		for i, bound := range h.bounds {
+			cumulative += h.counts[i]
+			fmt.Fprintf(b, "%s_bucket{%s=%q,le=%q} %d\n", name, label, k, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(b, "%s_bucket{%s=%q,le=\"+Inf\"} %d\n", name, label, k, h.count)
+		fmt.Fprintf(b, "%s_sum{%s=%q} %g\n", name, label, k, h.sum)
+		fmt.Fprintf(b, "%s_count{%s=%q} %d\n", name, label, k, h.count)
+	}
+}
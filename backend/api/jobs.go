@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/obscura-network/obscura-node/oracle"
+)
+
+// defaultJobListLimit matches the size of the dashboard's recent job history
+const defaultJobListLimit = 50
+
+// JobHistory provides lifecycle records for the job status endpoints
+type JobHistory interface {
+	Get(jobID string) (oracle.JobStatus, bool)
+	List(filter oracle.JobFilter) []oracle.JobStatus
+}
+
+// SetJobHistory enables the lifecycle endpoints, /api/jobs/lifecycle and /api/jobs/{id}
+func (ms *MetricsServer) SetJobHistory(history JobHistory) {
+	ms.jobHistory = history
+}
+
+// jobsHandler serves the dashboard's recent job history. Its shape is what
+// the frontend and SDKs consume; lifecycle records live at /api/jobs/lifecycle.
+func (ms *MetricsServer) jobsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ms.collector.mu.RLock()
+	defer ms.collector.mu.RUnlock()
+	jobs := ms.collector.recentJobs
+	if jobs == nil {
+		jobs = []JobRecord{}
+	}
+	json.NewEncoder(w).Encode(jobs)
+}
+
+func (ms *MetricsServer) jobLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if ms.jobHistory == nil {
+		writeError(w, http.StatusNotFound, "job tracking is not enabled")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := oracle.JobFilter{
+		State:     oracle.JobState(query.Get("state")),
+		Type:      oracle.JobType(query.Get("type")),
+		Requester: query.Get("requester"),
+		Limit:     defaultJobListLimit,
+	}
+
+	if since := query.Get("since"); since != "" {
+		ts, err := strconv.ParseInt(since, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "since must be a unix timestamp")
+			return
+		}
+		filter.Since = time.Unix(ts, 0)
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		filter.Limit = n
+	}
+
+	jobs := ms.jobHistory.List(filter)
+	if jobs == nil {
+		jobs = []oracle.JobStatus{}
+	}
+	json.NewEncoder(w).Encode(jobs)
+}
+
+func (ms *MetricsServer) jobHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if ms.jobHistory == nil {
+		writeError(w, http.StatusNotFound, "job tracking is not enabled")
+		return
+	}
+
+	job, ok := ms.jobHistory.Get(mux.Vars(r)["id"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "job not found")
+		return
+	}
+	json.NewEncoder(w).Encode(job)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
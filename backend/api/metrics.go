@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -48,12 +50,20 @@ type MetricsCollector struct {
 	recentJobs            []JobRecord
 	proposals             []Proposal
 	totalStaked           uint64
+	jobsRejected          uint64
+	queueDepth            map[string]int // Scheduler lane -> waiting jobs
+	proverQueueDepth      int
+	proofCacheHits        map[string]uint64     // Circuit -> proofs served from the prover cache
+	provingTime           map[string]*histogram // Circuit -> proving duration
 }
 
 // NewMetricsCollector creates a new metrics collector
 func NewMetricsCollector() *MetricsCollector {
 	mc := &MetricsCollector{
-		uptime: time.Now(),
+		uptime:         time.Now(),
+		queueDepth:     make(map[string]int),
+		proofCacheHits: make(map[string]uint64),
+		provingTime:    make(map[string]*histogram),
 	}
 	mc.initStaticData()
 	return mc
@@ -125,6 +135,47 @@ func (mc *MetricsCollector) IncrementTotalStaked(amount uint64) {
 	mc.totalStaked += amount
 }
 
+// IncrementJobsRejected counts jobs shed because the scheduler queue was full
+func (mc *MetricsCollector) IncrementJobsRejected() {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.jobsRejected++
+}
+
+// SetQueueDepth records the number of jobs waiting in a scheduler lane
+func (mc *MetricsCollector) SetQueueDepth(lane string, depth int) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.queueDepth[lane] = depth
+}
+
+// ObserveProvingTime records how long a worker took to generate a proof
+func (mc *MetricsCollector) ObserveProvingTime(circuit string, d time.Duration) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	h, ok := mc.provingTime[circuit]
+	if !ok {
+		h = newHistogram(provingBuckets)
+		mc.provingTime[circuit] = h
+	}
+	h.observe(d.Seconds())
+	mc.proofsGenerated++
+}
+
+// IncrementProofCacheHits counts a proof served without proving again
+func (mc *MetricsCollector) IncrementProofCacheHits(circuit string) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.proofCacheHits[circuit]++
+}
+
+// SetProverQueueDepth records the number of proofs waiting for a prover worker
+func (mc *MetricsCollector) SetProverQueueDepth(depth int) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.proverQueueDepth = depth
+}
+
 // AddJobRecord adds a job to the recent history
 func (mc *MetricsCollector) AddJobRecord(job JobRecord) {
 	mc.mu.Lock()
@@ -141,6 +192,11 @@ func (mc *MetricsCollector) GetMetrics() map[string]interface{} {
 	mc.mu.RLock()
 	defer mc.mu.RUnlock()
 
+	queueDepth := make(map[string]int, len(mc.queueDepth))
+	for lane, depth := range mc.queueDepth {
+		queueDepth[lane] = depth
+	}
+
 	return map[string]interface{}{
 		"requests_processed":     mc.requestsProcessed,
 		"proofs_generated":       mc.proofsGenerated,
@@ -152,6 +208,9 @@ func (mc *MetricsCollector) GetMetrics() map[string]interface{} {
 		"uptime_seconds":         time.Since(mc.uptime).Seconds(),
 		"last_request_timestamp": mc.lastRequestTime.Unix(),
 		"total_staked":           mc.totalStaked,
+		"jobs_rejected":          mc.jobsRejected,
+		"queue_depth":            queueDepth,
+		"prover_queue_depth":     mc.proverQueueDepth,
 	}
 }
 
@@ -160,6 +219,29 @@ func (mc *MetricsCollector) GetPrometheusMetrics() string {
 	mc.mu.RLock()
 	defer mc.mu.RUnlock()
 
+	var queue strings.Builder
+	lanes := make([]string, 0, len(mc.queueDepth))
+	for lane := range mc.queueDepth {
+		lanes = append(lanes, lane)
+	}
+	sort.Strings(lanes)
+	for _, lane := range lanes {
+		fmt.Fprintf(&queue, "obscura_job_queue_depth{lane=%q} %d\n", lane, mc.queueDepth[lane])
+	}
+
+	var cacheHits strings.Builder
+	circuits := make([]string, 0, len(mc.proofCacheHits))
+	for circuit := range mc.proofCacheHits {
+		circuits = append(circuits, circuit)
+	}
+	sort.Strings(circuits)
+	for _, circuit := range circuits {
+		fmt.Fprintf(&cacheHits, "obscura_proof_cache_hits_total{circuit=%q} %d\n", circuit, mc.proofCacheHits[circuit])
+	}
+
+	var proving strings.Builder
+	writeHistograms(&proving, "obscura_proving_duration_seconds", "circuit", mc.provingTime)
+
 	return fmt.Sprintf(`# HELP obscura_requests_processed_total Total number of oracle requests processed
 # TYPE obscura_requests_processed_total counter
 obscura_requests_processed_total %d
@@ -184,6 +266,23 @@ obscura_aggregations_completed_total %d
 # TYPE obscura_outliers_detected_total counter
 obscura_outliers_detected_total %d
 
+# HELP obscura_jobs_rejected_total Total number of jobs shed because the queue was full
+# TYPE obscura_jobs_rejected_total counter
+obscura_jobs_rejected_total %d
+
+# HELP obscura_job_queue_depth Jobs waiting per scheduler lane
+# TYPE obscura_job_queue_depth gauge
+%s
+# HELP obscura_prover_queue_depth Proofs waiting for a prover worker
+# TYPE obscura_prover_queue_depth gauge
+obscura_prover_queue_depth %d
+
+# HELP obscura_proof_cache_hits_total Proofs served from the prover cache or shared with an identical queued witness
+# TYPE obscura_proof_cache_hits_total counter
+%s
+# HELP obscura_proving_duration_seconds Time a prover worker spent generating a proof
+# TYPE obscura_proving_duration_seconds histogram
+%s
 # HELP obscura_uptime_seconds Node uptime in seconds
 # TYPE obscura_uptime_seconds gauge
 obscura_uptime_seconds %d
@@ -194,6 +293,11 @@ obscura_uptime_seconds %d
 		mc.transactionsFailed,
 		mc.aggregationsCompleted,
 		mc.outliersDetected,
+		mc.jobsRejected,
+		queue.String(),
+		mc.proverQueueDepth,
+		cacheHits.String(),
+		proving.String(),
 		int64(time.Since(mc.uptime).Seconds()),
 	)
 }
@@ -204,6 +308,15 @@ type MetricsServer struct {
 	feedManager *oracle.FeedManager
 	router      *mux.Router
 	port        string
+	jobHistory  JobHistory
+	deadLetters DeadLetterQueue
+	disclosures DisclosureService
+	circuits    CircuitRegistry
+	functions   FunctionRegistry
+	consensus   ComputeConsensus
+	bridge      BridgeCosigner
+
+	operatorToken string
 }
 
 // NewMetricsServer creates a new metrics HTTP server
@@ -225,6 +338,21 @@ func (ms *MetricsServer) setupRoutes() {
 	ms.router.HandleFunc("/api/stats", ms.metricsHandler).Methods("GET", "OPTIONS")
 	ms.router.HandleFunc("/api/feeds", ms.feedsHandler).Methods("GET", "OPTIONS")
 	ms.router.HandleFunc("/api/jobs", ms.jobsHandler).Methods("GET", "OPTIONS")
+	ms.router.HandleFunc("/api/jobs/lifecycle", ms.jobLifecycleHandler).Methods("GET", "OPTIONS")
+	ms.router.HandleFunc("/api/jobs/{id}", ms.jobHandler).Methods("GET", "OPTIONS")
+	ms.router.HandleFunc("/api/dead-letters", ms.deadLettersHandler).Methods("GET", "OPTIONS")
+	ms.router.HandleFunc("/api/dead-letters/{id}/requeue", ms.operatorOnly(ms.requeueDeadLetterHandler)).Methods("POST")
+	ms.router.HandleFunc("/api/dead-letters/{id}", ms.operatorOnly(ms.discardDeadLetterHandler)).Methods("DELETE")
+	ms.router.HandleFunc("/api/disclosures", ms.operatorOnly(ms.requestDisclosureHandler)).Methods("POST")
+	ms.router.HandleFunc("/api/disclosures/{id}", ms.operatorOnly(ms.disclosureHandler)).Methods("GET")
+	ms.router.HandleFunc("/api/circuits", ms.circuitsHandler).Methods("GET", "OPTIONS")
+	ms.router.HandleFunc("/api/verify", ms.verifyHandler).Methods("POST", "OPTIONS")
+	ms.router.HandleFunc("/api/functions", ms.functionsHandler).Methods("GET", "OPTIONS")
+	ms.router.HandleFunc("/api/functions", ms.registerFunctionHandler).Methods("POST")
+	ms.router.HandleFunc("/api/functions/{hash}", ms.functionHandler).Methods("GET", "OPTIONS")
+	ms.router.HandleFunc("/api/functions/{hash}", ms.deleteFunctionHandler).Methods("DELETE")
+	ms.router.HandleFunc("/api/compute/consensus", ms.computeConsensusHandler).Methods("POST")
+	ms.router.HandleFunc("/api/bridge/cosign", ms.bridgeCosignHandler).Methods("POST")
 	ms.router.HandleFunc("/api/proposals", ms.proposalsHandler).Methods("GET", "OPTIONS")
 	ms.router.HandleFunc("/api/network", ms.networkHandler).Methods("GET", "OPTIONS")
 	ms.router.HandleFunc("/api/chains", ms.chainsHandler).Methods("GET", "OPTIONS")
@@ -280,17 +408,6 @@ func (ms *MetricsServer) feedsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (ms *MetricsServer) jobsHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	ms.collector.mu.RLock()
-	defer ms.collector.mu.RUnlock()
-	jobs := ms.collector.recentJobs
-	if jobs == nil {
-		jobs = []JobRecord{}
-	}
-	json.NewEncoder(w).Encode(jobs)
-}
-
 func (ms *MetricsServer) proposalsHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	ms.collector.mu.RLock()
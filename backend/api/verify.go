@@ -0,0 +1,25 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+func (ms *MetricsServer) verifyHandler(w http.ResponseWriter, r *http.Request) {
+	var req zkp.VerifyRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	// A proof that fails to verify is still a 200; only malformed requests are errors
+	result, err := req.Verify()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -31,6 +31,7 @@ type Condition struct {
 	LastTriggered time.Time             // When this trigger last fired
 	LastValue    float64                // Last known value (for deviation)
 	Active       bool
+	JobType      oracle.JobType         // Job dispatched when the trigger fires; data feed if empty
 }
 
 // DeviationConfig holds deviation trigger configuration
@@ -113,6 +114,20 @@ func (tm *TriggerManager) RegisterHeartbeatTrigger(feedID string, interval time.
 	})
 }
 
+// RegisterScheduledJob dispatches a job of the given type for id on every
+// interval, such as a reserve attestation or a TWAP update
+func (tm *TriggerManager) RegisterScheduledJob(jobType oracle.JobType, id string, interval time.Duration, target string) string {
+	return tm.RegisterTask(Condition{
+		Type:    TriggerTypeHeartbeat,
+		FeedID:  id,
+		Target:  target,
+		JobType: jobType,
+		Params: map[string]interface{}{
+			"interval_ms": interval.Milliseconds(),
+		},
+	})
+}
+
 // DeactivateTrigger disables a trigger without removing it
 func (tm *TriggerManager) DeactivateTrigger(id string) bool {
 	tm.mu.Lock()
@@ -310,9 +325,14 @@ func (tm *TriggerManager) dispatchJob(task *Condition, reason string, value floa
 		return
 	}
 
+	jobType := task.JobType
+	if jobType == "" {
+		jobType = oracle.JobTypeDataFeed
+	}
+
 	job := oracle.JobRequest{
 		ID:        fmt.Sprintf("auto-%s-%d", task.ID, time.Now().UnixNano()),
-		Type:      oracle.JobTypeDataFeed,
+		Type:      jobType,
 		Timestamp: time.Now(),
 		Params: map[string]interface{}{
 			"feed_id":        task.FeedID,
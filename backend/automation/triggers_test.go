@@ -128,3 +128,22 @@ func TestTriggerRemoval(t *testing.T) {
 
 	t.Log("✅ Trigger removal test passed")
 }
+
+func TestScheduledJobTrigger(t *testing.T) {
+	jobQueue := make(chan oracle.JobRequest, 10)
+	tm := NewTriggerManager(jobQueue)
+
+	assetID := "0x5553444300000000000000000000000000000000000000000000000000000000"
+	tm.RegisterScheduledJob(oracle.JobTypeProofOfReserves, assetID, 1*time.Hour, "0x9fE46736679d2D9a65F0992F2272dE9f3c7fa6e0")
+
+	triggers := tm.GetActiveTriggers()
+	if len(triggers) != 1 || triggers[0].JobType != oracle.JobTypeProofOfReserves {
+		t.Fatalf("Expected one proof-of-reserves trigger, got %+v", triggers)
+	}
+
+	tm.dispatchJob(&triggers[0], "heartbeat", 0)
+	job := <-jobQueue
+	if job.Type != oracle.JobTypeProofOfReserves || job.Params["feed_id"] != assetID {
+		t.Errorf("Expected a proof-of-reserves job for %s, got %s %v", assetID, job.Type, job.Params["feed_id"])
+	}
+}
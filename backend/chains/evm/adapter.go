@@ -1,6 +1,7 @@
 package evm
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"fmt"
@@ -30,10 +31,15 @@ type EVMAdapter struct {
 	privateKey   *ecdsa.PrivateKey
 	fromAddress  common.Address
 	oracleABI    abi.ABI
+	bridgeABI    abi.ABI
 	connected    bool
 	gasPricer    *GasPricer
 }
 
+// gasLimitMarginDivisor adds 1/5 of the estimate as headroom for state
+// changes between estimation and inclusion
+const gasLimitMarginDivisor = 5
+
 // OracleABI is the ABI for ObscuraOracle contract
 const OracleABI = `[
 	{
@@ -114,6 +120,35 @@ const OracleABI = `[
 	}
 ]`
 
+// BridgeABI is the ABI for the bridge endpoint contract on each chain
+const BridgeABI = `[
+	{
+		"name": "BridgeMessage",
+		"type": "event",
+		"inputs": [
+			{"name": "nonce", "type": "uint64", "indexed": true},
+			{"name": "targetChain", "type": "uint64", "indexed": true},
+			{"name": "sender", "type": "address", "indexed": true},
+			{"name": "receiver", "type": "address"},
+			{"name": "payload", "type": "bytes"}
+		]
+	},
+	{
+		"name": "receiveMessage",
+		"type": "function",
+		"inputs": [
+			{"name": "sourceChain", "type": "uint64"},
+			{"name": "nonce", "type": "uint64"},
+			{"name": "sender", "type": "address"},
+			{"name": "receiver", "type": "address"},
+			{"name": "payload", "type": "bytes"},
+			{"name": "batchRoot", "type": "uint256"},
+			{"name": "committeeRoot", "type": "uint256"},
+			{"name": "proof", "type": "uint256[8]"}
+		]
+	}
+]`
+
 // NewEVMAdapter creates a new EVM chain adapter
 func NewEVMAdapter(config *chains.ChainConfig, privateKeyHex string) (*EVMAdapter, error) {
 	var pk *ecdsa.PrivateKey
@@ -135,12 +170,17 @@ func NewEVMAdapter(config *chains.ChainConfig, privateKeyHex string) (*EVMAdapte
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse oracle ABI: %w", err)
 	}
+	parsedBridgeABI, err := abi.JSON(strings.NewReader(BridgeABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse bridge ABI: %w", err)
+	}
 
 	return &EVMAdapter{
 		config:      config,
 		privateKey:  pk,
 		fromAddress: fromAddress,
 		oracleABI:   parsedABI,
+		bridgeABI:   parsedBridgeABI,
 		gasPricer:   NewGasPricer(config.GasStrategy),
 	}, nil
 }
@@ -602,6 +642,212 @@ func (a *EVMAdapter) SubscribeVRFRequests(ctx context.Context, callback chains.V
 	return nil
 }
 
+// SubscribeBridgeMessages subscribes to BridgeMessage events on the bridge contract
+func (a *EVMAdapter) SubscribeBridgeMessages(ctx context.Context, callback chains.BridgeMessageCallback) error {
+	a.mu.RLock()
+	client := a.wsClient
+	if client == nil {
+		client = a.client
+	}
+	a.mu.RUnlock()
+
+	if client == nil {
+		return fmt.Errorf("not connected")
+	}
+	if a.config.BridgeContract == "" {
+		return fmt.Errorf("no bridge contract configured for %s", a.config.Name)
+	}
+
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{common.HexToAddress(a.config.BridgeContract)},
+		Topics:    [][]common.Hash{{a.bridgeABI.Events["BridgeMessage"].ID}},
+	}
+
+	logs := make(chan types.Log)
+	sub, err := client.SubscribeFilterLogs(ctx, query, logs)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-sub.Err():
+				log.Error().Err(err).Str("chain", a.config.Name).Msg("Bridge subscription error")
+				return
+			case vLog := <-logs:
+				msg, err := a.parseBridgeMessage(vLog)
+				if err != nil {
+					log.Warn().Err(err).Str("tx", vLog.TxHash.Hex()).Msg("Skipping malformed BridgeMessage event")
+					continue
+				}
+				callback(msg)
+			}
+		}
+	}()
+
+	log.Info().Str("chain", a.config.Name).Msg("Subscribed to bridge message events")
+	return nil
+}
+
+func (a *EVMAdapter) parseBridgeMessage(vLog types.Log) (*chains.BridgeMessage, error) {
+	if len(vLog.Topics) != 4 {
+		return nil, fmt.Errorf("expected 4 topics, got %d", len(vLog.Topics))
+	}
+	fields := make(map[string]interface{})
+	if err := a.bridgeABI.UnpackIntoMap(fields, "BridgeMessage", vLog.Data); err != nil {
+		return nil, err
+	}
+	receiver, _ := fields["receiver"].(common.Address)
+	payload, _ := fields["payload"].([]byte)
+
+	return &chains.BridgeMessage{
+		SourceChain: a.config.ChainID,
+		TargetChain: new(big.Int).SetBytes(vLog.Topics[2].Bytes()).Uint64(),
+		Nonce:       new(big.Int).SetBytes(vLog.Topics[1].Bytes()).Uint64(),
+		Sender:      common.BytesToAddress(vLog.Topics[3].Bytes()).Hex(),
+		Receiver:    receiver.Hex(),
+		Payload:     payload,
+		BlockNumber: vLog.BlockNumber,
+		TxHash:      vLog.TxHash.Hex(),
+	}, nil
+}
+
+// VerifyBridgeMessage checks msg against the BridgeMessage event in its
+// transaction's receipt and requires ConfirmationBlocks on top of it
+func (a *EVMAdapter) VerifyBridgeMessage(ctx context.Context, msg chains.BridgeMessage) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if !a.connected {
+		return fmt.Errorf("not connected to %s", a.config.Name)
+	}
+	if msg.SourceChain != a.config.ChainID {
+		return fmt.Errorf("message from chain %d checked against %s", msg.SourceChain, a.config.Name)
+	}
+
+	receipt, err := a.client.TransactionReceipt(ctx, common.HexToHash(msg.TxHash))
+	if err != nil {
+		return fmt.Errorf("failed to get receipt for %s: %w", msg.TxHash, err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("transaction %s reverted", msg.TxHash)
+	}
+	head, err := a.client.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get block number: %w", err)
+	}
+	if head < receipt.BlockNumber.Uint64()+a.config.ConfirmationBlocks {
+		return fmt.Errorf("transaction %s has fewer than %d confirmations", msg.TxHash, a.config.ConfirmationBlocks)
+	}
+
+	bridgeAddr := common.HexToAddress(a.config.BridgeContract)
+	eventID := a.bridgeABI.Events["BridgeMessage"].ID
+	for _, vLog := range receipt.Logs {
+		if vLog.Address != bridgeAddr || len(vLog.Topics) == 0 || vLog.Topics[0] != eventID {
+			continue
+		}
+		emitted, err := a.parseBridgeMessage(*vLog)
+		if err != nil || emitted.Nonce != msg.Nonce {
+			continue
+		}
+		if emitted.TargetChain != msg.TargetChain ||
+			common.HexToAddress(emitted.Sender) != common.HexToAddress(msg.Sender) ||
+			common.HexToAddress(emitted.Receiver) != common.HexToAddress(msg.Receiver) ||
+			!bytes.Equal(emitted.Payload, msg.Payload) {
+			return fmt.Errorf("message %d does not match the event emitted in %s", msg.Nonce, msg.TxHash)
+		}
+		return nil
+	}
+	return fmt.Errorf("no BridgeMessage with nonce %d in %s", msg.Nonce, msg.TxHash)
+}
+
+// DeliverBridgeMessage submits a proven message to this chain's bridge contract
+func (a *EVMAdapter) DeliverBridgeMessage(ctx context.Context, delivery chains.BridgeDelivery) (*chains.TransactionReceipt, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if !a.connected {
+		return nil, fmt.Errorf("not connected to %s", a.config.Name)
+	}
+	if a.config.BridgeContract == "" {
+		return nil, fmt.Errorf("no bridge contract configured for %s", a.config.Name)
+	}
+
+	msg := delivery.Message
+	data, err := a.bridgeABI.Pack("receiveMessage",
+		msg.SourceChain,
+		msg.Nonce,
+		common.HexToAddress(msg.Sender),
+		common.HexToAddress(msg.Receiver),
+		msg.Payload,
+		delivery.BatchRoot,
+		delivery.CommitteeRoot,
+		delivery.Proof,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack call data: %w", err)
+	}
+
+	nonce, err := a.client.PendingNonceAt(ctx, a.fromAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	gasPrice, err := a.gasPricer.GetGasPrice(ctx, a.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+
+	// Proof verification cost depends on the payload and the receiver's hook,
+	// so the limit is estimated; a revert here means delivery can never succeed
+	bridgeAddr := common.HexToAddress(a.config.BridgeContract)
+	gas, err := a.client.EstimateGas(ctx, ethereum.CallMsg{From: a.fromAddress, To: &bridgeAddr, Data: data})
+	if err != nil {
+		if strings.Contains(err.Error(), "execution reverted") {
+			return nil, fmt.Errorf("%w: %v", chains.ErrDeliveryReverted, err)
+		}
+		return nil, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: gasPrice,
+		Gas:      gas + gas/gasLimitMarginDivisor,
+		To:       &bridgeAddr,
+		Data:     data,
+	})
+
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(big.NewInt(int64(a.config.ChainID))), a.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := a.client.SendTransaction(ctx, signedTx); err != nil {
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	log.Info().
+		Str("chain", a.config.Name).
+		Str("txHash", signedTx.Hash().Hex()).
+		Uint64("sourceChain", msg.SourceChain).
+		Uint64("nonce", msg.Nonce).
+		Msg("Bridge message delivered")
+
+	receipt, err := bind.WaitMined(ctx, a.client, signedTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for confirmation: %w", err)
+	}
+
+	return &chains.TransactionReceipt{
+		TxHash:      receipt.TxHash.Hex(),
+		BlockNumber: receipt.BlockNumber.Uint64(),
+		GasUsed:     receipt.GasUsed,
+		Status:      receipt.Status == 1,
+	}, nil
+}
+
 // DeployContracts deploys a contract to the chain
 func (a *EVMAdapter) DeployContracts(ctx context.Context, bytecode []byte, constructorArgs []interface{}) (string, error) {
 	a.mu.RLock()
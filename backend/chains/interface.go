@@ -2,10 +2,15 @@ package chains
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"time"
 )
 
+// ErrDeliveryReverted means the target chain would reject a bridge delivery,
+// e.g. because the nonce was already delivered or the proof is invalid
+var ErrDeliveryReverted = errors.New("bridge delivery would revert")
+
 // RoundData represents oracle round data compatible with Chainlink interface
 type RoundData struct {
 	RoundID         uint64
@@ -44,6 +49,7 @@ type ChainConfig struct {
 	OracleContract    string
 	StakeGuardContract string
 	VerifierContract  string
+	BridgeContract    string
 	ConfirmationBlocks uint64
 	GasStrategy       GasStrategy
 	IsEnabled         bool
@@ -88,6 +94,13 @@ type ChainAdapter interface {
 	SubscribeOracleRequests(ctx context.Context, callback OracleRequestCallback) error
 	SubscribeVRFRequests(ctx context.Context, callback VRFRequestCallback) error
 	
+	// Bridge operations
+	SubscribeBridgeMessages(ctx context.Context, callback BridgeMessageCallback) error
+	DeliverBridgeMessage(ctx context.Context, delivery BridgeDelivery) (*TransactionReceipt, error)
+	// VerifyBridgeMessage checks that msg was emitted on this chain, unchanged
+	// and with enough confirmations, before a committee member signs it
+	VerifyBridgeMessage(ctx context.Context, msg BridgeMessage) error
+	
 	// Contract deployment (for admin operations)
 	DeployContracts(ctx context.Context, bytecode []byte, constructorArgs []interface{}) (string, error)
 }
@@ -140,6 +153,30 @@ type OracleRequestCallback func(request *OracleRequest)
 // VRFRequestCallback is called when a new VRF request is detected
 type VRFRequestCallback func(request *VRFRequest)
 
+// BridgeMessageCallback is called when a BridgeMessage event is observed on a source chain
+type BridgeMessageCallback func(msg *BridgeMessage)
+
+// BridgeMessage is a message emitted by a source chain's bridge contract.
+// Nonce is unique per source chain.
+type BridgeMessage struct {
+	SourceChain uint64
+	TargetChain uint64
+	Nonce       uint64
+	Sender      string
+	Receiver    string
+	Payload     []byte
+	BlockNumber uint64
+	TxHash      string
+}
+
+// BridgeDelivery carries a message and its inclusion proof to the target chain
+type BridgeDelivery struct {
+	Message       BridgeMessage
+	BatchRoot     *big.Int
+	CommitteeRoot *big.Int
+	Proof         [8]*big.Int
+}
+
 // OracleRequest represents an incoming oracle data request
 type OracleRequest struct {
 	RequestID       uint64
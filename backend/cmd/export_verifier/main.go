@@ -1,14 +1,82 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"log"
+	"os"
+	"slices"
+	"strings"
+
 	"github.com/obscura-network/obscura-node/zkp"
 )
 
 func main() {
-	err := zkp.ExportSolidityContract("../contracts/Verifier.sol")
+	keyDir := flag.String("keys", "./keys", "directory holding keys created by 'obscura keys generate'")
+	out := flag.String("out", "../contracts/Verifier.sol", "path of the exported verifier contract")
+	circuit := flag.String("circuit", "range", "circuit whose verifier is exported: "+strings.Join(zkp.CircuitNames(), ", "))
+	backend := flag.String("backend", string(zkp.BackendGroth16), "proving backend: groth16 or plonk")
+	srs := flag.String("srs", "", "universal KZG SRS file (required for plonk)")
+	name := flag.String("name", "", "contract name, e.g. TWAPVerifier (default: Verifier, or PlonkVerifier for plonk)")
+	fixture := flag.String("fixture", "", "also write a JSON fixture of a valid proof and its public inputs to this path")
+	flag.Parse()
+
+	if !slices.Contains(zkp.CircuitNames(), *circuit) {
+		log.Fatalf("Unknown circuit %q; expected one of %s", *circuit, strings.Join(zkp.CircuitNames(), ", "))
+	}
+	b, err := zkp.ParseBackend(*backend)
 	if err != nil {
+		log.Fatal(err)
+	}
+	if err := zkp.SetBackend(*circuit, b); err != nil {
+		log.Fatal(err)
+	}
+	if b == zkp.BackendPlonk {
+		if *srs == "" {
+			log.Fatal("-srs is required for the plonk backend")
+		}
+		if err := zkp.LoadPlonkSRS(*srs); err != nil {
+			log.Fatalf("Failed to load SRS: %v", err)
+		}
+	}
+
+	zkp.SetKeyDir(*keyDir)
+	if err := zkp.Init(); err != nil {
+		log.Fatalf("Failed to load ZK keys: %v", err)
+	}
+	var contract strings.Builder
+	if err := zkp.ExportVerifier(*circuit, &contract); err != nil {
+		log.Fatalf("Failed to export verifier: %v", err)
+	}
+	// Exporting an ephemeral key would produce a verifier that no node can satisfy after restart
+	if !zkp.PersistentKeys() {
+		log.Fatalf("No persisted keys in %s; run 'obscura keys generate --dir %s' first", *keyDir, *keyDir)
+	}
+
+	src := contract.String()
+	if *name != "" {
+		for _, exported := range []string{"contract Verifier {", "contract PlonkVerifier {"} {
+			src = strings.Replace(src, exported, "contract "+*name+" {", 1)
+		}
+	}
+	if err := os.WriteFile(*out, []byte(src), 0o644); err != nil {
 		log.Fatalf("Failed to export verifier: %v", err)
 	}
-	log.Println("Verifier.sol exported successfully to contracts folder.")
+	log.Printf("%s verifier for %s exported successfully to %s.", b, *circuit, *out)
+
+	if *fixture == "" {
+		return
+	}
+	f, err := zkp.GenerateFixture(*circuit)
+	if err != nil {
+		log.Fatalf("Failed to generate fixture: %v", err)
+	}
+	raw, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*fixture, append(raw, '\n'), 0o644); err != nil {
+		log.Fatalf("Failed to write fixture: %v", err)
+	}
+	log.Printf("Fixture with %d public inputs written to %s.", len(f.PublicInputs), *fixture)
 }
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/spf13/cobra"
+
+	"github.com/obscura-network/obscura-node/crosschain"
+)
+
+var bridgeCmd = &cobra.Command{
+	Use:   "bridge",
+	Short: "Manage bridge committee keys",
+}
+
+var bridgeKeygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "Generate a committee member key for bridge.committee_key",
+	Run: func(cmd *cobra.Command, args []string) {
+		key, err := eddsa.GenerateKey(rand.Reader)
+		if err != nil {
+			fmt.Printf("❌ Key generation failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("committee_key: %s\n", hex.EncodeToString(key.Bytes()))
+		fmt.Printf("public key:    %s\n", hex.EncodeToString(key.PublicKey.Bytes()))
+		fmt.Println("🔑 Keep committee_key secret; add the public key to every member's bridge.committee list.")
+	},
+}
+
+var bridgeRootCmd = &cobra.Command{
+	Use:   "root [public keys...]",
+	Short: "Print the committee root to deploy ObscuraBridge with",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		members := make([]eddsa.PublicKey, len(args))
+		for i, s := range args {
+			pub, err := crosschain.ParseMemberKey(s)
+			if err != nil {
+				fmt.Printf("❌ Member %d: %v\n", i, err)
+				os.Exit(1)
+			}
+			members[i] = pub
+		}
+		committee, err := crosschain.NewCommittee(members, nil)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(committee.CommitteeRoot().String())
+	},
+}
+
+func init() {
+	bridgeCmd.AddCommand(bridgeKeygenCmd)
+	bridgeCmd.AddCommand(bridgeRootCmd)
+	rootCmd.AddCommand(bridgeCmd)
+}
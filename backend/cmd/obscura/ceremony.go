@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/obscura-network/obscura-node/zkp"
+	"github.com/spf13/cobra"
+)
+
+var ceremonyCmd = &cobra.Command{
+	Use:   "ceremony",
+	Short: "Run a multi-party trusted setup for the Obscura circuits",
+	Long: `Coordinates a Groth16 MPC ceremony. The coordinator runs init, each
+participant runs contribute on a copy of the ceremony directory (offline is
+fine) and hands it to the next, and anyone can re-run verify on the result.
+The keys are secure as long as one contributor discarded their randomness.`,
+}
+
+var ceremonyInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Start a new ceremony (phase 1: powers of tau)",
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString("dir")
+		circuits, _ := cmd.Flags().GetStringSlice("circuits")
+
+		c, err := zkp.InitCeremony(dir, circuits)
+		exitOnError("Failed to start ceremony", err)
+
+		m := c.Manifest()
+		fmt.Printf("✅ Ceremony initialized in %s (domain size %d)\n", dir, m.DomainSize)
+		for _, circuit := range m.Circuits {
+			fmt.Printf(" - %-22s circuit %s\n", circuit.Name, circuit.CircuitHash[:12])
+		}
+	},
+}
+
+var ceremonyContributeCmd = &cobra.Command{
+	Use:   "contribute",
+	Short: "Add your randomness to the current phase",
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString("dir")
+		name, _ := cmd.Flags().GetString("name")
+
+		c, err := zkp.OpenCeremony(dir)
+		exitOnError("Failed to open ceremony", err)
+
+		fmt.Printf("🎲 Contributing to %s as %q...\n", c.Manifest().Phase, name)
+		records, err := c.Contribute(name)
+		exitOnError("Contribution failed", err)
+
+		fmt.Println("✅ Contribution recorded. Publish these hashes so others can check your attestation:")
+		for _, r := range records {
+			fmt.Printf(" - %s  %s\n", r.File, r.Hash)
+		}
+	},
+}
+
+var ceremonySealCmd = &cobra.Command{
+	Use:   "seal-phase1",
+	Short: "Close phase 1 with a public random beacon and start phase 2",
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString("dir")
+		beacon, _ := cmd.Flags().GetString("beacon")
+
+		c, err := zkp.OpenCeremony(dir)
+		exitOnError("Failed to open ceremony", err)
+		exitOnError("Failed to seal phase 1", c.SealPhase1([]byte(beacon)))
+
+		fmt.Printf("✅ Phase 1 sealed (SRS %s). Phase 2 contributions are open.\n", c.Manifest().SRSHash[:12])
+	},
+}
+
+var ceremonyVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Re-verify every contribution in the transcript",
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString("dir")
+
+		c, err := zkp.OpenCeremony(dir)
+		exitOnError("Failed to open ceremony", err)
+
+		fmt.Println("🔍 Verifying ceremony transcript...")
+		exitOnError("Transcript verification failed", c.Verify())
+
+		m := c.Manifest()
+		fmt.Printf("✅ Transcript valid: %d phase 1 contributions, phase %s\n", len(m.Phase1), m.Phase)
+		for _, circuit := range m.Circuits {
+			fmt.Printf(" - %-22s %d contributions\n", circuit.Name, len(circuit.Contributions))
+		}
+	},
+}
+
+var ceremonyFinalizeCmd = &cobra.Command{
+	Use:   "finalize",
+	Short: "Close phase 2 with a public random beacon and write the keys",
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString("dir")
+		beacon, _ := cmd.Flags().GetString("beacon")
+		keyDir, _ := cmd.Flags().GetString("keys")
+
+		c, err := zkp.OpenCeremony(dir)
+		exitOnError("Failed to open ceremony", err)
+
+		generated, err := c.Finalize([]byte(beacon), keyDir)
+		exitOnError("Failed to finalize ceremony", err)
+
+		for _, meta := range generated {
+			fmt.Printf(" - %-22s vk %s (%d contributions)\n", meta.Circuit, meta.VKHash[:12], meta.Contributions)
+		}
+		fmt.Printf("✅ Keys written to %s. Re-export and redeploy Verifier.sol.\n", keyDir)
+	},
+}
+
+func exitOnError(msg string, err error) {
+	if err != nil {
+		fmt.Printf("❌ %s: %v\n", msg, err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	ceremonyCmd.PersistentFlags().String("dir", "./ceremony", "ceremony transcript directory")
+	ceremonyInitCmd.Flags().StringSlice("circuits", nil, "circuits to include (default: all)")
+	ceremonyContributeCmd.Flags().String("name", "anonymous", "name recorded with your contribution")
+	ceremonySealCmd.Flags().String("beacon", "", "public random beacon value, published after the last contribution")
+	ceremonySealCmd.MarkFlagRequired("beacon")
+	ceremonyFinalizeCmd.Flags().String("beacon", "", "public random beacon value, published after the last contribution")
+	ceremonyFinalizeCmd.Flags().String("keys", "./keys", "directory to write the final keys to")
+	ceremonyFinalizeCmd.MarkFlagRequired("beacon")
+
+	ceremonyCmd.AddCommand(ceremonyInitCmd)
+	ceremonyCmd.AddCommand(ceremonyContributeCmd)
+	ceremonyCmd.AddCommand(ceremonySealCmd)
+	ceremonyCmd.AddCommand(ceremonyVerifyCmd)
+	ceremonyCmd.AddCommand(ceremonyFinalizeCmd)
+	rootCmd.AddCommand(ceremonyCmd)
+}
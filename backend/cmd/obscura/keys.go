@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/obscura-network/obscura-node/zkp"
+	"github.com/spf13/cobra"
+)
+
+var keysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage persisted Groth16 proving and verifying keys",
+}
+
+var keysGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Run the circuit setup once and write the keys to disk",
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString("dir")
+		force, _ := cmd.Flags().GetBool("force")
+
+		fmt.Printf("🔑 Generating ZK keys in %s...\n", dir)
+		generated, err := zkp.GenerateKeys(dir, force)
+		if err != nil {
+			fmt.Printf("❌ Key generation failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, meta := range generated {
+			fmt.Printf(" - %-22s v%d  circuit %s  vk %s\n", meta.Circuit, meta.CircuitVersion, meta.CircuitHash[:12], meta.VKHash[:12])
+		}
+		fmt.Println("✅ Keys written. Re-export and redeploy Verifier.sol before starting nodes with these keys.")
+	},
+}
+
+var keysVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check that the persisted range-proof key matches a deployed Verifier.sol",
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString("dir")
+		verifier, _ := cmd.Flags().GetString("verifier")
+
+		zkp.SetKeyDir(dir)
+		if err := zkp.Init(); err != nil {
+			fmt.Printf("❌ Failed to load keys: %v\n", err)
+			os.Exit(1)
+		}
+		if !zkp.PersistentKeys() {
+			fmt.Printf("❌ No persisted keys found in %s\n", dir)
+			os.Exit(1)
+		}
+		if err := zkp.VerifyAgainstSolidity("range", verifier); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Keys in %s match %s\n", dir, verifier)
+	},
+}
+
+func init() {
+	keysCmd.PersistentFlags().String("dir", "./keys", "key directory")
+	keysGenerateCmd.Flags().Bool("force", false, "overwrite existing keys (invalidates deployed verifiers)")
+	keysVerifyCmd.Flags().String("verifier", "../contracts/Verifier.sol", "deployed verifier contract source")
+
+	keysCmd.AddCommand(keysGenerateCmd)
+	keysCmd.AddCommand(keysVerifyCmd)
+	rootCmd.AddCommand(keysCmd)
+}
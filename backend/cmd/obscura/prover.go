@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/obscura-network/obscura-node/zkp"
+	"github.com/spf13/cobra"
+)
+
+var proverCmd = &cobra.Command{
+	Use:   "prover",
+	Short: "Run a remote prover worker that oracle nodes offload ZK proofs to",
+	Long: `Runs a prover worker serving the remote prover protocol. The worker must use
+the same persisted keys and per-circuit backends as the nodes it serves; nodes
+verify every proof it returns and prove locally when it is unavailable.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		listen, _ := cmd.Flags().GetString("listen")
+		dir, _ := cmd.Flags().GetString("dir")
+		workers, _ := cmd.Flags().GetInt("workers")
+		backends, _ := cmd.Flags().GetStringToString("backend")
+		srs, _ := cmd.Flags().GetString("srs")
+		certFile, _ := cmd.Flags().GetString("tls-cert")
+		keyFile, _ := cmd.Flags().GetString("tls-key")
+		token := os.Getenv("OBSCURA_PROVER_TOKEN")
+
+		if err := initProverKeys(dir, backends, srs); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		if token == "" {
+			fmt.Println("⚠️  OBSCURA_PROVER_TOKEN is not set; any client can submit witnesses")
+		}
+
+		cfg := zkp.DefaultProverConfig()
+		if workers > 0 {
+			cfg.Workers = workers
+		}
+		prover := zkp.NewProverService(cfg)
+
+		ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer cancel()
+		go prover.Start(ctx)
+
+		server := &http.Server{Addr: listen, Handler: zkp.ProverHandler(prover, token)}
+		go func() {
+			<-ctx.Done()
+			fmt.Println("\n🛑 Shutting down prover...")
+			shutdownCtx, done := context.WithTimeout(context.Background(), 30*time.Second)
+			defer done()
+			server.Shutdown(shutdownCtx)
+		}()
+
+		fmt.Printf("✅ Prover worker listening on %s with %d workers\n", listen, cfg.Workers)
+		var err error
+		if certFile != "" {
+			err = server.ListenAndServeTLS(certFile, keyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("❌ Server error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// initProverKeys loads every circuit from dir with the given backends and
+// refuses ephemeral keys, whose proofs no node would accept
+func initProverKeys(dir string, backends map[string]string, srs string) error {
+	zkp.SetKeyDir(dir)
+	usesPlonk := false
+	for circuit, name := range backends {
+		backend, err := zkp.ParseBackend(name)
+		if err != nil {
+			return fmt.Errorf("--backend %s: %w", circuit, err)
+		}
+		if err := zkp.SetBackend(circuit, backend); err != nil {
+			return fmt.Errorf("--backend %s: %w", circuit, err)
+		}
+		usesPlonk = usesPlonk || backend == zkp.BackendPlonk
+	}
+	if usesPlonk {
+		if srs == "" {
+			return fmt.Errorf("--srs is required when a circuit uses the plonk backend")
+		}
+		if err := zkp.LoadPlonkSRS(srs); err != nil {
+			return err
+		}
+	}
+
+	if err := zkp.Init(); err != nil {
+		return fmt.Errorf("failed to load keys: %w", err)
+	}
+	if err := zkp.InitAdvancedCircuits(); err != nil {
+		return fmt.Errorf("failed to load keys: %w", err)
+	}
+	if !zkp.PersistentKeys() {
+		return fmt.Errorf("no persisted keys for every circuit in %s; copy the nodes' keys (%s)", dir, strings.Join(zkp.CircuitNames(), ", "))
+	}
+	return nil
+}
+
+func init() {
+	proverCmd.Flags().String("listen", ":9090", "address to serve the prover protocol on")
+	proverCmd.Flags().String("dir", "./keys", "key directory, identical to the nodes'")
+	proverCmd.Flags().Int("workers", 0, "concurrent proofs (default: number of CPU cores)")
+	proverCmd.Flags().StringToString("backend", nil, "per-circuit backend matching the nodes' zkp_backends, e.g. twap=plonk")
+	proverCmd.Flags().String("srs", "", "universal KZG SRS, required when a circuit uses plonk")
+	proverCmd.Flags().String("tls-cert", "", "TLS certificate; witnesses carry private inputs, so serve TLS outside trusted networks")
+	proverCmd.Flags().String("tls-key", "", "TLS private key")
+
+	rootCmd.AddCommand(proverCmd)
+}
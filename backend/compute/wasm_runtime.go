@@ -4,52 +4,36 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/tetratelabs/wazero"
-	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/obscura-network/obscura-node/functions"
 )
 
 // WasmRuntime handles the execution of serverless functions in a WASM sandbox.
+// Calls share the limits of functions.DefaultSandbox.
 type WasmRuntime struct {
-	runtime wazero.Runtime
+	compute *functions.ComputeManager
 }
 
 func NewWasmRuntime() *WasmRuntime {
-	ctx := context.Background()
-	r := wazero.NewRuntime(ctx)
-	
-	// Instantiate WASI
-	wasi_snapshot_preview1.MustInstantiate(ctx, r)
+	cm, err := functions.NewComputeManager(context.Background(), "", functions.DefaultSandbox())
+	if err != nil {
+		panic(err)
+	}
 	
 	return &WasmRuntime{
-		runtime: r,
+		compute: cm,
 	}
 }
 
-// ExecuteComputeFunc runs a WASM binary or simulates execution if buffer is empty.
-func (r *WasmRuntime) ExecuteComputeFunc(wasmBuffer []byte) (string, error) {
+// ExecuteComputeFunc runs a WASM binary's "run" function. The call ends when
+// ctx is done or the sandbox's time budget runs out, whichever comes first.
+func (r *WasmRuntime) ExecuteComputeFunc(ctx context.Context, wasmBuffer []byte) (string, error) {
 	if len(wasmBuffer) == 0 {
-		// Mock execution for demo/orchestration
-		return "42.0", nil
-	}
-
-	ctx := context.Background()
-	
-	// Instantiate the module
-	mod, err := r.runtime.Instantiate(ctx, wasmBuffer)
-	if err != nil {
-		return "", fmt.Errorf("failed to instantiate module: %v", err)
-	}
-	defer mod.Close(ctx)
-
-	// Call the "run" function
-	runFunc := mod.ExportedFunction("run")
-	if runFunc == nil {
-		return "", fmt.Errorf("module does not export 'run' function")
+		return "", fmt.Errorf("empty wasm module")
 	}
 
-	results, err := runFunc.Call(ctx)
+	results, err := r.compute.ExecuteWasm(ctx, wasmBuffer, "run", nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to call run function: %v", err)
+		return "", fmt.Errorf("failed to call run function: %w", err)
 	}
 	
 	if len(results) > 0 {
@@ -0,0 +1,378 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog/log"
+)
+
+// Compute round phases
+const (
+	PhaseCommit = "commit"
+	PhaseReveal = "reveal"
+)
+
+// SaltSize is the length of the salt every reveal must carry
+const SaltSize = 32
+
+var (
+	// ErrNoQuorum is returned when fewer than Threshold nodes commit in time
+	ErrNoQuorum = errors.New("compute commitments did not reach quorum")
+	// ErrInvalidMessage marks a compute message with a bad signature or from
+	// a node outside the committee
+	ErrInvalidMessage = errors.New("invalid compute message")
+)
+
+// ComputeMessage is one node's commitment to, or reveal of, its output for a
+// compute job. The commitment is sha256 over the length-prefixed job ID,
+// output and salt, so outputs stay hidden until a quorum has committed and a
+// reveal cannot move bytes between output and salt.
+type ComputeMessage struct {
+	Phase      string `json:"phase"`
+	JobID      string `json:"job_id"`
+	NodeID     string `json:"node_id"`
+	Commitment []byte `json:"commitment,omitempty"`
+	Output     []byte `json:"output,omitempty"`
+	Salt       []byte `json:"salt,omitempty"`
+	Signature  []byte `json:"signature"`
+}
+
+// ComputeTransport delivers compute messages to the other committee members
+type ComputeTransport interface {
+	Broadcast(ctx context.Context, msg ComputeMessage) error
+}
+
+// ComputeOutcome is the tally of a compute round. Output is set only when at
+// least Threshold nodes revealed the same output.
+type ComputeOutcome struct {
+	Output    []byte
+	Agreeing  []string
+	Divergent []string // revealed another output, or a reveal not matching the commitment
+	Missing   []string // committed but did not reveal in time
+}
+
+// computeRound collects the messages of one job. Messages from faster peers
+// may arrive before the local node starts the round; a round it never starts
+// is dropped after MaxRoundAge.
+type computeRound struct {
+	mu        sync.Mutex
+	commits   map[string][]byte
+	reveals   map[string]ComputeMessage
+	revealing bool
+	updated   chan struct{}
+
+	// Guarded by the manager's mutex
+	created time.Time
+	started bool
+}
+
+func newComputeRound() *computeRound {
+	return &computeRound{
+		commits: make(map[string][]byte),
+		reveals: make(map[string]ComputeMessage),
+		updated: make(chan struct{}, 1),
+		created: time.Now(),
+	}
+}
+
+// add records msg, or reports false for a commitment arriving once the
+// local node has revealed: it could copy an output already revealed
+func (r *computeRound) add(msg ComputeMessage) bool {
+	r.mu.Lock()
+	switch msg.Phase {
+	case PhaseCommit:
+		if r.revealing {
+			r.mu.Unlock()
+			return false
+		}
+		if _, ok := r.commits[msg.NodeID]; !ok {
+			r.commits[msg.NodeID] = msg.Commitment
+		}
+	case PhaseReveal:
+		if _, ok := r.reveals[msg.NodeID]; !ok {
+			r.reveals[msg.NodeID] = msg
+		}
+	}
+	r.mu.Unlock()
+
+	select {
+	case r.updated <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// startReveal closes the commit phase
+func (r *computeRound) startReveal() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.revealing = true
+}
+
+// revealed counts the committed nodes that have revealed
+func (r *computeRound) revealed() int {
+	n := 0
+	for nodeID := range r.commits {
+		if _, ok := r.reveals[nodeID]; ok {
+			n++
+		}
+	}
+	return n
+}
+
+// wait blocks until done holds or ctx ends
+func (r *computeRound) wait(ctx context.Context, done func() bool) bool {
+	for {
+		r.mu.Lock()
+		ok := done()
+		r.mu.Unlock()
+		if ok {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-r.updated:
+		}
+	}
+}
+
+// SetComputeTransport enables compute rounds with the registered nodes
+func (m *OCRManager) SetComputeTransport(t ComputeTransport) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transport = t
+}
+
+// LocalNodeID returns the address the node signs compute messages with
+func (m *OCRManager) LocalNodeID() string {
+	return m.localNode.ID
+}
+
+// computeRound returns the round of jobID, marking it started when the local
+// node runs it. Rounds never started, including those of finished jobs that
+// late messages would recreate, expire after MaxRoundAge.
+func (m *OCRManager) computeRound(jobID string, start bool) *computeRound {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.computeRounds == nil {
+		m.computeRounds = make(map[string]*computeRound)
+	}
+	for id, round := range m.computeRounds {
+		if !round.started && time.Since(round.created) > m.config.MaxRoundAge {
+			delete(m.computeRounds, id)
+		}
+	}
+	round, ok := m.computeRounds[jobID]
+	if !ok {
+		round = newComputeRound()
+		m.computeRounds[jobID] = round
+	}
+	round.started = round.started || start
+	return round
+}
+
+// committee lists the local node and every active registered node
+func (m *OCRManager) committee() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	members := []string{m.localNode.ID}
+	for id, node := range m.nodes {
+		if node.IsActive && id != m.localNode.ID {
+			members = append(members, id)
+		}
+	}
+	sort.Strings(members)
+	return members
+}
+
+func (m *OCRManager) isMember(nodeID string) bool {
+	if strings.EqualFold(nodeID, m.localNode.ID) {
+		return true
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	node, ok := m.nodes[common.HexToAddress(nodeID).Hex()]
+	return ok && node.IsActive
+}
+
+// HandleComputeMessage accepts a commit or reveal from another node
+func (m *OCRManager) HandleComputeMessage(msg ComputeMessage) error {
+	if msg.JobID == "" || (msg.Phase != PhaseCommit && msg.Phase != PhaseReveal) {
+		return fmt.Errorf("%w: unknown phase %q", ErrInvalidMessage, msg.Phase)
+	}
+	if msg.Phase == PhaseReveal && len(msg.Salt) != SaltSize {
+		return fmt.Errorf("%w: salt of %d bytes, want %d", ErrInvalidMessage, len(msg.Salt), SaltSize)
+	}
+	if !common.IsHexAddress(msg.NodeID) || !m.isMember(msg.NodeID) {
+		return fmt.Errorf("%w: %s is not in the committee", ErrInvalidMessage, msg.NodeID)
+	}
+	pub, err := crypto.SigToPub(hashComputeMessage(msg), msg.Signature)
+	if err != nil || crypto.PubkeyToAddress(*pub) != common.HexToAddress(msg.NodeID) {
+		return fmt.Errorf("%w: signature is not from %s", ErrInvalidMessage, msg.NodeID)
+	}
+	msg.NodeID = common.HexToAddress(msg.NodeID).Hex()
+	if !m.computeRound(msg.JobID, false).add(msg) {
+		return fmt.Errorf("%w: commitment from %s arrived after the reveal phase", ErrInvalidMessage, msg.NodeID)
+	}
+	return nil
+}
+
+// RunComputeRound commits to output, waits for every member to commit or for
+// a quorum once half of timeout has passed, reveals, and tallies the reveals
+// received before timeout. Commitments arriving after the reveal are
+// rejected. The round is forgotten afterwards, so a retried job starts a
+// fresh one.
+func (m *OCRManager) RunComputeRound(ctx context.Context, jobID string, output []byte, timeout time.Duration) (ComputeOutcome, error) {
+	m.mu.RLock()
+	transport := m.transport
+	m.mu.RUnlock()
+	if transport == nil {
+		return ComputeOutcome{}, fmt.Errorf("no compute transport configured")
+	}
+
+	round := m.computeRound(jobID, true)
+	defer func() {
+		m.mu.Lock()
+		delete(m.computeRounds, jobID)
+		m.mu.Unlock()
+	}()
+
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return ComputeOutcome{}, err
+	}
+	commit, err := m.signComputeMessage(ComputeMessage{
+		Phase:      PhaseCommit,
+		JobID:      jobID,
+		Commitment: computeCommitment(jobID, output, salt),
+	})
+	if err != nil {
+		return ComputeOutcome{}, err
+	}
+	round.add(commit)
+	if err := transport.Broadcast(ctx, commit); err != nil {
+		log.Warn().Err(err).Str("job_id", jobID).Msg("Failed to broadcast compute commitment")
+	}
+
+	roundCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	threshold := m.config.Threshold
+	members := len(m.committee())
+	commitCtx, cancelCommit := context.WithTimeout(roundCtx, timeout/2)
+	round.wait(commitCtx, func() bool { return len(round.commits) >= members })
+	cancelCommit()
+	if !round.wait(roundCtx, func() bool { return len(round.commits) >= threshold }) {
+		round.mu.Lock()
+		committed := len(round.commits)
+		round.mu.Unlock()
+		return ComputeOutcome{}, fmt.Errorf("%w: %d of %d commitments", ErrNoQuorum, committed, threshold)
+	}
+	round.startReveal()
+
+	reveal, err := m.signComputeMessage(ComputeMessage{Phase: PhaseReveal, JobID: jobID, Output: output, Salt: salt})
+	if err != nil {
+		return ComputeOutcome{}, err
+	}
+	round.add(reveal)
+	if err := transport.Broadcast(ctx, reveal); err != nil {
+		log.Warn().Err(err).Str("job_id", jobID).Msg("Failed to broadcast compute reveal")
+	}
+
+	// Give every node that committed until the deadline to reveal
+	round.wait(roundCtx, func() bool { return round.revealed() >= len(round.commits) })
+	if ctx.Err() != nil {
+		return ComputeOutcome{}, ctx.Err()
+	}
+
+	round.mu.Lock()
+	defer round.mu.Unlock()
+	return tallyCompute(jobID, round, threshold), nil
+}
+
+// tallyCompute groups valid reveals by output and picks the largest group
+func tallyCompute(jobID string, round *computeRound, threshold int) ComputeOutcome {
+	groups := make(map[string][]string)
+	var outcome ComputeOutcome
+	for nodeID, commitment := range round.commits {
+		reveal, ok := round.reveals[nodeID]
+		switch {
+		case !ok:
+			outcome.Missing = append(outcome.Missing, nodeID)
+		case !bytes.Equal(computeCommitment(jobID, reveal.Output, reveal.Salt), commitment):
+			outcome.Divergent = append(outcome.Divergent, nodeID)
+		default:
+			groups[string(reveal.Output)] = append(groups[string(reveal.Output)], nodeID)
+		}
+	}
+
+	var best string
+	for output, nodes := range groups {
+		if len(nodes) > len(groups[best]) || (len(nodes) == len(groups[best]) && output < best) {
+			best = output
+		}
+	}
+	for output, nodes := range groups {
+		if output == best && len(nodes) >= threshold {
+			outcome.Output = []byte(output)
+			outcome.Agreeing = append(outcome.Agreeing, nodes...)
+		} else {
+			outcome.Divergent = append(outcome.Divergent, nodes...)
+		}
+	}
+	sort.Strings(outcome.Agreeing)
+	sort.Strings(outcome.Divergent)
+	sort.Strings(outcome.Missing)
+	return outcome
+}
+
+// computeCommitment hashes each field behind its length, so no two splits of
+// the same bytes give the same commitment
+func computeCommitment(jobID string, output, salt []byte) []byte {
+	h := sha256.New()
+	for _, field := range [][]byte{[]byte(jobID), output, salt} {
+		var size [8]byte
+		binary.BigEndian.PutUint64(size[:], uint64(len(field)))
+		h.Write(size[:])
+		h.Write(field)
+	}
+	return h.Sum(nil)
+}
+
+// signComputeMessage signs msg with the local node's key
+func (m *OCRManager) signComputeMessage(msg ComputeMessage) (ComputeMessage, error) {
+	msg.NodeID = m.localNode.ID
+	sig, err := crypto.Sign(hashComputeMessage(msg), m.localNode.PrivateKey)
+	if err != nil {
+		return ComputeMessage{}, fmt.Errorf("failed to sign compute %s: %w", msg.Phase, err)
+	}
+	msg.Signature = sig
+	return msg, nil
+}
+
+// hashComputeMessage creates a hash of a compute message for signing
+func hashComputeMessage(msg ComputeMessage) []byte {
+	data := fmt.Sprintf("%s:%s:%s:%x:%x:%x",
+		msg.Phase,
+		msg.JobID,
+		common.HexToAddress(msg.NodeID).Hex(),
+		msg.Commitment,
+		msg.Output,
+		msg.Salt,
+	)
+	hash := sha256.Sum256([]byte(data))
+	return hash[:]
+}
@@ -0,0 +1,219 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// memoryTransport delivers messages straight to the other managers
+type memoryTransport struct {
+	self  string
+	peers map[string]*OCRManager
+}
+
+func (t *memoryTransport) Broadcast(ctx context.Context, msg ComputeMessage) error {
+	for id, peer := range t.peers {
+		if id != t.self {
+			if err := peer.HandleComputeMessage(msg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func newCommittee(t *testing.T, size, threshold int) []*OCRManager {
+	t.Helper()
+	managers := make([]*OCRManager, size)
+	peers := make(map[string]*OCRManager)
+	for i := range managers {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg := DefaultOCRConfig()
+		cfg.Threshold = threshold
+		managers[i], err = NewOCRManager(cfg, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		peers[managers[i].LocalNodeID()] = managers[i]
+	}
+	for _, m := range managers {
+		for _, other := range managers {
+			if other != m {
+				m.RegisterNode(&OCRNode{ID: other.LocalNodeID(), IsActive: true})
+			}
+		}
+		m.SetComputeTransport(&memoryTransport{self: m.LocalNodeID(), peers: peers})
+	}
+	return managers
+}
+
+func runCommittee(t *testing.T, managers []*OCRManager, jobID string, outputs [][]byte) []ComputeOutcome {
+	t.Helper()
+	outcomes := make([]ComputeOutcome, len(managers))
+	errs := make([]error, len(managers))
+	var wg sync.WaitGroup
+	for i, m := range managers {
+		wg.Add(1)
+		go func(i int, m *OCRManager) {
+			defer wg.Done()
+			outcomes[i], errs[i] = m.RunComputeRound(context.Background(), jobID, outputs[i], 2*time.Second)
+		}(i, m)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("node %d: %v", i, err)
+		}
+	}
+	return outcomes
+}
+
+func TestComputeRoundFlagsDivergentNode(t *testing.T) {
+	managers := newCommittee(t, 3, 2)
+	outcomes := runCommittee(t, managers, "job-1", [][]byte{{7}, {7}, {9}})
+
+	for i, outcome := range outcomes {
+		if string(outcome.Output) != string([]byte{7}) {
+			t.Fatalf("node %d: expected agreed output 7, got %v", i, outcome.Output)
+		}
+		if len(outcome.Agreeing) != 2 || len(outcome.Divergent) != 1 || len(outcome.Missing) != 0 {
+			t.Fatalf("node %d: unexpected tally %+v", i, outcome)
+		}
+		if outcome.Divergent[0] != managers[2].LocalNodeID() {
+			t.Fatalf("node %d: expected %s to diverge, got %s", i, managers[2].LocalNodeID(), outcome.Divergent[0])
+		}
+	}
+}
+
+func TestComputeRoundWithoutAgreement(t *testing.T) {
+	managers := newCommittee(t, 3, 2)
+	outcomes := runCommittee(t, managers, "job-2", [][]byte{{1}, {2}, {3}})
+
+	for i, outcome := range outcomes {
+		if outcome.Output != nil {
+			t.Fatalf("node %d: expected no agreement, got %v", i, outcome.Output)
+		}
+		if len(outcome.Divergent) != 3 {
+			t.Fatalf("node %d: expected every node to diverge, got %+v", i, outcome)
+		}
+	}
+}
+
+func TestComputeRoundNeedsQuorum(t *testing.T) {
+	managers := newCommittee(t, 3, 2)
+	_, err := managers[0].RunComputeRound(context.Background(), "job-3", []byte{1}, 100*time.Millisecond)
+	if !errors.Is(err, ErrNoQuorum) {
+		t.Fatalf("expected ErrNoQuorum, got %v", err)
+	}
+}
+
+func TestComputeMessageRejectsOutsiders(t *testing.T) {
+	managers := newCommittee(t, 2, 2)
+
+	outsider, err := NewOCRManager(DefaultOCRConfig(), mustKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := outsider.signComputeMessage(ComputeMessage{Phase: PhaseCommit, JobID: "job-4", Commitment: []byte{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := managers[0].HandleComputeMessage(msg); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("expected outsider to be rejected, got %v", err)
+	}
+
+	// A member's message signed by another key is forged
+	msg.NodeID = managers[1].LocalNodeID()
+	if err := managers[0].HandleComputeMessage(msg); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("expected forged message to be rejected, got %v", err)
+	}
+}
+
+func TestComputeMessageRejectsLateCommitment(t *testing.T) {
+	managers := newCommittee(t, 3, 2)
+	commit := func(m *OCRManager) ComputeMessage {
+		msg, err := m.signComputeMessage(ComputeMessage{Phase: PhaseCommit, JobID: "job-5", Commitment: []byte{1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return msg
+	}
+
+	round := managers[0].computeRound("job-5", true)
+	if err := managers[0].HandleComputeMessage(commit(managers[1])); err != nil {
+		t.Fatalf("expected a commitment before the reveal to be accepted, got %v", err)
+	}
+	round.startReveal()
+	if err := managers[0].HandleComputeMessage(commit(managers[2])); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("expected a commitment after the reveal to be rejected, got %v", err)
+	}
+	if len(round.commits) != 1 {
+		t.Errorf("expected only the early commitment, got %d", len(round.commits))
+	}
+}
+
+func TestComputeRoundsNeverStartedExpire(t *testing.T) {
+	managers := newCommittee(t, 2, 2)
+	managers[0].config.MaxRoundAge = 10 * time.Millisecond
+
+	msg, err := managers[1].signComputeMessage(ComputeMessage{Phase: PhaseCommit, JobID: "job-6", Commitment: []byte{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := managers[0].HandleComputeMessage(msg); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	msg, err = managers[1].signComputeMessage(ComputeMessage{Phase: PhaseCommit, JobID: "job-7", Commitment: []byte{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := managers[0].HandleComputeMessage(msg); err != nil {
+		t.Fatal(err)
+	}
+	managers[0].mu.RLock()
+	defer managers[0].mu.RUnlock()
+	if _, ok := managers[0].computeRounds["job-6"]; ok || len(managers[0].computeRounds) != 1 {
+		t.Errorf("expected only the fresh round to be kept, got %d rounds", len(managers[0].computeRounds))
+	}
+}
+
+func TestComputeRevealCannotResplitCommitment(t *testing.T) {
+	managers := newCommittee(t, 2, 2)
+	salt := make([]byte, SaltSize)
+	commitment := computeCommitment("job-8", []byte{7}, salt)
+
+	// Moving the output's last byte into the salt keeps the bytes but not the
+	// commitment
+	if bytes.Equal(commitment, computeCommitment("job-8", nil, append([]byte{7}, salt...))) {
+		t.Fatal("expected a re-split reveal to miss the commitment")
+	}
+
+	reveal, err := managers[1].signComputeMessage(ComputeMessage{Phase: PhaseReveal, JobID: "job-8", Output: nil, Salt: append([]byte{7}, salt...)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := managers[0].HandleComputeMessage(reveal); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("expected a reveal with a %d-byte salt to be rejected, got %v", SaltSize+1, err)
+	}
+}
+
+func mustKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
@@ -107,6 +107,10 @@ type OCRManager struct {
 	
 	// VRF for leader election
 	vrfGen func(seed []byte) (*big.Int, []byte, error)
+
+	// Compute rounds compare WASM outputs across nodes before fulfillment
+	transport     ComputeTransport
+	computeRounds map[string]*computeRound // jobID -> round
 }
 
 // NewOCRManager creates a new OCR manager
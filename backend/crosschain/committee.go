@@ -0,0 +1,274 @@
+package crosschain
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/rs/zerolog/log"
+
+	"github.com/obscura-network/obscura-node/chains"
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+// CosignPath is where committee members ask each other to sign a batch
+const CosignPath = "/api/bridge/cosign"
+
+// BatchSigner collects a quorum of committee signatures over a batch root
+type BatchSigner interface {
+	CommitteeRoot() *big.Int
+	SignBatch(ctx context.Context, root *big.Int, batch []chains.BridgeMessage) ([]zkp.BridgeSignature, error)
+	// Cosign signs root with the member keys held by this process
+	Cosign(root *big.Int) ([]MemberSignature, error)
+}
+
+// MemberSignature is one member's EdDSA signature over a batch root
+type MemberSignature struct {
+	Index     int    `json:"index"`
+	Signature []byte `json:"signature"`
+}
+
+// CosignRequest asks a committee member to sign the batch built from Messages
+type CosignRequest struct {
+	Messages []chains.BridgeMessage `json:"messages"`
+}
+
+// CosignResponse carries the member signatures a peer contributed
+type CosignResponse struct {
+	Signatures []MemberSignature `json:"signatures"`
+}
+
+// Committee is the bridge signing committee. Every member's public key is a
+// leaf of the committee tree whose root target chains trust; members whose
+// private keys are held locally sign directly and the rest are asked through
+// their node's cosign endpoint.
+type Committee struct {
+	members []eddsa.PublicKey
+	local   map[int]*eddsa.PrivateKey
+	tree    *zkp.MerkleTree
+	peers   []string
+	client  *http.Client
+}
+
+// NewCommittee builds the committee tree. local maps member indices to the
+// private keys this process holds.
+func NewCommittee(members []eddsa.PublicKey, local map[int]*eddsa.PrivateKey) (*Committee, error) {
+	leaves := make([]*big.Int, len(members))
+	for i := range members {
+		leaves[i] = memberLeaf(&members[i])
+	}
+	tree, err := zkp.NewMerkleTree(leaves, zkp.BridgeCommitteeDepth)
+	if err != nil {
+		return nil, fmt.Errorf("committee too large: %w", err)
+	}
+
+	for i, key := range local {
+		if i < 0 || i >= len(members) || !key.PublicKey.Equal(&members[i]) {
+			return nil, fmt.Errorf("local key %d is not committee member %d", i, i)
+		}
+	}
+	return &Committee{
+		members: members,
+		local:   local,
+		tree:    tree,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// SetPeers sets the API URLs of the nodes holding the other members' keys
+func (c *Committee) SetPeers(urls []string) error {
+	peers := make([]string, len(urls))
+	for i, url := range urls {
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			return fmt.Errorf("bridge peer %q must be an http(s) URL", url)
+		}
+		peers[i] = strings.TrimRight(url, "/") + CosignPath
+	}
+	c.peers = peers
+	return nil
+}
+
+// CommitteeRoot returns the root target chains check proofs against
+func (c *Committee) CommitteeRoot() *big.Int {
+	return c.tree.Root()
+}
+
+// Cosign signs root with every locally held member key
+func (c *Committee) Cosign(root *big.Int) ([]MemberSignature, error) {
+	if len(c.local) == 0 {
+		return nil, fmt.Errorf("no committee keys held by this node")
+	}
+	msg := rootMessage(root)
+	sigs := make([]MemberSignature, 0, len(c.local))
+	for i, key := range c.local {
+		sig, err := key.Sign(msg, hash.MIMC_BN254.New())
+		if err != nil {
+			return nil, fmt.Errorf("member %d failed to sign: %w", i, err)
+		}
+		sigs = append(sigs, MemberSignature{Index: i, Signature: sig})
+	}
+	return sigs, nil
+}
+
+// SignBatch signs the root with the local members and asks the peers for the
+// rest. Every peer signature is checked against its member's key; the lowest
+// BridgeQuorum indices are returned in the increasing order the bridge circuit
+// requires.
+func (c *Committee) SignBatch(ctx context.Context, root *big.Int, batch []chains.BridgeMessage) ([]zkp.BridgeSignature, error) {
+	collected := make(map[int][]byte)
+	if len(c.local) > 0 {
+		local, err := c.Cosign(root)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range local {
+			collected[s.Index] = s.Signature
+		}
+	}
+	if len(collected) < zkp.BridgeQuorum && len(c.peers) > 0 {
+		for _, s := range c.collect(ctx, batch) {
+			if _, ok := collected[s.Index]; ok {
+				continue
+			}
+			if err := c.verify(root, s); err != nil {
+				log.Warn().Err(err).Int("member", s.Index).Msg("Discarding invalid committee signature")
+				continue
+			}
+			collected[s.Index] = s.Signature
+		}
+	}
+	if len(collected) < zkp.BridgeQuorum {
+		return nil, fmt.Errorf("only %d of %d required committee signatures collected", len(collected), zkp.BridgeQuorum)
+	}
+
+	indices := make([]int, 0, len(collected))
+	for i := range collected {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+
+	sigs := make([]zkp.BridgeSignature, 0, zkp.BridgeQuorum)
+	for _, i := range indices[:zkp.BridgeQuorum] {
+		sigs = append(sigs, zkp.BridgeSignature{
+			PublicKey: c.members[i].Bytes(),
+			Signature: collected[i],
+			Index:     i,
+			Path:      c.tree.Path(i),
+		})
+	}
+	return sigs, nil
+}
+
+// collect posts the batch to every peer and returns whatever they signed
+func (c *Committee) collect(ctx context.Context, batch []chains.BridgeMessage) []MemberSignature {
+	body, err := json.Marshal(CosignRequest{Messages: batch})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to encode cosign request")
+		return nil
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		sigs []MemberSignature
+	)
+	for _, url := range c.peers {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			resp, err := c.post(ctx, url, body)
+			if err != nil {
+				log.Warn().Err(err).Str("peer", url).Msg("Committee peer did not cosign batch")
+				return
+			}
+			mu.Lock()
+			sigs = append(sigs, resp.Signatures...)
+			mu.Unlock()
+		}(url)
+	}
+	wg.Wait()
+	return sigs
+}
+
+func (c *Committee) post(ctx context.Context, url string, body []byte) (*CosignResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	var out CosignResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("invalid cosign response: %w", err)
+	}
+	return &out, nil
+}
+
+// verify checks a peer's signature against the member key at its index
+func (c *Committee) verify(root *big.Int, s MemberSignature) error {
+	if s.Index < 0 || s.Index >= len(c.members) {
+		return fmt.Errorf("no committee member %d", s.Index)
+	}
+	ok, err := c.members[s.Index].Verify(s.Signature, rootMessage(root), hash.MIMC_BN254.New())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("signature does not verify")
+	}
+	return nil
+}
+
+// ParseMemberKey decodes a hex-encoded compressed committee public key
+func ParseMemberKey(s string) (eddsa.PublicKey, error) {
+	var pub eddsa.PublicKey
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return pub, fmt.Errorf("invalid member key: %w", err)
+	}
+	if _, err := pub.SetBytes(b); err != nil {
+		return pub, fmt.Errorf("invalid member key: %w", err)
+	}
+	return pub, nil
+}
+
+// ParseCommitteeKey decodes a hex-encoded committee private key
+func ParseCommitteeKey(s string) (*eddsa.PrivateKey, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid committee key: %w", err)
+	}
+	key := new(eddsa.PrivateKey)
+	if _, err := key.SetBytes(b); err != nil {
+		return nil, fmt.Errorf("invalid committee key: %w", err)
+	}
+	return key, nil
+}
+
+func rootMessage(root *big.Int) []byte {
+	msg := make([]byte, 32)
+	root.FillBytes(msg)
+	return msg
+}
+
+func memberLeaf(pub *eddsa.PublicKey) *big.Int {
+	return zkp.MiMCHash(pub.A.X.BigInt(new(big.Int)), pub.A.Y.BigInt(new(big.Int)))
+}
@@ -1,95 +1,339 @@
 package crosschain
 
 import (
-	"crypto/rand"
+	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"sync"
+	"time"
 
-	"github.com/obscura-network/obscura-node/zkp"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/rs/zerolog/log"
+
+	"github.com/obscura-network/obscura-node/chains"
+	"github.com/obscura-network/obscura-node/storage"
+	"github.com/obscura-network/obscura-node/zkp"
 )
 
-// BridgeMessage represents a cross-chain payload
-type BridgeMessage struct {
-	ID        string
-	Source    string
-	Target    string
-	Data      []byte
-	Signature []byte
+const deliveredPrefix = "bridge_delivered_"
+
+// maxBatchSize is the number of leaves in a batch tree
+const maxBatchSize = 1 << zkp.BridgeBatchDepth
+
+// errUndeliverable marks messages that retrying cannot deliver
+var errUndeliverable = errors.New("message cannot be delivered")
+
+// ErrUnverifiedMessage is returned to a peer asking to cosign a message this
+// node cannot find on its source chain
+var ErrUnverifiedMessage = errors.New("bridge message not verified on source chain")
+
+// DeliveryRecord marks a message as delivered so it is never relayed twice
+type DeliveryRecord struct {
+	SourceChain uint64    `json:"source_chain"`
+	Nonce       uint64    `json:"nonce"`
+	TargetChain uint64    `json:"target_chain"`
+	TxHash      string    `json:"tx_hash"`
+	DeliveredAt time.Time `json:"delivered_at"`
 }
 
-// CrossLink handles cross-chain communication
+// CrossLink relays BridgeMessage events between chains. Observed messages are
+// batched into a MiMC Merkle tree, the root is signed by a committee quorum, and
+// each message is delivered to its target chain with a proof of inclusion.
+// Committee members that do not relay only cosign batches other nodes built.
 type CrossLink struct {
-	supportedChains []string
-	secretKey       *big.Int // Bridge signing key for ZK proofs
+	mu       sync.Mutex
+	store    storage.Store
+	signer   BatchSigner
+	adapters map[uint64]chains.ChainAdapter
+	pending  []chains.BridgeMessage
+	queued   map[string]bool
+	interval time.Duration
+	relay    bool
 }
 
-// NewCrossLink initializes the bridge module with a secure random key
-func NewCrossLink() *CrossLink {
-	// Generate a cryptographically secure random key for ZK proof generation
-	secretKey, err := rand.Int(rand.Reader, new(big.Int).Exp(big.NewInt(2), big.NewInt(254), nil))
-	if err != nil {
-		// Fallback to deterministic key if random fails (should never happen)
-		log.Warn().Err(err).Msg("Failed to generate random key, using deterministic fallback")
-		secretKey = new(big.Int).SetBytes([]byte("obscura-bridge-default-key-v1"))
+// NewCrossLink creates a relayer; chains are added with RegisterChain
+func NewCrossLink(store storage.Store, signer BatchSigner) *CrossLink {
+	return &CrossLink{
+		store:    store,
+		signer:   signer,
+		adapters: make(map[uint64]chains.ChainAdapter),
+		queued:   make(map[string]bool),
+		interval: 15 * time.Second,
+		relay:    true,
 	}
+}
 
-	return &CrossLink{
-		supportedChains: []string{"ethereum", "solana", "arbitrum", "optimism", "base", "polygon"},
-		secretKey:       secretKey,
+// SetRelay controls whether this node batches and delivers messages itself;
+// when false it only answers cosign requests
+func (cl *CrossLink) SetRelay(relay bool) {
+	cl.relay = relay
+}
+
+// RegisterChain makes a chain both a message source and a delivery target
+func (cl *CrossLink) RegisterChain(adapter chains.ChainAdapter) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.adapters[adapter.ChainID()] = adapter
+}
+
+// Start subscribes to every registered chain and flushes batches until ctx is done
+func (cl *CrossLink) Start(ctx context.Context) {
+	cl.mu.Lock()
+	adapters := make([]chains.ChainAdapter, 0, len(cl.adapters))
+	for _, a := range cl.adapters {
+		adapters = append(adapters, a)
+	}
+	cl.mu.Unlock()
+
+	if cl.signer == nil || len(adapters) == 0 {
+		log.Info().Msg("Bridge relayer disabled: no committee signer or chains registered")
+		return
+	}
+	if !cl.relay {
+		log.Info().Int("chains", len(adapters)).Msg("Bridge relaying disabled; cosigning batches for peers only")
+		return
+	}
+
+	for _, a := range adapters {
+		if err := a.SubscribeBridgeMessages(ctx, cl.Observe); err != nil {
+			log.Error().Err(err).Str("chain", a.Name()).Msg("Failed to subscribe to bridge messages")
+		}
+	}
+
+	ticker := time.NewTicker(cl.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := cl.Flush(ctx); err != nil {
+				log.Error().Err(err).Msg("Bridge batch failed")
+			}
+		}
 	}
 }
 
-// NewCrossLinkWithKey initializes the bridge module with a provided key (for testing)
-func NewCrossLinkWithKey(key *big.Int) *CrossLink {
-	return &CrossLink{
-		supportedChains: []string{"ethereum", "solana", "arbitrum", "optimism", "base", "polygon"},
-		secretKey:       key,
+// Observe queues a source-chain message unless it was already queued or delivered
+func (cl *CrossLink) Observe(msg *chains.BridgeMessage) {
+	key := messageKey(msg.SourceChain, msg.Nonce)
+
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if cl.queued[key] || cl.deliveredLocked(key) {
+		log.Debug().Uint64("source", msg.SourceChain).Uint64("nonce", msg.Nonce).Msg("Ignoring replayed bridge message")
+		return
+	}
+	cl.queued[key] = true
+	cl.pending = append(cl.pending, *msg)
+}
+
+// Delivered reports whether a message was already delivered to its target chain
+func (cl *CrossLink) Delivered(sourceChain, nonce uint64) bool {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return cl.deliveredLocked(messageKey(sourceChain, nonce))
+}
+
+// Pending returns the number of messages waiting for the next batch
+func (cl *CrossLink) Pending() int {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return len(cl.pending)
+}
+
+// Flush commits the pending messages to a batch, has the root signed and
+// delivers each message with its proof. Messages that fail to send are
+// requeued for the next batch; it returns the number delivered.
+func (cl *CrossLink) Flush(ctx context.Context) (int, error) {
+	cl.mu.Lock()
+	n := len(cl.pending)
+	if n > maxBatchSize {
+		n = maxBatchSize
+	}
+	batch := cl.pending[:n:n]
+	cl.pending = cl.pending[n:]
+	cl.mu.Unlock()
+
+	if len(batch) == 0 {
+		return 0, nil
+	}
+
+	tree, err := batchTree(batch)
+	if err != nil {
+		cl.requeue(batch...)
+		return 0, err
 	}
+
+	sigs, err := cl.signer.SignBatch(ctx, tree.Root(), batch)
+	if err != nil {
+		cl.requeue(batch...)
+		return 0, fmt.Errorf("failed to sign batch root: %w", err)
+	}
+
+	delivered := 0
+	for i, msg := range batch {
+		err := cl.deliver(ctx, msg, batchWitness(tree, i, msg, cl.signer.CommitteeRoot(), sigs))
+		switch {
+		case err == nil:
+			delivered++
+		case err == errUndeliverable:
+			cl.forget(msg)
+		case errors.Is(err, chains.ErrDeliveryReverted):
+			log.Error().Err(err).Uint64("source", msg.SourceChain).Uint64("nonce", msg.Nonce).Msg("Dropping bridge message the target chain rejects")
+			cl.forget(msg)
+		default:
+			log.Warn().Err(err).Uint64("source", msg.SourceChain).Uint64("nonce", msg.Nonce).Msg("Bridge delivery failed, requeueing")
+			cl.requeue(msg)
+		}
+	}
+
+	log.Info().Int("batch", len(batch)).Int("delivered", delivered).Str("root", tree.Root().Text(16)).Msg("Bridge batch relayed")
+	return delivered, nil
 }
 
-// RelayMessage handles relaying a message to another chain with verification
-func (cl *CrossLink) RelayMessage(msg BridgeMessage) error {
-	log.Info().Str("msg_id", msg.ID).Str("target", msg.Target).Msg("Relaying Cross-Chain Message")
-	
-	// Chain verification
-	isValid := false
-	for _, c := range cl.supportedChains {
-		if c == msg.Target { isValid = true; break }
+func (cl *CrossLink) deliver(ctx context.Context, msg chains.BridgeMessage, w zkp.BridgeWitness) error {
+	cl.mu.Lock()
+	adapter, ok := cl.adapters[msg.TargetChain]
+	cl.mu.Unlock()
+	if !ok {
+		log.Error().Uint64("target", msg.TargetChain).Uint64("nonce", msg.Nonce).Msg("Dropping bridge message for unregistered target chain")
+		return errUndeliverable
 	}
-	if !isValid { return fmt.Errorf("unsupported chain: %s", msg.Target) }
 
-	// 1. Generate ZK Proof of validity
-	proof, err := cl.GenerateZKProofForBridge(msg.Data)
+	proof, err := zkp.GenerateBridgeProof(w)
 	if err != nil {
 		return fmt.Errorf("failed to generate bridge proof: %w", err)
 	}
+	serialized, err := zkp.SerializeProof(proof)
+	if err != nil {
+		return err
+	}
 
-	log.Info().
-		Str("msg_id", msg.ID).
-		Int("proof_len", len(proof)).
-		Msg("Message Relayed Successfully with ZK Proof")
+	receipt, err := adapter.DeliverBridgeMessage(ctx, chains.BridgeDelivery{
+		Message:       msg,
+		BatchRoot:     w.BatchRoot,
+		CommitteeRoot: w.CommitteeRoot,
+		Proof:         serialized,
+	})
+	if err != nil {
+		return err
+	}
+	if !receipt.Status {
+		// The target contract rejects nonces it has seen, so a revert usually
+		// means another relayer delivered first; retrying would not help
+		log.Error().Str("tx", receipt.TxHash).Uint64("nonce", msg.Nonce).Msg("Bridge delivery reverted")
+		return errUndeliverable
+	}
 
-	return nil
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	key := messageKey(msg.SourceChain, msg.Nonce)
+	delete(cl.queued, key)
+	return cl.store.SaveJob(deliveredPrefix+key, DeliveryRecord{
+		SourceChain: msg.SourceChain,
+		Nonce:       msg.Nonce,
+		TargetChain: msg.TargetChain,
+		TxHash:      receipt.TxHash,
+		DeliveredAt: time.Now(),
+	})
 }
 
-// GenerateZKProofForBridge generates a validity proof for the state transition
-func (cl *CrossLink) GenerateZKProofForBridge(data []byte) ([]byte, error) {
-	msgHash := new(big.Int).SetBytes(data)
-	originChain := big.NewInt(1) // Ethereum = 1
+// Cosign signs a batch a relaying peer built, after checking every message
+// against its source chain so the committee never signs an invented message
+func (cl *CrossLink) Cosign(ctx context.Context, req CosignRequest) (*CosignResponse, error) {
+	if cl.signer == nil {
+		return nil, fmt.Errorf("no committee signer configured")
+	}
+	if len(req.Messages) == 0 || len(req.Messages) > maxBatchSize {
+		return nil, fmt.Errorf("batch must hold 1 to %d messages", maxBatchSize)
+	}
 
-	proof, err := zkp.GenerateBridgeProof(msgHash, originChain, cl.secretKey)
+	for _, msg := range req.Messages {
+		cl.mu.Lock()
+		adapter, ok := cl.adapters[msg.SourceChain]
+		cl.mu.Unlock()
+		if !ok {
+			return nil, fmt.Errorf("%w: source chain %d is not registered", ErrUnverifiedMessage, msg.SourceChain)
+		}
+		if err := adapter.VerifyBridgeMessage(ctx, msg); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnverifiedMessage, err)
+		}
+	}
+
+	tree, err := batchTree(req.Messages)
 	if err != nil {
 		return nil, err
 	}
+	sigs, err := cl.signer.Cosign(tree.Root())
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Int("batch", len(req.Messages)).Str("root", tree.Root().Text(16)).Msg("Cosigned bridge batch")
+	return &CosignResponse{Signatures: sigs}, nil
+}
+
+// batchTree commits to a batch in message order
+func batchTree(batch []chains.BridgeMessage) (*zkp.MerkleTree, error) {
+	leaves := make([]*big.Int, len(batch))
+	for i, msg := range batch {
+		leaves[i] = zkp.BridgeLeaf(msg.SourceChain, msg.TargetChain, msg.Nonce, payloadHash(msg))
+	}
+	return zkp.NewMerkleTree(leaves, zkp.BridgeBatchDepth)
+}
 
-	serialized, _ := zkp.SerializeProof(proof)
-	
-	// Convert [8]*big.Int to []byte for transmission
-	var output []byte
-	for _, b := range serialized {
-		output = append(output, b.Bytes()...)
+// batchWitness assembles the circuit inputs for message i of a batch
+func batchWitness(tree *zkp.MerkleTree, i int, msg chains.BridgeMessage, committeeRoot *big.Int, sigs []zkp.BridgeSignature) zkp.BridgeWitness {
+	return zkp.BridgeWitness{
+		BatchRoot:     tree.Root(),
+		CommitteeRoot: committeeRoot,
+		SourceChain:   msg.SourceChain,
+		TargetChain:   msg.TargetChain,
+		Nonce:         msg.Nonce,
+		PayloadHash:   payloadHash(msg),
+		MessageIndex:  i,
+		MessagePath:   tree.Path(i),
+		Signatures:    sigs,
 	}
+}
+
+func (cl *CrossLink) requeue(msgs ...chains.BridgeMessage) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.pending = append(cl.pending, msgs...)
+}
+
+func (cl *CrossLink) forget(msg chains.BridgeMessage) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	delete(cl.queued, messageKey(msg.SourceChain, msg.Nonce))
+}
+
+func (cl *CrossLink) deliveredLocked(key string) bool {
+	_, ok := cl.store.GetJob(deliveredPrefix + key)
+	return ok
+}
+
+// payloadArgs is the abi.encode(address, address, bytes) layout ObscuraBridge hashes
+var payloadArgs = func() abi.Arguments {
+	address, _ := abi.NewType("address", "", nil)
+	bytesType, _ := abi.NewType("bytes", "", nil)
+	return abi.Arguments{{Type: address}, {Type: address}, {Type: bytesType}}
+}()
+
+// payloadHash matches ObscuraBridge.payloadHash, which binds the sender and
+// receiver so a relayer cannot redirect a proven payload
+func payloadHash(msg chains.BridgeMessage) *big.Int {
+	encoded, err := payloadArgs.Pack(common.HexToAddress(msg.Sender), common.HexToAddress(msg.Receiver), msg.Payload)
+	if err != nil {
+		panic(fmt.Sprintf("bridge payload encoding: %v", err))
+	}
+	return zkp.FieldElement(crypto.Keccak256(encoded))
+}
 
-	return output, nil
+func messageKey(sourceChain, nonce uint64) string {
+	return fmt.Sprintf("%d_%d", sourceChain, nonce)
 }
@@ -0,0 +1,204 @@
+package crosschain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/consensys/gnark-crypto/hash"
+
+	"github.com/obscura-network/obscura-node/chains"
+	"github.com/obscura-network/obscura-node/storage"
+)
+
+// mockChain records deliveries; unimplemented ChainAdapter methods panic
+type mockChain struct {
+	chains.ChainAdapter
+	id         uint64
+	fail       bool
+	deliveries []chains.BridgeDelivery
+	emitted    map[uint64]chains.BridgeMessage // by nonce, for VerifyBridgeMessage
+}
+
+func (m *mockChain) ChainID() uint64 { return m.id }
+func (m *mockChain) Name() string    { return "mock" }
+
+func (m *mockChain) DeliverBridgeMessage(_ context.Context, d chains.BridgeDelivery) (*chains.TransactionReceipt, error) {
+	if m.fail {
+		return nil, errors.New("rpc unavailable")
+	}
+	m.deliveries = append(m.deliveries, d)
+	return &chains.TransactionReceipt{TxHash: "0xdead", Status: true}, nil
+}
+
+func (m *mockChain) VerifyBridgeMessage(_ context.Context, msg chains.BridgeMessage) error {
+	emitted, ok := m.emitted[msg.Nonce]
+	if !ok || emitted.Receiver != msg.Receiver || string(emitted.Payload) != string(msg.Payload) {
+		return errors.New("no such event")
+	}
+	return nil
+}
+
+func newTestCommittee(t *testing.T) *Committee {
+	members := make([]eddsa.PublicKey, 4)
+	local := make(map[int]*eddsa.PrivateKey)
+	for i := range members {
+		key, err := eddsa.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		members[i] = key.PublicKey
+		if i != 1 {
+			local[i] = key
+		}
+	}
+	c, err := NewCommittee(members, local)
+	if err != nil {
+		t.Fatalf("NewCommittee failed: %v", err)
+	}
+	return c
+}
+
+func TestCrossLinkDeliversOnceByNonce(t *testing.T) {
+	store, err := storage.NewFileStore("./test_crosslink.json")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer store.Clear()
+
+	target := &mockChain{id: 42161, fail: true}
+	cl := NewCrossLink(store, newTestCommittee(t))
+	cl.RegisterChain(&mockChain{id: 1})
+	cl.RegisterChain(target)
+
+	msg := &chains.BridgeMessage{SourceChain: 1, TargetChain: 42161, Nonce: 7, Sender: "0x01", Receiver: "0x02", Payload: []byte("mint 10")}
+	cl.Observe(msg)
+	cl.Observe(msg)
+	if cl.Pending() != 1 {
+		t.Fatalf("Expected duplicate nonce to be queued once, got %d pending", cl.Pending())
+	}
+
+	// A failed send is kept for the next batch
+	if n, err := cl.Flush(context.Background()); err != nil || n != 0 {
+		t.Fatalf("Expected no deliveries while the target is down, got %d (%v)", n, err)
+	}
+	if cl.Pending() != 1 {
+		t.Fatalf("Expected the message to be requeued, got %d pending", cl.Pending())
+	}
+
+	target.fail = false
+	if n, err := cl.Flush(context.Background()); err != nil || n != 1 {
+		t.Fatalf("Expected one delivery, got %d (%v)", n, err)
+	}
+	d := target.deliveries[0]
+	if d.Message.Nonce != 7 || d.CommitteeRoot.Cmp(cl.signer.CommitteeRoot()) != 0 || d.Proof[0] == nil {
+		t.Errorf("Unexpected delivery: %+v", d)
+	}
+
+	// Replays of the same event after delivery are ignored, including after a restart
+	reloaded, _ := storage.NewFileStore("./test_crosslink.json")
+	restarted := NewCrossLink(reloaded, cl.signer)
+	restarted.Observe(msg)
+	if restarted.Pending() != 0 || !restarted.Delivered(1, 7) {
+		t.Error("Delivered message was queued again")
+	}
+}
+
+func TestCommitteeNeedsQuorumOfLocalKeys(t *testing.T) {
+	members := make([]eddsa.PublicKey, 3)
+	local := make(map[int]*eddsa.PrivateKey)
+	for i := range members {
+		key, _ := eddsa.GenerateKey(rand.Reader)
+		members[i] = key.PublicKey
+		if i == 0 {
+			local[i] = key
+		}
+	}
+	c, err := NewCommittee(members, local)
+	if err != nil {
+		t.Fatalf("NewCommittee failed: %v", err)
+	}
+	if _, err := c.SignBatch(context.Background(), c.CommitteeRoot(), nil); err == nil {
+		t.Error("Expected signing to fail without a quorum of keys")
+	}
+
+	other, _ := eddsa.GenerateKey(rand.Reader)
+	if _, err := NewCommittee(members, map[int]*eddsa.PrivateKey{1: other}); err == nil {
+		t.Error("Expected a key that is not the member's to be rejected")
+	}
+}
+
+func TestCommitteeCollectsPeerSignatures(t *testing.T) {
+	keys := make([]*eddsa.PrivateKey, 4)
+	members := make([]eddsa.PublicKey, len(keys))
+	for i := range keys {
+		keys[i], _ = eddsa.GenerateKey(rand.Reader)
+		members[i] = keys[i].PublicKey
+	}
+
+	msg := chains.BridgeMessage{SourceChain: 1, TargetChain: 42161, Nonce: 7, Sender: "0x01", Receiver: "0x02", Payload: []byte("mint 10"), TxHash: "0xabc"}
+
+	// The peer holds members 1 and 2 and only signs messages it finds on chain
+	peerCommittee, err := NewCommittee(members, map[int]*eddsa.PrivateKey{1: keys[1], 2: keys[2]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	peer := NewCrossLink(nil, peerCommittee)
+	peer.RegisterChain(&mockChain{id: 1, emitted: map[uint64]chains.BridgeMessage{7: msg}})
+	peerServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CosignRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		resp, err := peer.Cosign(r.Context(), req)
+		if err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer peerServer.Close()
+
+	// A misbehaving peer claims member 3 with a key that is not the member's
+	forger, _ := eddsa.GenerateKey(rand.Reader)
+	forgedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CosignRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		tree, _ := batchTree(req.Messages)
+		sig, _ := forger.Sign(rootMessage(tree.Root()), hash.MIMC_BN254.New())
+		json.NewEncoder(w).Encode(CosignResponse{Signatures: []MemberSignature{{Index: 3, Signature: sig}}})
+	}))
+	defer forgedServer.Close()
+
+	leader, err := NewCommittee(members, map[int]*eddsa.PrivateKey{0: keys[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := leader.SetPeers([]string{peerServer.URL, forgedServer.URL}); err != nil {
+		t.Fatal(err)
+	}
+
+	batch := []chains.BridgeMessage{msg}
+	tree, _ := batchTree(batch)
+	sigs, err := leader.SignBatch(context.Background(), tree.Root(), batch)
+	if err != nil {
+		t.Fatalf("SignBatch failed: %v", err)
+	}
+	for i, s := range sigs {
+		if s.Index != i {
+			t.Errorf("Expected members 0, 1, 2 in order, got %d at position %d", s.Index, i)
+		}
+	}
+
+	// A message the source chain never emitted gets no peer signatures
+	forged := msg
+	forged.Receiver = "0x03"
+	batch = []chains.BridgeMessage{forged}
+	tree, _ = batchTree(batch)
+	if _, err := leader.SignBatch(context.Background(), tree.Root(), batch); err == nil {
+		t.Error("Expected a batch with an unverified message to miss the quorum")
+	}
+}
@@ -3,66 +3,207 @@ package functions
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/rs/zerolog/log"
 	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
 	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
 )
 
 // ComputeManager handles WASM function execution
 // Using wazero (Pure Go) instead of wasmtime-go for better portability/no-cgo,
 // consistent with existing go.mod dependencies.
 type ComputeManager struct {
-	runtime wazero.Runtime
+	runtime  wazero.Runtime
+	sandbox  Sandbox
+	hostMu   sync.RWMutex
+	host     Host
+	mu       sync.Mutex
+	compiled map[string]wazero.CompiledModule // module hash -> compiled module
 }
 
-// NewComputeManager initializes the WASM runtime
-func NewComputeManager(ctx context.Context) (*ComputeManager, error) {
-	r := wazero.NewRuntime(ctx)
+// NewComputeManager initializes the WASM runtime with every call confined to
+// sandbox. Compiled code is cached in cacheDir so modules are not recompiled
+// after a restart; an empty cacheDir keeps the cache in memory.
+func NewComputeManager(ctx context.Context, cacheDir string, sandbox Sandbox) (*ComputeManager, error) {
+	sandbox = sandbox.withDefaults()
+	cache := wazero.NewCompilationCache()
+	if cacheDir != "" {
+		var err error
+		if cache, err = wazero.NewCompilationCacheWithDir(cacheDir); err != nil {
+			return nil, fmt.Errorf("failed to open compilation cache: %w", err)
+		}
+	}
+	r := wazero.NewRuntimeWithConfig(ctx, sandbox.runtimeConfig().WithCompilationCache(cache))
 	
-	// Instantiate WASI
+	// Instantiate WASI; what it can reach is decided per instance by the sandbox
 	if _, err := wasi_snapshot_preview1.Instantiate(ctx, r); err != nil {
 		return nil, err
 	}
 
-	return &ComputeManager{
-		runtime: r,
-	}, nil
+	cm := &ComputeManager{
+		runtime:  r,
+		sandbox:  sandbox,
+		compiled: make(map[string]wazero.CompiledModule),
+	}
+	if err := cm.instantiateHost(ctx, r); err != nil {
+		return nil, fmt.Errorf("failed to register host functions: %w", err)
+	}
+	return cm, nil
 }
 
-// ExecuteWasm runs a WASM binary with input data
-func (cm *ComputeManager) ExecuteWasm(ctx context.Context, wasmCode []byte, funcName string, params []uint64) ([]uint64, error) {
-	log.Debug().Msg("Compiling and Executing WASM function")
+// SetHost connects the host functions to node services
+func (cm *ComputeManager) SetHost(host Host) {
+	cm.hostMu.Lock()
+	defer cm.hostMu.Unlock()
+	cm.host = host
+}
 
-	// Compile module
-	mod, err := cm.runtime.CompileModule(ctx, wasmCode)
+func (cm *ComputeManager) currentHost() Host {
+	cm.hostMu.RLock()
+	defer cm.hostMu.RUnlock()
+	return cm.host
+}
+
+// Compile meters and compiles a module once and keeps it for later calls.
+// The module is validated as submitted first: code touching globals it does
+// not declare would otherwise reach the fuel global the rewrite appends.
+func (cm *ComputeManager) Compile(ctx context.Context, wasmCode []byte) (wazero.CompiledModule, error) {
+	hash := ModuleHash(wasmCode)
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if mod, ok := cm.compiled[hash]; ok {
+		return mod, nil
+	}
+	original, err := cm.runtime.CompileModule(ctx, wasmCode)
 	if err != nil {
 		return nil, fmt.Errorf("compile error: %w", err)
 	}
-	defer mod.Close(ctx)
+	original.Close(ctx)
+	metered, err := meter(wasmCode, cm.sandbox.Fuel)
+	if err != nil {
+		return nil, fmt.Errorf("compile error: %w", err)
+	}
+	mod, err := cm.runtime.CompileModule(ctx, metered)
+	if err != nil {
+		return nil, fmt.Errorf("compile error: %w", err)
+	}
+	cm.compiled[hash] = mod
+	return mod, nil
+}
+
+// Exports lists the functions a module exports
+func (cm *ComputeManager) Exports(ctx context.Context, wasmCode []byte) ([]string, error) {
+	mod, err := cm.Compile(ctx, wasmCode)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for name := range mod.ExportedFunctions() {
+		if strings.HasPrefix(name, reservedPrefix) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Evict drops the compiled module for hash
+func (cm *ComputeManager) Evict(ctx context.Context, hash string) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if mod, ok := cm.compiled[hash]; ok {
+		mod.Close(ctx)
+		delete(cm.compiled, hash)
+	}
+}
+
+// Sandbox returns the limits every call runs under
+func (cm *ComputeManager) Sandbox() Sandbox {
+	return cm.sandbox
+}
+
+// ExecuteWasm runs a WASM binary with input data in a fresh instance, within
+// the sandbox's memory, fuel and time budget
+func (cm *ComputeManager) ExecuteWasm(ctx context.Context, wasmCode []byte, funcName string, params []uint64) ([]uint64, error) {
+	result, err := cm.Invoke(ctx, wasmCode, Call{Entrypoint: funcName, Params: params})
+	return result.Results, err
+}
+
+// Invoke runs one call in a fresh instance, within the sandbox's memory, fuel
+// and time budget, with the host functions scoped to the module
+func (cm *ComputeManager) Invoke(ctx context.Context, wasmCode []byte, call Call) (CallResult, error) {
+	log.Debug().Msg("Executing WASM function")
+	if len(call.Input) > MaxHostBuffer {
+		return CallResult{}, fmt.Errorf("input of %d bytes exceeds %d", len(call.Input), MaxHostBuffer)
+	}
 
-	// Instantiate
-	modConfig := wazero.NewModuleConfig().WithStdout(log.Logger).WithStderr(log.Logger)
-	instance, err := cm.runtime.InstantiateModule(ctx, mod, modConfig)
+	mod, err := cm.Compile(ctx, wasmCode)
 	if err != nil {
-		return nil, fmt.Errorf("instantiate error: %w", err)
+		return CallResult{}, err
+	}
+
+	inv := &invocation{module: ModuleHash(wasmCode), input: call.Input}
+	callCtx, cancel := context.WithTimeout(withInvocation(ctx, inv), cm.sandbox.Timeout)
+	defer cancel()
+
+	instance, err := cm.runtime.InstantiateModule(callCtx, mod, cm.sandbox.moduleConfig())
+	if err != nil {
+		return CallResult{}, cm.budgetError(ctx, callCtx, nil, fmt.Errorf("instantiate error: %w", err))
 	}
 	defer instance.Close(ctx)
 
+	// Start functions run under the budget too, and share the call's fuel
+	for _, name := range []string{startExport, "_start"} {
+		start := instance.ExportedFunction(name)
+		if start == nil {
+			continue
+		}
+		if _, err := start.Call(callCtx); err != nil {
+			var exit *sys.ExitError
+			if errors.As(err, &exit) && exit.ExitCode() == 0 {
+				continue
+			}
+			return CallResult{}, cm.budgetError(ctx, callCtx, instance, fmt.Errorf("instantiate error: %w", err))
+		}
+	}
+
 	// Export function
-	f := instance.ExportedFunction(funcName)
+	f := instance.ExportedFunction(call.Entrypoint)
 	if f == nil {
-		return nil, fmt.Errorf("function %s not found", funcName)
+		return CallResult{}, fmt.Errorf("function %s not found", call.Entrypoint)
 	}
 
 	// Call
-	results, err := f.Call(ctx, params...)
+	results, err := f.Call(callCtx, call.Params...)
 	if err != nil {
-		return nil, fmt.Errorf("execution error: %w", err)
+		return CallResult{}, cm.budgetError(ctx, callCtx, instance, fmt.Errorf("execution error: %w", err))
 	}
 
-	return results, nil
+	return CallResult{Results: results, Output: inv.output}, nil
+}
+
+// budgetError tells a call that ran out of budget apart from one whose
+// caller gave up. Fuel is checked first: it runs out at the same point on
+// every node, so the error does not depend on which budget a node hit first.
+func (cm *ComputeManager) budgetError(ctx, callCtx context.Context, instance api.Module, err error) error {
+	if instance != nil {
+		if fuel := instance.ExportedGlobal(fuelExport); fuel != nil && int64(fuel.Get()) < 0 {
+			return fmt.Errorf("%w: ran out of %d fuel", ErrBudgetExceeded, cm.sandbox.Fuel)
+		}
+	}
+	if ctx.Err() == nil && callCtx.Err() != nil {
+		return fmt.Errorf("%w: no result within %s", ErrBudgetExceeded, cm.sandbox.Timeout)
+	}
+	return err
 }
 
 // Close cleans up resources
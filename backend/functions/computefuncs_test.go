@@ -0,0 +1,208 @@
+package functions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// loopModule exports run() i64, which never returns
+var loopModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	0x01, 0x05, 0x01, 0x60, 0x00, 0x01, 0x7e,
+	0x03, 0x02, 0x01, 0x00,
+	0x07, 0x07, 0x01, 0x03, 'r', 'u', 'n', 0x00, 0x00,
+	0x0a, 0x0b, 0x01, 0x09, 0x00, 0x03, 0x40, 0x0c, 0x00, 0x0b, 0x42, 0x00, 0x0b, // loop { br 0 }
+}
+
+// memoryModule exports run() i64 returning 7 and declares pages of memory
+func memoryModule(pages byte) []byte {
+	return []byte{
+		0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+		0x01, 0x05, 0x01, 0x60, 0x00, 0x01, 0x7e,
+		0x03, 0x02, 0x01, 0x00,
+		0x05, 0x03, 0x01, 0x00, pages, // one memory, no maximum
+		0x07, 0x07, 0x01, 0x03, 'r', 'u', 'n', 0x00, 0x00,
+		0x0a, 0x06, 0x01, 0x04, 0x00, 0x42, 0x07, 0x0b,
+	}
+}
+
+func TestExecuteWasmEnforcesTimeBudget(t *testing.T) {
+	ctx := context.Background()
+	cm, err := NewComputeManager(ctx, "", Sandbox{Timeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to start wasm runtime: %v", err)
+	}
+	defer cm.Close(ctx)
+
+	start := time.Now()
+	if _, err := cm.ExecuteWasm(ctx, loopModule, "run", nil); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("Expected an endless loop to exceed its budget, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Loop ran for %s past a 100ms budget", elapsed)
+	}
+
+	// A caller that gives up is not the function's fault
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := cm.ExecuteWasm(cancelled, loopModule, "run", nil); err == nil || errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected a cancelled call to fail without exceeding its budget, got %v", err)
+	}
+}
+
+// countModule exports run() i64, which counts to n in a loop. Metered, it
+// costs 3 instructions outside the loop and 8 per iteration.
+func countModule(n int) []byte {
+	body := []byte{0x01, 0x01, 0x7e} // one i64 local
+	body = append(body, 0x03, 0x40, 0x20, 0x00, 0x42, 0x01, 0x7c, 0x22, 0x00, 0x42)
+	body = append(body, sleb(n)...)
+	body = append(body, 0x53, 0x0d, 0x00, 0x0b, 0x20, 0x00, 0x0b)
+
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	module = append(module, section(1, vec([]byte{0x60, 0x00, 0x01, 0x7e}))...)
+	module = append(module, section(3, vec([]byte{0x00}))...)
+	module = append(module, section(7, vec(append(str("run"), 0x00, 0x00)))...)
+	return append(module, section(10, vec(append(uleb(len(body)), body...)))...)
+}
+
+func sleb(n int) []byte {
+	var out []byte
+	for {
+		b := byte(n & 0x7f)
+		n >>= 7
+		if n == 0 && b&0x40 == 0 {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func TestExecuteWasmEnforcesFuelBudget(t *testing.T) {
+	ctx := context.Background()
+	const iterations = 1000
+	needed := uint64(3 + 8*iterations)
+
+	run := func(fuel uint64, timeout time.Duration, code []byte) ([]uint64, error) {
+		cm, err := NewComputeManager(ctx, "", Sandbox{Fuel: fuel, Timeout: timeout})
+		if err != nil {
+			t.Fatalf("Failed to start wasm runtime: %v", err)
+		}
+		defer cm.Close(ctx)
+		return cm.ExecuteWasm(ctx, code, "run", nil)
+	}
+
+	// The cut-off is the same instruction however long the node would wait
+	for _, timeout := range []time.Duration{time.Second, time.Hour} {
+		results, err := run(needed, timeout, countModule(iterations))
+		if err != nil || len(results) != 1 || results[0] != iterations {
+			t.Fatalf("Expected the loop to finish on exactly its fuel, got %v, %v", results, err)
+		}
+		if _, err := run(needed-1, timeout, countModule(iterations)); !errors.Is(err, ErrBudgetExceeded) {
+			t.Fatalf("Expected the loop to run out one instruction short, got %v", err)
+		}
+	}
+
+	// An endless loop fails with the same error wherever it runs
+	first, err := run(1_000_000, time.Hour, loopModule)
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("Expected an endless loop to run out of fuel, got %v, %v", first, err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, again := run(1_000_000, time.Minute, loopModule); again == nil || again.Error() != err.Error() {
+			t.Errorf("Expected the same failure on every run, got %v then %v", err, again)
+		}
+	}
+}
+
+func TestStartFunctionsAreMetered(t *testing.T) {
+	ctx := context.Background()
+	cm, err := NewComputeManager(ctx, "", Sandbox{Fuel: 1_000_000, Timeout: time.Hour})
+	if err != nil {
+		t.Fatalf("Failed to start wasm runtime: %v", err)
+	}
+	defer cm.Close(ctx)
+
+	// start() loops forever; run() i64 returns 0
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	module = append(module, section(1, vec([]byte{0x60, 0x00, 0x00}, []byte{0x60, 0x00, 0x01, 0x7e}))...)
+	module = append(module, section(3, vec([]byte{0x00}, []byte{0x01}))...)
+	module = append(module, section(7, vec(append(str("run"), 0x00, 0x01)))...)
+	module = append(module, section(8, []byte{0x00})...)
+	module = append(module, section(10, vec(
+		[]byte{0x07, 0x00, 0x03, 0x40, 0x0c, 0x00, 0x0b, 0x0b},
+		[]byte{0x04, 0x00, 0x42, 0x00, 0x0b},
+	))...)
+
+	if _, err := cm.ExecuteWasm(ctx, module, "run", nil); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("Expected an endless start function to run out of fuel, got %v", err)
+	}
+	exports, err := cm.Exports(ctx, module)
+	if err != nil || len(exports) != 1 || exports[0] != "run" {
+		t.Errorf("Expected only the module's own exports, got %v, %v", exports, err)
+	}
+}
+
+func TestMeterRejectsReservedExports(t *testing.T) {
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	module = append(module, section(1, vec([]byte{0x60, 0x00, 0x00}))...)
+	module = append(module, section(3, vec([]byte{0x00}))...)
+	module = append(module, section(7, vec(append(str(fuelExport), 0x00, 0x00)))...)
+	module = append(module, section(10, vec([]byte{0x02, 0x00, 0x0b}))...)
+
+	if _, err := meter(module, 10); err == nil {
+		t.Error("Expected a module exporting the fuel global's name to be rejected")
+	}
+}
+
+func TestCompileRejectsFuelRefill(t *testing.T) {
+	ctx := context.Background()
+	cm, err := NewComputeManager(ctx, "", Sandbox{Fuel: 10_000, Timeout: time.Hour})
+	if err != nil {
+		t.Fatalf("Failed to start wasm runtime: %v", err)
+	}
+	defer cm.Close(ctx)
+
+	// run() i64 loops forever, writing global 0, which it does not declare
+	// but which the rewrite would add as the fuel global
+	body := []byte{0x00, 0x03, 0x40, 0x42, 0xe4, 0x00, 0x24, 0x00, 0x0c, 0x00, 0x0b, 0x42, 0x00, 0x0b}
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	module = append(module, section(1, vec([]byte{0x60, 0x00, 0x01, 0x7e}))...)
+	module = append(module, section(3, vec([]byte{0x00}))...)
+	module = append(module, section(7, vec(append(str("run"), 0x00, 0x00)))...)
+	module = append(module, section(10, vec(append(uleb(len(body)), body...)))...)
+
+	if _, err := cm.Compile(ctx, module); err == nil {
+		t.Fatal("Expected a module writing an undeclared global to be rejected")
+	}
+	if _, err := cm.ExecuteWasm(ctx, module, "run", nil); err == nil || errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected the module to fail to compile rather than run, got %v", err)
+	}
+}
+
+func TestExecuteWasmCapsMemory(t *testing.T) {
+	ctx := context.Background()
+	cm, err := NewComputeManager(ctx, "", Sandbox{MemoryPages: 4})
+	if err != nil {
+		t.Fatalf("Failed to start wasm runtime: %v", err)
+	}
+	defer cm.Close(ctx)
+
+	results, err := cm.ExecuteWasm(ctx, memoryModule(2), "run", nil)
+	if err != nil || len(results) != 1 || results[0] != 7 {
+		t.Fatalf("Expected a module within the cap to run, got %v, %v", results, err)
+	}
+	if _, err := cm.ExecuteWasm(ctx, memoryModule(8), "run", nil); err == nil {
+		t.Error("Expected a module declaring more memory than the cap to be rejected")
+	}
+}
+
+func TestDefaultSandboxIsDeterministic(t *testing.T) {
+	if !DefaultSandbox().Deterministic() {
+		t.Error("Expected the default sandbox to grant nothing")
+	}
+	if (Sandbox{Clock: true}).Deterministic() {
+		t.Error("Expected a clock grant to make results node-dependent")
+	}
+}
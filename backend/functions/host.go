@@ -0,0 +1,272 @@
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+
+	"github.com/obscura-network/obscura-node/adapters"
+	"github.com/obscura-network/obscura-node/oracle"
+	"github.com/obscura-network/obscura-node/storage"
+)
+
+// HostModule is the import module of the host functions:
+//
+//	input_len() i32                       length of the call's input
+//	input_read(ptr i32)                   copy the input to ptr
+//	output_write(ptr, len i32) i32        set the call's output
+//	feed_read(id_ptr, id_len i32) i32     latest value of a feed, as JSON
+//	http_fetch(req_ptr, req_len i32) i32  GET an allowlisted URL, as JSON
+//	secret_read(name_ptr, name_len i32) i32  a secret in the module's scope
+//	result_read(ptr i32)                  copy the last result to ptr
+//
+// Calls that produce data return its length, to be copied out with
+// result_read, or a negative HostStatus.
+const HostModule = "obscura"
+
+// MaxHostBuffer bounds the input, output and each result of a call
+const MaxHostBuffer = 1 << 20
+
+// HostStatus is returned by host functions that fail
+type HostStatus int32
+
+const (
+	HostDenied   HostStatus = -1 // not allowlisted or out of scope
+	HostNotFound HostStatus = -2
+	HostFailed   HostStatus = -3
+)
+
+// Host connects functions to node services. A nil service, an empty
+// allowlist or a module without a secret scope leaves the matching host
+// functions returning HostDenied.
+type Host struct {
+	Feeds    *oracle.FeedManager
+	Adapters *adapters.AdapterManager
+	Secrets  *storage.SecretManager
+
+	// HTTPAllowlist holds the URL prefixes http_fetch may reach
+	HTTPAllowlist []string
+	// SecretScopes maps a module hash to the secret names it may read
+	SecretScopes map[string][]string
+}
+
+// Deterministic reports whether feed_read and http_fetch are disabled. Both
+// return whatever a feed or URL holds when called, so results read through
+// them differ between nodes.
+func (h Host) Deterministic() bool {
+	return h.Feeds == nil && len(h.HTTPAllowlist) == 0
+}
+
+// HTTPRequest is the JSON a module passes to http_fetch. The response body
+// must be JSON; path selects a value within it as in data sources.
+type HTTPRequest struct {
+	URL     string            `json:"url"`
+	Path    string            `json:"path,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Call is one invocation of a function
+type Call struct {
+	Entrypoint string
+	Params     []uint64
+	Input      []byte // read with input_len and input_read
+}
+
+// CallResult is what a function returned
+type CallResult struct {
+	Results []uint64
+	Output  []byte // written with output_write
+}
+
+// invocation is the per-call state host functions reach through the context
+type invocation struct {
+	module string
+	input  []byte
+	output []byte
+	result []byte
+}
+
+type invocationKey struct{}
+
+func withInvocation(ctx context.Context, inv *invocation) context.Context {
+	return context.WithValue(ctx, invocationKey{}, inv)
+}
+
+func invocationFrom(ctx context.Context) *invocation {
+	inv, _ := ctx.Value(invocationKey{}).(*invocation)
+	if inv == nil {
+		return &invocation{}
+	}
+	return inv
+}
+
+// instantiateHost registers the host module with the runtime. The functions
+// read cm.host on every call, so SetHost takes effect immediately.
+func (cm *ComputeManager) instantiateHost(ctx context.Context, r wazero.Runtime) error {
+	_, err := r.NewHostModuleBuilder(HostModule).
+		NewFunctionBuilder().WithFunc(func(ctx context.Context) uint32 {
+		return uint32(len(invocationFrom(ctx).input))
+	}).Export("input_len").
+		NewFunctionBuilder().WithFunc(func(ctx context.Context, m api.Module, ptr uint32) {
+		writeMemory(m, ptr, invocationFrom(ctx).input)
+	}).Export("input_read").
+		NewFunctionBuilder().WithFunc(func(ctx context.Context, m api.Module, ptr, n uint32) int32 {
+		if n > MaxHostBuffer {
+			return int32(HostDenied)
+		}
+		invocationFrom(ctx).output = append([]byte(nil), readMemory(m, ptr, n)...)
+		return 0
+	}).Export("output_write").
+		NewFunctionBuilder().WithFunc(func(ctx context.Context, m api.Module, ptr, n uint32) int32 {
+		return cm.feedRead(ctx, string(readMemory(m, ptr, n)))
+	}).Export("feed_read").
+		NewFunctionBuilder().WithFunc(func(ctx context.Context, m api.Module, ptr, n uint32) int32 {
+		return cm.httpFetch(ctx, readMemory(m, ptr, n))
+	}).Export("http_fetch").
+		NewFunctionBuilder().WithFunc(func(ctx context.Context, m api.Module, ptr, n uint32) int32 {
+		return cm.secretRead(ctx, string(readMemory(m, ptr, n)))
+	}).Export("secret_read").
+		NewFunctionBuilder().WithFunc(func(ctx context.Context, m api.Module, ptr uint32) {
+		writeMemory(m, ptr, invocationFrom(ctx).result)
+	}).Export("result_read").
+		Instantiate(ctx)
+	return err
+}
+
+// setResult stores data for result_read and returns its length
+func setResult(ctx context.Context, data []byte) int32 {
+	if len(data) > MaxHostBuffer {
+		return int32(HostFailed)
+	}
+	invocationFrom(ctx).result = data
+	return int32(len(data))
+}
+
+func (cm *ComputeManager) feedRead(ctx context.Context, id string) int32 {
+	host := cm.currentHost()
+	if host.Feeds == nil {
+		return int32(HostDenied)
+	}
+	status, ok := host.Feeds.GetLatest(id)
+	if !ok {
+		return int32(HostNotFound)
+	}
+	data, err := json.Marshal(status)
+	if err != nil {
+		return int32(HostFailed)
+	}
+	return setResult(ctx, data)
+}
+
+func (cm *ComputeManager) httpFetch(ctx context.Context, raw []byte) int32 {
+	host := cm.currentHost()
+	var req HTTPRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return int32(HostFailed)
+	}
+	if host.Adapters == nil || !host.allows(req.URL) {
+		return int32(HostDenied)
+	}
+
+	// The fetch cannot outlive the call's budget
+	timeout := cm.sandbox.Timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	if timeout <= 0 {
+		return int32(HostFailed)
+	}
+	result, err := host.Adapters.Fetch(adapters.FetchDataRequest{
+		URL:      req.URL,
+		Method:   "GET",
+		Path:     req.Path,
+		Headers:  req.Headers,
+		Retries:  1,
+		Timeout:  timeout,
+		Redirect: host.allows,
+	})
+	if err != nil {
+		return int32(HostFailed)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return int32(HostFailed)
+	}
+	return setResult(ctx, data)
+}
+
+func (cm *ComputeManager) secretRead(ctx context.Context, name string) int32 {
+	host := cm.currentHost()
+	if host.Secrets == nil || !host.inScope(invocationFrom(ctx).module, name) {
+		return int32(HostDenied)
+	}
+	secret, ok := host.Secrets.GetCredential(name)
+	if !ok {
+		return int32(HostNotFound)
+	}
+	return setResult(ctx, []byte(secret))
+}
+
+// allows matches rawURL against the allowlist by scheme, host and whole path
+// segments, so a prefix cannot be extended into another host or a sibling
+// path. Paths with ".." segments are refused rather than resolved.
+func (h Host) allows(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || u.User != nil {
+		return false
+	}
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment == ".." {
+			return false
+		}
+	}
+	target := path.Clean("/" + u.Path)
+	for _, prefix := range h.HTTPAllowlist {
+		p, err := url.Parse(prefix)
+		if err != nil {
+			continue
+		}
+		base := strings.TrimSuffix(path.Clean("/"+p.Path), "/")
+		if u.Scheme == p.Scheme && u.Host == p.Host && (target == base || strings.HasPrefix(target, base+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h Host) inScope(module, name string) bool {
+	for _, allowed := range h.SecretScopes[module] {
+		if allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
+// readMemory returns a view of guest memory; an out-of-range access traps
+func readMemory(m api.Module, ptr, n uint32) []byte {
+	if m.Memory() == nil {
+		panic(fmt.Errorf("host call from a module without memory"))
+	}
+	data, ok := m.Memory().Read(ptr, n)
+	if !ok {
+		panic(fmt.Errorf("host call read out of range: %d+%d", ptr, n))
+	}
+	return data
+}
+
+func writeMemory(m api.Module, ptr uint32, data []byte) {
+	if m.Memory() == nil {
+		panic(fmt.Errorf("host call from a module without memory"))
+	}
+	if !m.Memory().Write(ptr, data) {
+		panic(fmt.Errorf("host call write out of range: %d+%d", ptr, len(data)))
+	}
+}
@@ -0,0 +1,237 @@
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/obscura-network/obscura-node/adapters"
+	"github.com/obscura-network/obscura-node/oracle"
+	"github.com/obscura-network/obscura-node/storage"
+)
+
+var (
+	typeBuffer = []byte{0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7f} // (i32, i32) -> i32
+	typePtr    = []byte{0x60, 0x01, 0x7f, 0x00}             // (i32) -> ()
+	typeLen    = []byte{0x60, 0x00, 0x01, 0x7f}             // () -> i32
+	typeRun    = []byte{0x60, 0x00, 0x01, 0x7e}             // () -> i64
+)
+
+type hostImport struct {
+	name    string
+	typeIdx byte
+}
+
+func uleb(n int) []byte {
+	var out []byte
+	for {
+		b := byte(n & 0x7f)
+		n >>= 7
+		if n == 0 {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+// i32Const encodes a non-negative i32.const
+func i32Const(n int) []byte {
+	out := []byte{0x41}
+	for {
+		b := byte(n & 0x7f)
+		n >>= 7
+		if n == 0 && b&0x40 == 0 {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func vec(items ...[]byte) []byte {
+	out := uleb(len(items))
+	for _, item := range items {
+		out = append(out, item...)
+	}
+	return out
+}
+
+func section(id byte, content []byte) []byte {
+	return append(append([]byte{id}, uleb(len(content))...), content...)
+}
+
+func str(s string) []byte {
+	return append(uleb(len(s)), s...)
+}
+
+// hostModule assembles a module importing host functions, with one page of
+// memory holding data at offset 0 and run() i64 as the last function
+func hostModule(imports []hostImport, body []byte, data string) []byte {
+	var importEntries [][]byte
+	for _, imp := range imports {
+		entry := append(str(HostModule), str(imp.name)...)
+		importEntries = append(importEntries, append(entry, 0x00, imp.typeIdx))
+	}
+	fn := append(uleb(len(body)), body...)
+	segment := append(append([]byte{0x00}, append(i32Const(0), 0x0b)...), str(data)...)
+
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	module = append(module, section(1, vec(typeBuffer, typePtr, typeLen, typeRun))...)
+	module = append(module, section(2, vec(importEntries...))...)
+	module = append(module, section(3, vec([]byte{3}))...)
+	module = append(module, section(5, vec([]byte{0x00, 0x01}))...)
+	module = append(module, section(7, vec(append(str("run"), 0x00, byte(len(imports)))))...)
+	module = append(module, section(10, vec(fn))...)
+	module = append(module, section(11, vec(segment))...)
+	return module
+}
+
+// callModule calls fn(data) and writes whatever it returned as the output,
+// returning fn's status
+func callModule(fn, data string) []byte {
+	imports := []hostImport{{fn, 0}, {"result_read", 1}, {"output_write", 0}}
+	var body []byte
+	body = append(body, 0x01, 0x01, 0x7f) // one i32 local
+	body = append(body, i32Const(0)...)
+	body = append(body, i32Const(len(data))...)
+	body = append(body, 0x10, 0x00, 0x21, 0x00) // local = fn(0, len)
+	body = append(body, i32Const(1024)...)
+	body = append(body, 0x10, 0x01) // result_read(1024)
+	body = append(body, i32Const(1024)...)
+	body = append(body, 0x20, 0x00, 0x10, 0x02, 0x1a) // output_write(1024, local)
+	body = append(body, 0x20, 0x00, 0xac, 0x0b)       // return i64(local)
+	return hostModule(imports, body, data)
+}
+
+// echoModule writes its input back as its output
+var echoModule = hostModule([]hostImport{{"input_len", 2}, {"input_read", 1}, {"output_write", 0}}, []byte{
+	0x01, 0x01, 0x7f,
+	0x10, 0x00, 0x21, 0x00, // local = input_len()
+	0x41, 0x00, 0x10, 0x01, // input_read(0)
+	0x41, 0x00, 0x20, 0x00, 0x10, 0x02, 0x1a, // output_write(0, local)
+	0x20, 0x00, 0xac, 0x0b,
+}, "")
+
+func newHostedManager(t *testing.T) *ComputeManager {
+	ctx := context.Background()
+	cm, err := NewComputeManager(ctx, "", DefaultSandbox())
+	if err != nil {
+		t.Fatalf("Failed to start wasm runtime: %v", err)
+	}
+	t.Cleanup(func() { cm.Close(ctx) })
+	return cm
+}
+
+func invoke(t *testing.T, cm *ComputeManager, code []byte, input string) (int64, string) {
+	result, err := cm.Invoke(context.Background(), code, Call{Entrypoint: "run", Input: []byte(input)})
+	if err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+	return int64(result.Results[0]), string(result.Output)
+}
+
+func TestHostPassesBuffersThroughMemory(t *testing.T) {
+	cm := newHostedManager(t)
+	status, output := invoke(t, cm, echoModule, `{"balances":[1,2,3]}`)
+	if status != 20 || output != `{"balances":[1,2,3]}` {
+		t.Errorf("Expected the input echoed back, got %d %q", status, output)
+	}
+}
+
+func TestHostReadsFeeds(t *testing.T) {
+	cm := newHostedManager(t)
+	module := callModule("feed_read", "ETH-USD")
+	if status, _ := invoke(t, cm, module, ""); status != int64(HostDenied) {
+		t.Errorf("Expected feed reads to be denied without a feed manager, got %d", status)
+	}
+
+	feeds := oracle.NewFeedManager()
+	feeds.RegisterFeed(&oracle.FeedConfig{ID: "ETH-USD", Active: true})
+	cm.SetHost(Host{Feeds: feeds})
+	if status, _ := invoke(t, cm, module, ""); status != int64(HostNotFound) {
+		t.Errorf("Expected a feed without a value to be not found, got %d", status)
+	}
+
+	feeds.UpdateFeedValue(oracle.FeedLiveStatus{ID: "ETH-USD", Value: "$3850.10", Answer: 3850.1})
+	_, output := invoke(t, cm, module, "")
+	var status oracle.FeedLiveStatus
+	if err := json.Unmarshal([]byte(output), &status); err != nil || status.Answer != 3850.1 {
+		t.Errorf("Expected the feed's latest value, got %q", output)
+	}
+}
+
+func TestHostFetchesAllowlistedURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"rate": 4.25}}`))
+	}))
+	defer server.Close()
+
+	cm := newHostedManager(t)
+	cm.SetHost(Host{Adapters: adapters.NewAdapterManager(), HTTPAllowlist: []string{server.URL + "/rates"}})
+
+	allowed, _ := json.Marshal(HTTPRequest{URL: server.URL + "/rates/usd", Path: "data.rate"})
+	if _, output := invoke(t, cm, callModule("http_fetch", string(allowed)), ""); output != "4.25" {
+		t.Errorf("Expected the selected value, got %q", output)
+	}
+
+	for _, outside := range []string{"/admin", "/rates-admin", "/rates/../admin", "/rates/%2e%2e/admin"} {
+		denied, _ := json.Marshal(HTTPRequest{URL: server.URL + outside})
+		if status, _ := invoke(t, cm, callModule("http_fetch", string(denied)), ""); status != int64(HostDenied) {
+			t.Errorf("Expected %s outside the allowlist to be denied, got %d", outside, status)
+		}
+	}
+}
+
+func TestHostFetchDoesNotFollowRedirectsOffTheAllowlist(t *testing.T) {
+	var leaked string
+	elsewhere := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaked = r.Header.Get("X-Api-Key")
+		w.Write([]byte(`{"data": {"rate": 1}}`))
+	}))
+	defer elsewhere.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rates/moved":
+			http.Redirect(w, r, "/rates/usd", http.StatusFound)
+		case "/rates/usd":
+			w.Write([]byte(`{"data": {"rate": 4.25}}`))
+		default:
+			http.Redirect(w, r, elsewhere.URL+"/steal", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	cm := newHostedManager(t)
+	cm.SetHost(Host{Adapters: adapters.NewAdapterManager(), HTTPAllowlist: []string{server.URL + "/rates"}})
+
+	moved, _ := json.Marshal(HTTPRequest{URL: server.URL + "/rates/moved", Path: "data.rate"})
+	if _, output := invoke(t, cm, callModule("http_fetch", string(moved)), ""); output != "4.25" {
+		t.Errorf("Expected a redirect within the allowlist to be followed, got %q", output)
+	}
+
+	away, _ := json.Marshal(HTTPRequest{URL: server.URL + "/rates/away", Headers: map[string]string{"X-Api-Key": "secret"}})
+	if status, _ := invoke(t, cm, callModule("http_fetch", string(away)), ""); status != int64(HostFailed) {
+		t.Errorf("Expected a redirect off the allowlist to fail, got %d", status)
+	}
+	if leaked != "" {
+		t.Errorf("Expected the headers to stay on the allowlisted host, got %q", leaked)
+	}
+}
+
+func TestHostScopesSecrets(t *testing.T) {
+	secrets := storage.NewSecretManager()
+	secrets.AddSecret("bank-api", "Bearer bank-key")
+
+	cm := newHostedManager(t)
+	module := callModule("secret_read", "bank-api")
+	other := append(callModule("secret_read", "bank-api"), section(0, append(str("copy"), 0x01))...) // same code, another hash
+	cm.SetHost(Host{Secrets: secrets, SecretScopes: map[string][]string{ModuleHash(module): {"bank-api"}}})
+
+	if _, output := invoke(t, cm, module, ""); output != "Bearer bank-key" {
+		t.Errorf("Expected the scoped secret, got %q", output)
+	}
+	if status, _ := invoke(t, cm, other, ""); status != int64(HostDenied) {
+		t.Errorf("Expected a module without a scope to be denied, got %d", status)
+	}
+}
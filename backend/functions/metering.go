@@ -0,0 +1,501 @@
+package functions
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Modules are metered by rewriting them before compilation. A mutable i64
+// global starts at the sandbox's fuel; every function entry and every loop
+// iteration subtracts the number of instructions its body can run before the
+// next metered point, and traps once the global drops below zero. The budget
+// therefore depends only on the code and its inputs, never on the host.
+const (
+	// reservedPrefix is the export namespace the rewrite adds to
+	reservedPrefix = "obscura."
+	// fuelExport is the fuel global, read back to tell exhaustion apart from
+	// the module's own traps
+	fuelExport = reservedPrefix + "fuel"
+	// startExport is the module's start function, which the rewrite moves out
+	// of instantiation so it runs metered like any other call
+	startExport = reservedPrefix + "start"
+)
+
+const (
+	sectionCustom   = 0
+	sectionImport   = 2
+	sectionGlobal   = 6
+	sectionExport   = 7
+	sectionStart    = 8
+	sectionCode     = 10
+	exportFunction  = 0x00
+	exportGlobal    = 0x03
+	opLoop          = 0x03
+	opBlock         = 0x02
+	opIf            = 0x04
+	opEnd           = 0x0b
+	valueTypeI64    = 0x7e
+	globalMutable   = 0x01
+	blockTypeEmpty  = 0x40
+	opI64Const      = 0x42
+	opGlobalGet     = 0x23
+	opGlobalSet     = 0x24
+	opI64Sub        = 0x7d
+	opI64LtS        = 0x53
+	opUnreachable   = 0x00
+	wasmHeaderBytes = 8
+)
+
+// sectionOrder ranks the known sections in the order they must appear
+var sectionOrder = map[byte]int{1: 1, 2: 2, 3: 3, 4: 4, 5: 5, 13: 6, 6: 7, 7: 8, 8: 9, 9: 10, 12: 11, 10: 12, 11: 13}
+
+type rawSection struct {
+	id      byte
+	content []byte
+}
+
+// meter returns code with fuel metering injected, starting from fuel units
+func meter(code []byte, fuel uint64) ([]byte, error) {
+	if len(code) < wasmHeaderBytes || string(code[:4]) != "\x00asm" {
+		return nil, errors.New("not a wasm module")
+	}
+	if fuel > math.MaxInt64 {
+		fuel = math.MaxInt64
+	}
+
+	var sections []rawSection
+	r := &wasmReader{b: code, pos: wasmHeaderBytes}
+	for !r.done() {
+		id := r.byte()
+		size := r.u32()
+		content := r.bytes(int(size))
+		if r.err != nil {
+			return nil, fmt.Errorf("malformed section %d: %w", id, r.err)
+		}
+		sections = append(sections, rawSection{id: id, content: content})
+	}
+
+	var (
+		importedGlobals, definedGlobals uint32
+		start                           *uint32
+	)
+	for _, s := range sections {
+		var err error
+		switch s.id {
+		case sectionImport:
+			importedGlobals, err = countImportedGlobals(s.content)
+		case sectionGlobal:
+			r := &wasmReader{b: s.content}
+			definedGlobals, err = r.u32(), r.err
+		case sectionExport:
+			err = checkExportNames(s.content)
+		case sectionStart:
+			r := &wasmReader{b: s.content}
+			idx := r.u32()
+			start, err = &idx, r.err
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	global := importedGlobals + definedGlobals
+
+	fuelGlobal := []byte{valueTypeI64, globalMutable, opI64Const}
+	fuelGlobal = appendSLEB(fuelGlobal, int64(fuel))
+	fuelGlobal = append(fuelGlobal, opEnd)
+
+	exports := [][]byte{appendExport(nil, fuelExport, exportGlobal, global)}
+	if start != nil {
+		exports = append(exports, appendExport(nil, startExport, exportFunction, *start))
+	}
+
+	added := map[byte][][]byte{sectionGlobal: {fuelGlobal}, sectionExport: exports}
+	out := append([]byte(nil), code[:wasmHeaderBytes]...)
+	emitAdded := func(rank int) {
+		for _, id := range []byte{sectionGlobal, sectionExport} {
+			if entries, ok := added[id]; ok && sectionOrder[id] < rank {
+				out = appendSection(out, id, appendVector(nil, 0, nil, entries))
+				delete(added, id)
+			}
+		}
+	}
+
+	for _, s := range sections {
+		if s.id != sectionCustom {
+			emitAdded(sectionOrder[s.id])
+		}
+		switch s.id {
+		case sectionGlobal, sectionExport:
+			r := &wasmReader{b: s.content}
+			n := r.u32()
+			out = appendSection(out, s.id, appendVector(nil, n, r.rest(), added[s.id]))
+			delete(added, s.id)
+		case sectionStart:
+			// Exported instead, and called by Invoke
+		case sectionCode:
+			content, err := meterCode(s.content, global)
+			if err != nil {
+				return nil, err
+			}
+			out = appendSection(out, s.id, content)
+		default:
+			out = appendSection(out, s.id, s.content)
+		}
+	}
+	emitAdded(math.MaxInt)
+	return out, nil
+}
+
+// countImportedGlobals counts the global imports, which come before the
+// module's own globals in the index space
+func countImportedGlobals(content []byte) (uint32, error) {
+	r := &wasmReader{b: content}
+	var globals uint32
+	for n := r.u32(); n > 0 && r.err == nil; n-- {
+		r.name()
+		r.name()
+		switch kind := r.byte(); kind {
+		case 0x00: // function
+			r.u32()
+		case 0x01: // table
+			r.byte()
+			r.limits()
+		case 0x02: // memory
+			r.limits()
+		case 0x03: // global
+			r.byte()
+			r.byte()
+			globals++
+		default:
+			return 0, fmt.Errorf("unknown import kind %#x", kind)
+		}
+	}
+	if r.err != nil {
+		return 0, fmt.Errorf("malformed import section: %w", r.err)
+	}
+	return globals, nil
+}
+
+// checkExportNames keeps modules out of the namespace the rewrite exports in
+func checkExportNames(content []byte) error {
+	r := &wasmReader{b: content}
+	for n := r.u32(); n > 0 && r.err == nil; n-- {
+		if name := r.name(); strings.HasPrefix(name, reservedPrefix) {
+			return fmt.Errorf("export %q uses the reserved %q prefix", name, reservedPrefix)
+		}
+		r.byte()
+		r.u32()
+	}
+	if r.err != nil {
+		return fmt.Errorf("malformed export section: %w", r.err)
+	}
+	return nil
+}
+
+// meterCode injects a fuel charge at the entry of every function body and the
+// head of every loop
+func meterCode(content []byte, global uint32) ([]byte, error) {
+	r := &wasmReader{b: content}
+	n := r.u32()
+	out := appendULEB(nil, uint64(n))
+	for i := uint32(0); i < n && r.err == nil; i++ {
+		size := r.u32()
+		body, err := meterBody(r.bytes(int(size)), global)
+		if err != nil {
+			return nil, fmt.Errorf("function body %d: %w", i, err)
+		}
+		out = appendULEB(out, uint64(len(body)))
+		out = append(out, body...)
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("malformed code section: %w", r.err)
+	}
+	return out, nil
+}
+
+// meteredPoint is where a charge is injected and how much it charges: the
+// instructions of its function or loop body outside nested loops, an upper
+// bound of what runs before the next charge
+type meteredPoint struct {
+	offset int
+	cost   int64
+}
+
+func meterBody(body []byte, global uint32) ([]byte, error) {
+	r := &wasmReader{b: body}
+	for n := r.u32(); n > 0 && r.err == nil; n-- {
+		r.u32()
+		r.byte()
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	points := []meteredPoint{{offset: r.pos}}
+	// frames holds, per open block, the point its instructions are charged to
+	frames := []int{0}
+	for len(frames) > 0 {
+		if r.done() {
+			return nil, errors.New("unterminated function body")
+		}
+		op, err := r.instruction()
+		if err != nil {
+			return nil, err
+		}
+		points[frames[len(frames)-1]].cost++
+		switch op {
+		case opLoop:
+			points = append(points, meteredPoint{offset: r.pos})
+			frames = append(frames, len(points)-1)
+		case opBlock, opIf:
+			frames = append(frames, frames[len(frames)-1])
+		case opEnd:
+			frames = frames[:len(frames)-1]
+		}
+	}
+	if !r.done() {
+		return nil, errors.New("trailing bytes after function body")
+	}
+
+	out := make([]byte, 0, len(body)+len(points)*24)
+	last := 0
+	for _, p := range points {
+		out = append(out, body[last:p.offset]...)
+		out = appendCharge(out, global, p.cost)
+		last = p.offset
+	}
+	return append(out, body[last:]...), nil
+}
+
+// appendCharge subtracts cost from the fuel global and traps below zero
+func appendCharge(out []byte, global uint32, cost int64) []byte {
+	out = append(out, opGlobalGet)
+	out = appendULEB(out, uint64(global))
+	out = append(out, opI64Const)
+	out = appendSLEB(out, cost)
+	out = append(out, opI64Sub, opGlobalSet)
+	out = appendULEB(out, uint64(global))
+	out = append(out, opGlobalGet)
+	out = appendULEB(out, uint64(global))
+	out = append(out, opI64Const, 0x00, opI64LtS, opIf, blockTypeEmpty, opUnreachable, opEnd)
+	return out
+}
+
+func appendExport(out []byte, name string, kind byte, index uint32) []byte {
+	out = appendULEB(out, uint64(len(name)))
+	out = append(out, name...)
+	out = append(out, kind)
+	return appendULEB(out, uint64(index))
+}
+
+// appendVector encodes a vector of n existing entries followed by added ones
+func appendVector(out []byte, n uint32, existing []byte, added [][]byte) []byte {
+	out = appendULEB(out, uint64(n)+uint64(len(added)))
+	out = append(out, existing...)
+	for _, entry := range added {
+		out = append(out, entry...)
+	}
+	return out
+}
+
+func appendSection(out []byte, id byte, content []byte) []byte {
+	out = append(out, id)
+	out = appendULEB(out, uint64(len(content)))
+	return append(out, content...)
+}
+
+func appendULEB(out []byte, v uint64) []byte {
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func appendSLEB(out []byte, v int64) []byte {
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+// wasmReader decodes the parts of the binary format the rewrite needs; the
+// first error sticks and later reads return zero values
+type wasmReader struct {
+	b   []byte
+	pos int
+	err error
+}
+
+func (r *wasmReader) done() bool {
+	return r.err != nil || r.pos >= len(r.b)
+}
+
+func (r *wasmReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *wasmReader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if r.pos >= len(r.b) {
+		r.fail(errors.New("unexpected end of module"))
+		return 0
+	}
+	b := r.b[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *wasmReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || r.pos+n > len(r.b) {
+		r.fail(errors.New("unexpected end of module"))
+		return nil
+	}
+	b := r.b[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *wasmReader) rest() []byte {
+	return r.bytes(len(r.b) - r.pos)
+}
+
+func (r *wasmReader) u32() uint32 {
+	var v uint64
+	for shift := 0; shift < 35; shift += 7 {
+		b := r.byte()
+		v |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			if v > math.MaxUint32 {
+				r.fail(errors.New("integer too large"))
+			}
+			return uint32(v)
+		}
+	}
+	r.fail(errors.New("integer too long"))
+	return 0
+}
+
+// leb skips a LEB128 integer of any width
+func (r *wasmReader) leb() {
+	for i := 0; i < 10; i++ {
+		if r.byte()&0x80 == 0 {
+			return
+		}
+	}
+	r.fail(errors.New("integer too long"))
+}
+
+func (r *wasmReader) name() string {
+	return string(r.bytes(int(r.u32())))
+}
+
+func (r *wasmReader) limits() {
+	flags := r.byte()
+	r.leb()
+	if flags&0x01 != 0 {
+		r.leb()
+	}
+}
+
+// instruction skips one instruction and its immediates, returning its opcode
+func (r *wasmReader) instruction() (byte, error) {
+	op := r.byte()
+	switch {
+	case op == opBlock || op == opLoop || op == opIf:
+		// Empty, a value type, or a signed type index
+		r.leb()
+	case op == 0x0c || op == 0x0d: // br, br_if
+		r.leb()
+	case op == 0x0e: // br_table
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			r.leb()
+		}
+		r.leb()
+	case op == 0x10: // call
+		r.leb()
+	case op == 0x11: // call_indirect
+		r.leb()
+		r.leb()
+	case op == 0x1c: // select with types
+		r.bytes(int(r.u32()))
+	case op >= 0x20 && op <= 0x26: // locals, globals, table.get/set
+		r.leb()
+	case op >= 0x28 && op <= 0x3e: // loads and stores
+		r.leb()
+		r.leb()
+	case op == 0x3f || op == 0x40: // memory.size, memory.grow
+		r.leb()
+	case op == 0x41 || op == opI64Const:
+		r.leb()
+	case op == 0x43:
+		r.bytes(4)
+	case op == 0x44:
+		r.bytes(8)
+	case op == 0xd0: // ref.null
+		r.byte()
+	case op == 0xd2: // ref.func
+		r.leb()
+	case op == 0xfc:
+		r.miscImmediates(r.u32())
+	case op == 0xfd:
+		r.vectorImmediates(r.u32())
+	case op <= 0x01 || op == 0x05 || op == opEnd || op == 0x0f || op == 0x1a || op == 0x1b ||
+		(op >= 0x45 && op <= 0xc4) || op == 0xd1:
+		// No immediates
+	default:
+		r.fail(fmt.Errorf("unsupported opcode %#x", op))
+	}
+	return op, r.err
+}
+
+// miscImmediates skips the immediates of the 0xfc saturating truncation,
+// bulk memory and table instructions
+func (r *wasmReader) miscImmediates(sub uint32) {
+	switch {
+	case sub <= 7:
+	case sub == 8: // memory.init
+		r.leb()
+		r.leb()
+	case sub == 9 || sub == 11 || sub == 13 || (sub >= 15 && sub <= 17):
+		r.leb()
+	case sub == 10 || sub == 12 || sub == 14:
+		r.leb()
+		r.leb()
+	default:
+		r.fail(fmt.Errorf("unsupported opcode 0xfc %d", sub))
+	}
+}
+
+// vectorImmediates skips the immediates of the 0xfd SIMD instructions
+func (r *wasmReader) vectorImmediates(sub uint32) {
+	switch {
+	case sub <= 11 || sub == 92 || sub == 93: // loads and stores
+		r.leb()
+		r.leb()
+	case sub == 12 || sub == 13: // v128.const, i8x16.shuffle
+		r.bytes(16)
+	case sub >= 21 && sub <= 34: // lane access
+		r.byte()
+	case sub >= 84 && sub <= 91: // lane loads and stores
+		r.leb()
+		r.leb()
+		r.byte()
+	}
+}
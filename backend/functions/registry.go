@@ -0,0 +1,341 @@
+package functions
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// MaxModuleSize bounds the modules the registry accepts
+const MaxModuleSize = 4 << 20
+
+// DefaultAllowedExports are the functions a module may export unless the
+// operator allows more
+var DefaultAllowedExports = []string{"run"}
+
+var (
+	// ErrModuleNotFound is returned for a hash with no stored module
+	ErrModuleNotFound = errors.New("wasm module not found")
+	// ErrInvalidModule marks a module rejected at registration
+	ErrInvalidModule = errors.New("invalid wasm module")
+	// ErrNotOwner is returned when a signature is not from the module's owner
+	ErrNotOwner = errors.New("signature is not from the module owner")
+	// ErrQuotaExceeded is returned when the registry or an owner holds as
+	// many modules as the quota allows
+	ErrQuotaExceeded = errors.New("wasm module quota exceeded")
+)
+
+// Quota bounds the modules the registry keeps. Any signer may register, so
+// Total is what bounds the disk used: at most Total * MaxModuleSize bytes.
+type Quota struct {
+	PerOwner int `mapstructure:"per_owner"`
+	Total    int `mapstructure:"total"`
+}
+
+// DefaultQuota allows 8 modules per owner and 256 in all
+func DefaultQuota() Quota {
+	return Quota{PerOwner: 8, Total: 256}
+}
+
+func (q Quota) withDefaults() Quota {
+	d := DefaultQuota()
+	if q.PerOwner <= 0 {
+		q.PerOwner = d.PerOwner
+	}
+	if q.Total <= 0 {
+		q.Total = d.Total
+	}
+	return q
+}
+
+// Module describes a registered function. The code itself is only handed to
+// the runtime.
+type Module struct {
+	Hash      string    `json:"hash"`
+	Owner     string    `json:"owner"`
+	Signature string    `json:"signature"`
+	Exports   []string  `json:"exports"`
+	Size      int       `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ModuleHash is the hex SHA-256 a module is addressed by
+func ModuleHash(code []byte) string {
+	sum := sha256.Sum256(code)
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeHash lowercases a module hash and strips any 0x prefix, rejecting
+// anything that is not a SHA-256
+func NormalizeHash(hash string) (string, error) {
+	h := strings.ToLower(strings.TrimPrefix(hash, "0x"))
+	if b, err := hex.DecodeString(h); err != nil || len(b) != sha256.Size {
+		return "", fmt.Errorf("%q is not a SHA-256 module hash", hash)
+	}
+	return h, nil
+}
+
+// RegisterMessage is what an owner signs (EIP-191 personal_sign) to register
+// a module
+func RegisterMessage(hash string) string {
+	return "Register Obscura function " + hash
+}
+
+// DeleteMessage is what an owner signs to delete a module
+func DeleteMessage(hash string) string {
+	return "Delete Obscura function " + hash
+}
+
+// Registry stores WASM modules in a directory of their own, addressed by the
+// SHA-256 of their bytes, so a job naming a hash always runs exactly that
+// code. Each module is a <hash>.wasm file next to its <hash>.json metadata,
+// which is written last and marks the module registered. Modules are
+// compiled when registered and stay compiled in the compute manager.
+type Registry struct {
+	mu      sync.Mutex
+	dir     string
+	compute *ComputeManager
+	allowed map[string]bool
+	quota   Quota
+}
+
+// NewRegistry creates a registry keeping modules in dir that compiles with cm
+func NewRegistry(dir string, cm *ComputeManager) (*Registry, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create module directory: %w", err)
+	}
+	r := &Registry{dir: dir, compute: cm, quota: DefaultQuota()}
+	r.SetAllowedExports(DefaultAllowedExports)
+	return r, nil
+}
+
+// SetAllowedExports replaces the functions modules may export. Modules that
+// are already registered are not rechecked.
+func (r *Registry) SetAllowedExports(names []string) {
+	r.allowed = make(map[string]bool, len(names))
+	for _, name := range names {
+		r.allowed[name] = true
+	}
+}
+
+// SetQuota replaces the module quota. Modules that are already registered
+// are kept.
+func (r *Registry) SetQuota(q Quota) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.quota = q.withDefaults()
+}
+
+// Register validates, compiles and stores a module signed by owner. Registering
+// the same module again returns the existing entry.
+func (r *Registry) Register(ctx context.Context, code []byte, owner, signature string) (Module, error) {
+	if len(code) == 0 || len(code) > MaxModuleSize {
+		return Module{}, fmt.Errorf("%w: size must be between 1 and %d bytes", ErrInvalidModule, MaxModuleSize)
+	}
+	hash := ModuleHash(code)
+	if err := verifyOwner(owner, signature, RegisterMessage(hash)); err != nil {
+		return Module{}, err
+	}
+	owner = common.HexToAddress(owner).Hex()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.Module(hash); ok {
+		return existing, nil
+	}
+	if err := r.checkQuota(owner); err != nil {
+		return Module{}, err
+	}
+
+	exports, err := r.compute.Exports(ctx, code)
+	if err != nil {
+		return Module{}, fmt.Errorf("%w: %v", ErrInvalidModule, err)
+	}
+	m := Module{
+		Hash:      hash,
+		Owner:     owner,
+		Signature: signature,
+		Exports:   exports,
+		Size:      len(code),
+		CreatedAt: time.Now().UTC(),
+	}
+	// Only registered modules stay compiled
+	if err := r.checkExports(exports); err != nil {
+		r.compute.Evict(ctx, hash)
+		return Module{}, err
+	}
+	if err := r.save(m, code); err != nil {
+		r.compute.Evict(ctx, hash)
+		return Module{}, err
+	}
+	return m, nil
+}
+
+func (r *Registry) checkQuota(owner string) error {
+	modules := r.List()
+	if len(modules) >= r.quota.Total {
+		return fmt.Errorf("%w: the registry holds %d modules", ErrQuotaExceeded, r.quota.Total)
+	}
+	owned := 0
+	for _, m := range modules {
+		if m.Owner == owner {
+			owned++
+		}
+	}
+	if owned >= r.quota.PerOwner {
+		return fmt.Errorf("%w: %s owns %d modules", ErrQuotaExceeded, owner, r.quota.PerOwner)
+	}
+	return nil
+}
+
+func (r *Registry) checkExports(exports []string) error {
+	if len(exports) == 0 {
+		return fmt.Errorf("%w: module exports no functions", ErrInvalidModule)
+	}
+	for _, name := range exports {
+		if !r.allowed[name] {
+			return fmt.Errorf("%w: export %q is not allowed", ErrInvalidModule, name)
+		}
+	}
+	return nil
+}
+
+// save writes the code, then the metadata that marks it registered
+func (r *Registry) save(m Module, code []byte) error {
+	meta, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(r.codePath(m.Hash), code); err != nil {
+		return fmt.Errorf("failed to store module: %w", err)
+	}
+	if err := writeFileAtomic(r.metaPath(m.Hash), meta); err != nil {
+		os.Remove(r.codePath(m.Hash))
+		return fmt.Errorf("failed to store module: %w", err)
+	}
+	return nil
+}
+
+// Get returns the module stored under hash, checking that its bytes still
+// hash to it
+func (r *Registry) Get(hash string) ([]byte, error) {
+	hash, err := NormalizeHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := r.Module(hash); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrModuleNotFound, hash)
+	}
+	code, err := os.ReadFile(r.codePath(hash))
+	if err != nil || ModuleHash(code) != hash {
+		return nil, fmt.Errorf("stored module %s is corrupt", hash)
+	}
+	return code, nil
+}
+
+// Module returns the metadata of a registered module
+func (r *Registry) Module(hash string) (Module, bool) {
+	hash, err := NormalizeHash(hash)
+	if err != nil {
+		return Module{}, false
+	}
+	return r.readModule(r.metaPath(hash))
+}
+
+// List returns every registered module, oldest first
+func (r *Registry) List() []Module {
+	paths, _ := filepath.Glob(filepath.Join(r.dir, "*.json"))
+	var modules []Module
+	for _, path := range paths {
+		if m, ok := r.readModule(path); ok {
+			modules = append(modules, m)
+		}
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].CreatedAt.Before(modules[j].CreatedAt) })
+	return modules
+}
+
+// Delete removes a module when signature is its owner's over DeleteMessage
+func (r *Registry) Delete(ctx context.Context, hash, signature string) error {
+	m, ok := r.Module(hash)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrModuleNotFound, hash)
+	}
+	if err := verifyOwner(m.Owner, signature, DeleteMessage(m.Hash)); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := os.Remove(r.metaPath(m.Hash)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	os.Remove(r.codePath(m.Hash))
+	r.compute.Evict(ctx, m.Hash)
+	return nil
+}
+
+func (r *Registry) codePath(hash string) string {
+	return filepath.Join(r.dir, hash+".wasm")
+}
+
+func (r *Registry) metaPath(hash string) string {
+	return filepath.Join(r.dir, hash+".json")
+}
+
+func (r *Registry) readModule(path string) (Module, bool) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Module{}, false
+	}
+	var m Module
+	if err := json.Unmarshal(raw, &m); err != nil || m.Hash == "" {
+		return Module{}, false
+	}
+	return m, true
+}
+
+// writeFileAtomic replaces path with data through a temporary file
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// verifyOwner checks an EIP-191 signature over message against owner
+func verifyOwner(owner, signature, message string) error {
+	if !common.IsHexAddress(owner) {
+		return fmt.Errorf("%w: owner %q is not an address", ErrNotOwner, owner)
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return fmt.Errorf("%w: malformed signature", ErrNotOwner)
+	}
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte(message)), sig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != common.HexToAddress(owner) {
+		return ErrNotOwner
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package functions
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// addModule exports run(i64, i64) i64 returning the sum of its arguments
+var addModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	0x01, 0x07, 0x01, 0x60, 0x02, 0x7e, 0x7e, 0x01, 0x7e,
+	0x03, 0x02, 0x01, 0x00,
+	0x07, 0x07, 0x01, 0x03, 'r', 'u', 'n', 0x00, 0x00,
+	0x0a, 0x09, 0x01, 0x07, 0x00, 0x20, 0x00, 0x20, 0x01, 0x7c, 0x0b,
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, message string) string {
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27 // as wallets return it
+	return hexutil.Encode(sig)
+}
+
+func TestRegistryRequiresOwnerSignatures(t *testing.T) {
+	ctx := context.Background()
+	cm, err := NewComputeManager(ctx, t.TempDir(), DefaultSandbox())
+	if err != nil {
+		t.Fatalf("Failed to start wasm runtime: %v", err)
+	}
+	defer cm.Close(ctx)
+
+	dir := t.TempDir()
+	owner, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	ownerAddr := crypto.PubkeyToAddress(owner.PublicKey).Hex()
+	hash := ModuleHash(addModule)
+
+	registry := newTestRegistry(t, dir, cm)
+	if _, err := registry.Register(ctx, addModule, ownerAddr, sign(t, other, RegisterMessage(hash))); !errors.Is(err, ErrNotOwner) {
+		t.Errorf("Expected a signature from another key to be rejected, got %v", err)
+	}
+	m, err := registry.Register(ctx, addModule, ownerAddr, sign(t, owner, RegisterMessage(hash)))
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if m.Hash != hash || m.Owner != ownerAddr || len(m.Exports) != 1 || m.Exports[0] != "run" {
+		t.Errorf("Unexpected module %+v", m)
+	}
+
+	// Modules survive a restart
+	reloaded := newTestRegistry(t, dir, cm)
+	code, err := reloaded.Get("0x" + hash)
+	if err != nil || string(code) != string(addModule) {
+		t.Fatalf("Expected the stored module back, got %v", err)
+	}
+	if list := reloaded.List(); len(list) != 1 || list[0].Hash != hash {
+		t.Errorf("Expected one listed module, got %+v", list)
+	}
+
+	if err := registry.Delete(ctx, hash, sign(t, other, DeleteMessage(hash))); !errors.Is(err, ErrNotOwner) {
+		t.Errorf("Expected only the owner to delete, got %v", err)
+	}
+	if err := registry.Delete(ctx, hash, sign(t, owner, DeleteMessage(hash))); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := registry.Get(hash); !errors.Is(err, ErrModuleNotFound) {
+		t.Errorf("Expected a deleted module to be gone, got %v", err)
+	}
+	if len(registry.List()) != 0 {
+		t.Error("Expected a deleted module to be unlisted")
+	}
+}
+
+func TestRegistryValidatesExports(t *testing.T) {
+	ctx := context.Background()
+	cm, err := NewComputeManager(ctx, "", DefaultSandbox())
+	if err != nil {
+		t.Fatalf("Failed to start wasm runtime: %v", err)
+	}
+	defer cm.Close(ctx)
+
+	owner, _ := crypto.GenerateKey()
+	ownerAddr := crypto.PubkeyToAddress(owner.PublicKey).Hex()
+	registry := newTestRegistry(t, t.TempDir(), cm)
+	registry.SetAllowedExports([]string{"score"})
+
+	hash := ModuleHash(addModule)
+	if _, err := registry.Register(ctx, addModule, ownerAddr, sign(t, owner, RegisterMessage(hash))); !errors.Is(err, ErrInvalidModule) {
+		t.Errorf("Expected an export outside the allowlist to be rejected, got %v", err)
+	}
+	if cm.isCompiled(hash) {
+		t.Error("Expected a rejected module to be evicted")
+	}
+
+	// A module exporting nothing is rejected and evicted too
+	empty := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	if _, err := registry.Register(ctx, empty, ownerAddr, sign(t, owner, RegisterMessage(ModuleHash(empty)))); !errors.Is(err, ErrInvalidModule) {
+		t.Errorf("Expected a module without exports to be rejected, got %v", err)
+	}
+	if cm.isCompiled(ModuleHash(empty)) {
+		t.Error("Expected a module without exports to be evicted")
+	}
+
+	garbage := []byte("not wasm")
+	if _, err := registry.Register(ctx, garbage, ownerAddr, sign(t, owner, RegisterMessage(ModuleHash(garbage)))); !errors.Is(err, ErrInvalidModule) {
+		t.Errorf("Expected a module that does not compile to be rejected, got %v", err)
+	}
+}
+
+func TestRegistryEnforcesQuota(t *testing.T) {
+	ctx := context.Background()
+	cm, err := NewComputeManager(ctx, "", DefaultSandbox())
+	if err != nil {
+		t.Fatalf("Failed to start wasm runtime: %v", err)
+	}
+	defer cm.Close(ctx)
+
+	registry := newTestRegistry(t, t.TempDir(), cm)
+	registry.SetQuota(Quota{PerOwner: 1, Total: 2})
+	register := func(key *ecdsa.PrivateKey, code []byte) error {
+		_, err := registry.Register(ctx, code, crypto.PubkeyToAddress(key.PublicKey).Hex(), sign(t, key, RegisterMessage(ModuleHash(code))))
+		return err
+	}
+	a, _ := crypto.GenerateKey()
+	b, _ := crypto.GenerateKey()
+	c, _ := crypto.GenerateKey()
+
+	if err := register(a, addModule); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := register(a, memoryModule(1)); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected a second module from one owner to be refused, got %v", err)
+	}
+	if err := register(a, addModule); err != nil {
+		t.Errorf("Expected re-registering a stored module to succeed, got %v", err)
+	}
+	if err := register(b, memoryModule(1)); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := register(c, memoryModule(2)); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected a full registry to refuse modules, got %v", err)
+	}
+}
+
+func newTestRegistry(t *testing.T, dir string, cm *ComputeManager) *Registry {
+	t.Helper()
+	registry, err := NewRegistry(dir, cm)
+	if err != nil {
+		t.Fatalf("Failed to open registry: %v", err)
+	}
+	return registry
+}
+
+func (cm *ComputeManager) isCompiled(hash string) bool {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	_, ok := cm.compiled[hash]
+	return ok
+}
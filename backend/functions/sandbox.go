@@ -0,0 +1,86 @@
+package functions
+
+import (
+	"crypto/rand"
+	"errors"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+)
+
+// ErrBudgetExceeded is returned when a function runs out of fuel or past its
+// time budget
+var ErrBudgetExceeded = errors.New("wasm execution budget exceeded")
+
+// Sandbox bounds what a function may use while it runs. By default a
+// function sees no filesystem, a fake clock and a fixed random stream, so the
+// same module and inputs give the same result on every node. Each grant
+// trades that away and should only be given to functions whose results are
+// not compared across nodes.
+type Sandbox struct {
+	// MemoryPages caps each instance's linear memory, in 64 KiB pages
+	MemoryPages uint32 `mapstructure:"memory_pages"`
+	// Timeout is the wall-clock budget of one call; a function still running
+	// when it expires is closed, loops included
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Fuel is the instruction budget of one call, start functions included.
+	// Unlike Timeout it runs out at the same instruction on every node, however
+	// fast, so a call that exceeds it fails the same way everywhere.
+	Fuel uint64 `mapstructure:"fuel"`
+
+	// Filesystem is a host directory mounted read-only at /
+	Filesystem string `mapstructure:"filesystem"`
+	// Clock gives the host's wall and monotonic clocks and a real sleep
+	Clock bool `mapstructure:"clock"`
+	// Random gives crypto/rand in place of the fixed stream
+	Random bool `mapstructure:"random"`
+}
+
+// DefaultSandbox allows 16 MiB of memory, a billion instructions and 5
+// seconds per call, with no grants
+func DefaultSandbox() Sandbox {
+	return Sandbox{MemoryPages: 256, Timeout: 5 * time.Second, Fuel: 1_000_000_000}
+}
+
+// Deterministic reports whether results do not depend on the node
+func (s Sandbox) Deterministic() bool {
+	return s.Filesystem == "" && !s.Clock && !s.Random
+}
+
+func (s Sandbox) withDefaults() Sandbox {
+	d := DefaultSandbox()
+	if s.MemoryPages == 0 {
+		s.MemoryPages = d.MemoryPages
+	}
+	if s.Timeout <= 0 {
+		s.Timeout = d.Timeout
+	}
+	if s.Fuel == 0 {
+		s.Fuel = d.Fuel
+	}
+	return s
+}
+
+// runtimeConfig applies the limits that wazero enforces per runtime
+func (s Sandbox) runtimeConfig() wazero.RuntimeConfig {
+	return wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(s.MemoryPages).
+		WithCloseOnContextDone(true)
+}
+
+// moduleConfig grants an instance only what the sandbox allows. Output
+// streams are discarded: a function could otherwise print private inputs.
+// Start functions are left to Invoke, which runs them metered.
+func (s Sandbox) moduleConfig() wazero.ModuleConfig {
+	config := wazero.NewModuleConfig().WithName("").WithStartFunctions()
+	if s.Filesystem != "" {
+		config = config.WithFSConfig(wazero.NewFSConfig().WithReadOnlyDirMount(s.Filesystem, "/"))
+	}
+	if s.Clock {
+		config = config.WithSysWalltime().WithSysNanotime().WithSysNanosleep()
+	}
+	if s.Random {
+		config = config.WithRandSource(rand.Reader)
+	}
+	return config
+}
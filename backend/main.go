@@ -5,7 +5,6 @@ import (
 
 	"github.com/joho/godotenv"
 	"github.com/obscura-network/obscura-node/node"
-	"github.com/obscura-network/obscura-node/zkp"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -21,15 +20,8 @@ func main() {
 		log.Warn().Msg("No .env file found, using defaults/environment variables")
 	}
 
-	// Initialize ZK Circuits (Legacy/Core support)
-	if err := zkp.Init(); err != nil {
-		log.Warn().Err(err).Msg("Failed to initialize ZKP circuits (Running in Mock Mode)")
-	} else {
-		log.Info().Msg("ZKP Circuits initialized")
-	}
-
 	// Initialize Core Node (New Architecture)
-	// Configuration is handled via Viper in NewNode()
+	// Configuration is handled via Viper in NewNode(), which also loads the ZK keys
 	obscuraNode, err := node.NewNode()
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to initialize Obscura Node")
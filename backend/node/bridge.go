@@ -0,0 +1,125 @@
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/rs/zerolog/log"
+
+	"github.com/obscura-network/obscura-node/chains"
+	"github.com/obscura-network/obscura-node/chains/evm"
+	"github.com/obscura-network/obscura-node/crosschain"
+	"github.com/obscura-network/obscura-node/storage"
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+// BridgeChain is a chain with an ObscuraBridge deployment that CrossLink
+// relays messages from and delivers them to
+type BridgeChain struct {
+	Name               string `mapstructure:"name"`
+	ChainID            uint64 `mapstructure:"chain_id"`
+	RPCURL             string `mapstructure:"rpc_url"`
+	WebSocketURL       string `mapstructure:"ws_url"`
+	BridgeContract     string `mapstructure:"bridge_contract"`
+	ConfirmationBlocks uint64 `mapstructure:"confirmation_blocks"`
+}
+
+// BridgeConfig makes the node a member of the bridge signing committee.
+// Committee lists every member's public key in committee order, CommitteeKey
+// is this node's member key and Peers are the API URLs of the other members.
+type BridgeConfig struct {
+	Relay        bool          `mapstructure:"relay"`
+	Committee    []string      `mapstructure:"committee"`
+	CommitteeKey string        `mapstructure:"committee_key"`
+	Peers        []string      `mapstructure:"peers"`
+	Chains       []BridgeChain `mapstructure:"chains"`
+}
+
+// newBridge connects the bridge chains and builds the committee signer.
+// Without chains the relayer stays idle.
+func newBridge(ctx context.Context, cfg BridgeConfig, store storage.Store, privateKey string) (*crosschain.CrossLink, error) {
+	if len(cfg.Chains) == 0 {
+		return crosschain.NewCrossLink(store, nil), nil
+	}
+
+	committee, err := newBridgeCommittee(cfg)
+	if err != nil {
+		return nil, err
+	}
+	crosslink := crosschain.NewCrossLink(store, committee)
+	crosslink.SetRelay(cfg.Relay)
+
+	for _, c := range cfg.Chains {
+		if c.BridgeContract == "" {
+			return nil, fmt.Errorf("bridge chain %s: bridge_contract is required", c.Name)
+		}
+		adapter, err := evm.NewEVMAdapter(&chains.ChainConfig{
+			Name:               c.Name,
+			ChainID:            c.ChainID,
+			RPCURL:             c.RPCURL,
+			WebSocketURL:       c.WebSocketURL,
+			BridgeContract:     c.BridgeContract,
+			ConfirmationBlocks: c.ConfirmationBlocks,
+			GasStrategy:        chains.GasStrategyLegacy,
+			IsEnabled:          true,
+		}, privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("bridge chain %s: %w", c.Name, err)
+		}
+		if err := adapter.Connect(ctx); err != nil {
+			return nil, fmt.Errorf("bridge chain %s: %w", c.Name, err)
+		}
+		crosslink.RegisterChain(adapter)
+	}
+
+	log.Info().
+		Int("chains", len(cfg.Chains)).
+		Int("peers", len(cfg.Peers)).
+		Bool("relay", cfg.Relay).
+		Str("committee_root", committee.CommitteeRoot().String()).
+		Msg("Bridge committee configured; ObscuraBridge deployments must trust this root")
+	return crosslink, nil
+}
+
+// newBridgeCommittee parses the committee keys and points it at the peers
+func newBridgeCommittee(cfg BridgeConfig) (*crosschain.Committee, error) {
+	if len(cfg.Committee) == 0 || cfg.CommitteeKey == "" {
+		return nil, fmt.Errorf("bridge.committee and bridge.committee_key are required with bridge chains")
+	}
+	if len(cfg.Peers)+1 < zkp.BridgeQuorum {
+		return nil, fmt.Errorf("bridge.peers must list at least %d other members to reach the signing quorum", zkp.BridgeQuorum-1)
+	}
+	members := make([]eddsa.PublicKey, len(cfg.Committee))
+	for i, s := range cfg.Committee {
+		pub, err := crosschain.ParseMemberKey(s)
+		if err != nil {
+			return nil, fmt.Errorf("bridge.committee[%d]: %w", i, err)
+		}
+		members[i] = pub
+	}
+
+	key, err := crosschain.ParseCommitteeKey(cfg.CommitteeKey)
+	if err != nil {
+		return nil, fmt.Errorf("bridge.committee_key: %w", err)
+	}
+	index := -1
+	for i := range members {
+		if key.PublicKey.Equal(&members[i]) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("bridge.committee_key is not a member of bridge.committee")
+	}
+
+	committee, err := crosschain.NewCommittee(members, map[int]*eddsa.PrivateKey{index: key})
+	if err != nil {
+		return nil, err
+	}
+	if err := committee.SetPeers(cfg.Peers); err != nil {
+		return nil, err
+	}
+	return committee, nil
+}
@@ -0,0 +1,40 @@
+package node
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+)
+
+func TestBridgeCommitteeConfig(t *testing.T) {
+	keys := make([]*eddsa.PrivateKey, 4)
+	members := make([]string, len(keys))
+	for i := range keys {
+		keys[i], _ = eddsa.GenerateKey(rand.Reader)
+		members[i] = hex.EncodeToString(keys[i].PublicKey.Bytes())
+	}
+	peers := []string{"https://node-a:8080", "https://node-b:8080"}
+
+	cfg := BridgeConfig{Committee: members, CommitteeKey: hex.EncodeToString(keys[2].Bytes()), Peers: peers}
+	committee, err := newBridgeCommittee(cfg)
+	if err != nil {
+		t.Fatalf("newBridgeCommittee failed: %v", err)
+	}
+	if committee.CommitteeRoot().Sign() == 0 {
+		t.Error("Expected a committee root")
+	}
+
+	outsider, _ := eddsa.GenerateKey(rand.Reader)
+	cfg.CommitteeKey = hex.EncodeToString(outsider.Bytes())
+	if _, err := newBridgeCommittee(cfg); err == nil {
+		t.Error("Expected a key outside the committee to be rejected")
+	}
+
+	cfg.CommitteeKey = hex.EncodeToString(keys[2].Bytes())
+	cfg.Peers = peers[:1]
+	if _, err := newBridgeCommittee(cfg); err == nil {
+		t.Error("Expected a committee that cannot reach quorum to be rejected")
+	}
+}
@@ -0,0 +1,179 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/obscura-network/obscura-node/api"
+	"github.com/obscura-network/obscura-node/functions"
+	"github.com/obscura-network/obscura-node/oracle"
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+// SetFunctionRegistry enables COMPUTE jobs, which run modules from the registry
+func (jm *JobManager) SetFunctionRegistry(r *functions.Registry) {
+	jm.functions = r
+}
+
+func computeRequestFromJob(job oracle.JobRequest) oracle.ComputeRequest {
+	function, _ := job.Params["function"].(string)
+	entrypoint, _ := job.Params["entrypoint"].(string)
+	if entrypoint == "" {
+		entrypoint = oracle.DefaultEntrypoint
+	}
+	decimals, _ := toInt(job.Params["decimals"])
+
+	req := oracle.ComputeRequest{
+		Function:   function,
+		Entrypoint: entrypoint,
+		Decimals:   decimals,
+		Threshold:  toBigInt(job.Params["threshold"]),
+	}
+	switch inputs := job.Params["inputs"].(type) {
+	case []oracle.DataSource:
+		req.Inputs = inputs
+	case []interface{}:
+		// Jobs reloaded from the store carry their inputs as generic maps,
+		// keyed by DataSource field names when they came from an event
+		str := func(m map[string]interface{}, keys ...string) string {
+			for _, key := range keys {
+				if s, ok := m[key].(string); ok {
+					return s
+				}
+			}
+			return ""
+		}
+		for _, in := range inputs {
+			m, _ := in.(map[string]interface{})
+			req.Inputs = append(req.Inputs, oracle.DataSource{URL: str(m, "url", "URL"), Path: str(m, "path", "Path")})
+		}
+	}
+	return req
+}
+
+// handleCompute runs a registered WASM function over private inputs and
+// proves whether its output is at least the job's threshold. Only the
+// threshold and the outcome leave the node; the inputs and the output are
+// never logged.
+func (jm *JobManager) handleCompute(ctx context.Context, job oracle.JobRequest) error {
+	req := computeRequestFromJob(job)
+	if err := req.Validate(); err != nil {
+		return permanent(err)
+	}
+	if jm.functions == nil || jm.computeMgr == nil {
+		return permanent(fmt.Errorf("compute jobs are not enabled on this node"))
+	}
+	output, err := jm.runFunction(ctx, job.ID, req)
+	if err != nil {
+		return err
+	}
+
+	if jm.consensus != nil {
+		if err := jm.agreeOnOutput(ctx, job.ID, output.Uint64()); err != nil {
+			return err
+		}
+	}
+
+	// Prove whether output >= threshold without revealing output. Both
+	// outcomes are posted so the request never stays pending.
+	satisfied := output.Cmp(req.Threshold) >= 0
+	tag, err := jm.circuitVersion(ctx, "private", jm.oracleAddr)
+	if err != nil {
+		return err
+	}
+	jm.trackState(job.ID, oracle.JobStateProving)
+	proof, err := zkp.GeneratePrivateComputationProofContext(ctx, output, req.Threshold)
+	if err != nil {
+		return proofFailure(fmt.Errorf("private ZK proof generation failed: %w", err))
+	}
+	serialized, err := zkp.SerializeProof(proof)
+	if err != nil {
+		return permanent(fmt.Errorf("proof serialization failed: %w", err))
+	}
+
+	// The oracle takes the threshold from the request and the outcome from
+	// the call, and checks the proof against both
+	reqID, ok := new(big.Int).SetString(job.ID, 10)
+	if !ok {
+		return permanent(fmt.Errorf("invalid request ID %q", job.ID))
+	}
+	data, err := jm.oracleABI.Pack("fulfillCompute", reqID, satisfied, serialized)
+	if err != nil {
+		return permanent(fmt.Errorf("failed to pack fulfillCompute: %w", err))
+	}
+	txHash, err := jm.sendToJobs(ctx, []string{job.ID}, jm.oracleAddr, data, tag)
+	if err != nil {
+		return err
+	}
+
+	status := "Proven"
+	if !satisfied {
+		status = "Not Satisfied"
+	}
+	log.Info().
+		Str("job_id", job.ID).
+		Str("tx_hash", txHash.Hex()).
+		Bool("satisfied", satisfied).
+		Int("circuit_version", tag.Version).
+		Msg("Confidential Compute Proof Generated and Dispatched")
+	jm.addComputeRecord(job.ID, req.Function, status, txHash.Hex())
+	return nil
+}
+
+// runFunction fetches the private inputs with vault credentials and calls
+// the function's entrypoint with them, returning its first result
+func (jm *JobManager) runFunction(ctx context.Context, jobID string, req oracle.ComputeRequest) (*big.Int, error) {
+	code, err := jm.functions.Get(req.Function)
+	if err != nil {
+		return nil, permanent(fmt.Errorf("failed to load function: %w", err))
+	}
+
+	jm.trackState(jobID, oracle.JobStateFetching)
+	params := make([]uint64, len(req.Inputs))
+	for i, src := range req.Inputs {
+		raw, err := jm.fetchSource(src)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch private input %d: %w", i, err)
+		}
+		value, err := oracle.ScaleValue(raw, req.Decimals)
+		if err != nil {
+			return nil, permanent(fmt.Errorf("input %d: %w", i, err))
+		}
+		if !value.IsUint64() {
+			return nil, permanent(fmt.Errorf("input %d does not fit in a uint64 at %d decimals", i, req.Decimals))
+		}
+		params[i] = value.Uint64()
+	}
+
+	log.Info().Str("job_id", jobID).Str("function", req.Function).Msg("Executing Confidential Computation...")
+	results, err := jm.computeMgr.ExecuteWasm(ctx, code, req.Entrypoint, params)
+	if err != nil {
+		// The job was cancelled, not failed by the function; retry it
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, permanent(fmt.Errorf("function %s failed: %w", req.Function, err))
+	}
+	if len(results) == 0 {
+		return nil, permanent(fmt.Errorf("function %s returned no result", req.Function))
+	}
+	return new(big.Int).SetUint64(results[0]), nil
+}
+
+func (jm *JobManager) addComputeRecord(jobID, function, status, hash string) {
+	if jm.metrics == nil {
+		return
+	}
+	jm.metrics.AddJobRecord(api.JobRecord{
+		ID:        jobID,
+		Type:      "Private Compute",
+		Target:    function,
+		Status:    status,
+		Hash:      hash,
+		Timestamp: time.Now(),
+	})
+}
@@ -0,0 +1,202 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog/log"
+
+	ocr "github.com/obscura-network/obscura-node/consensus"
+	"github.com/obscura-network/obscura-node/functions"
+)
+
+// ComputeConsensusPath is where nodes post compute commits and reveals
+const ComputeConsensusPath = "/api/compute/consensus"
+
+// computePenalty is the reputation a node loses for a divergent or missing
+// compute reveal
+const computePenalty = 5.0
+
+// ComputePeer is another node in the compute committee
+type ComputePeer struct {
+	NodeID string `mapstructure:"node_id"` // address of the peer's signing key
+	URL    string `mapstructure:"url"`
+}
+
+// ComputeConsensusConfig runs every COMPUTE job on a committee of nodes and
+// fulfills only when at least Threshold of them report the same output
+type ComputeConsensusConfig struct {
+	Threshold int           `mapstructure:"threshold"`
+	Timeout   time.Duration `mapstructure:"timeout"`
+	Peers     []ComputePeer `mapstructure:"peers"`
+}
+
+// checkComputeDeterminism refuses settings under which honest peers could
+// compute different outputs for the same job
+func checkComputeDeterminism(sandbox functions.Sandbox, host functions.Host) error {
+	if !sandbox.Deterministic() {
+		return errors.New("function_sandbox must not grant filesystem, clock or random access")
+	}
+	if !host.Deterministic() {
+		return errors.New("feed_read and http_fetch must be disabled, with an empty function_http_allowlist: their results differ between nodes")
+	}
+	return nil
+}
+
+// newComputeConsensus builds the OCR manager that signs and tallies compute
+// rounds with the node's key
+func newComputeConsensus(cfg ComputeConsensusConfig, privateKey string) (*ocr.OCRManager, error) {
+	if cfg.Threshold < 2 || cfg.Threshold > len(cfg.Peers)+1 {
+		return nil, fmt.Errorf("compute_consensus.threshold must be between 2 and %d", len(cfg.Peers)+1)
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(privateKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	ocrCfg := ocr.DefaultOCRConfig()
+	ocrCfg.Threshold = cfg.Threshold
+	mgr, err := ocr.NewOCRManager(ocrCfg, key)
+	if err != nil {
+		return nil, err
+	}
+	for _, peer := range cfg.Peers {
+		if !common.IsHexAddress(peer.NodeID) {
+			return nil, fmt.Errorf("compute_consensus peer %q: node_id must be an address", peer.URL)
+		}
+		if err := mgr.RegisterNode(&ocr.OCRNode{ID: common.HexToAddress(peer.NodeID).Hex(), IsActive: true, LastSeen: time.Now()}); err != nil {
+			return nil, err
+		}
+	}
+
+	transport, err := newHTTPComputeTransport(cfg.Peers)
+	if err != nil {
+		return nil, err
+	}
+	mgr.SetComputeTransport(transport)
+	return mgr, nil
+}
+
+// httpComputeTransport posts compute messages to each peer's API
+type httpComputeTransport struct {
+	urls   []string
+	client *http.Client
+}
+
+func newHTTPComputeTransport(peers []ComputePeer) (*httpComputeTransport, error) {
+	urls := make([]string, len(peers))
+	for i, peer := range peers {
+		if !strings.HasPrefix(peer.URL, "http://") && !strings.HasPrefix(peer.URL, "https://") {
+			return nil, fmt.Errorf("compute_consensus peer %q must be an http(s) URL", peer.URL)
+		}
+		if strings.HasPrefix(peer.URL, "http://") {
+			log.Warn().Str("peer", peer.URL).Msg("Compute peer is not using TLS; reveals include function outputs")
+		}
+		urls[i] = strings.TrimRight(peer.URL, "/") + ComputeConsensusPath
+	}
+	return &httpComputeTransport{urls: urls, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// Broadcast sends msg to every peer and fails only if none accepted it
+func (t *httpComputeTransport) Broadcast(ctx context.Context, msg ocr.ComputeMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, url := range t.urls {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			if err := t.post(ctx, url, body); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", url, err))
+				mu.Unlock()
+			}
+		}(url)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 && len(errs) == len(t.urls) {
+		return errors.Join(errs...)
+	}
+	for _, err := range errs {
+		log.Warn().Err(err).Str("job_id", msg.JobID).Msg("Compute peer did not accept message")
+	}
+	return nil
+}
+
+func (t *httpComputeTransport) post(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SetComputeConsensus makes COMPUTE jobs agree on their output with the
+// committee before anything is proven or posted
+func (jm *JobManager) SetComputeConsensus(mgr *ocr.OCRManager, timeout time.Duration) {
+	jm.consensus = mgr
+	jm.consensusTimeout = timeout
+}
+
+// agreeOnOutput runs a commit/reveal round over output and charges
+// reputation to nodes whose reveal diverged or never came. It fails when no
+// Threshold of nodes agree or when the local output is not the agreed one.
+func (jm *JobManager) agreeOnOutput(ctx context.Context, jobID string, output uint64) error {
+	encoded := binary.BigEndian.AppendUint64(nil, output)
+	outcome, err := jm.consensus.RunComputeRound(ctx, jobID, encoded, jm.consensusTimeout)
+	if err != nil {
+		// Peers may not have run the job yet; a retry starts a new round
+		return fmt.Errorf("compute consensus failed: %w", err)
+	}
+
+	local := jm.consensus.LocalNodeID()
+	for _, nodeID := range append(outcome.Divergent, outcome.Missing...) {
+		if nodeID == local {
+			continue
+		}
+		if jm.repMgr != nil {
+			jm.repMgr.UpdateReputation(nodeID, -computePenalty)
+		}
+		log.Warn().Str("job_id", jobID).Str("node", nodeID).Msg("Compute node diverged from the committee")
+	}
+
+	if outcome.Output == nil {
+		return permanent(fmt.Errorf("compute outputs did not reach agreement: %d agreeing, %d divergent, %d missing",
+			len(outcome.Agreeing), len(outcome.Divergent), len(outcome.Missing)))
+	}
+	if !bytes.Equal(outcome.Output, encoded) {
+		if jm.repMgr != nil {
+			jm.repMgr.UpdateReputation("self", -computePenalty)
+		}
+		return permanent(fmt.Errorf("local compute output diverged from the committee"))
+	}
+	log.Info().Str("job_id", jobID).Int("agreeing", len(outcome.Agreeing)).Msg("Compute committee agreed on output")
+	return nil
+}
@@ -0,0 +1,119 @@
+package node
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	ocr "github.com/obscura-network/obscura-node/consensus"
+	"github.com/obscura-network/obscura-node/functions"
+	"github.com/obscura-network/obscura-node/oracle"
+	"github.com/obscura-network/obscura-node/security"
+)
+
+func TestComputeConsensusPenalizesDivergentNode(t *testing.T) {
+	const size = 3
+	keys := make([]string, size)
+	ids := make([]string, size)
+	managers := make([]*ocr.OCRManager, size)
+	servers := make([]*httptest.Server, size)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = hex.EncodeToString(crypto.FromECDSA(key))
+		ids[i] = crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+		i := i
+		servers[i] = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var msg ocr.ComputeMessage
+			if err := json.NewDecoder(r.Body).Decode(&msg); err != nil || managers[i].HandleComputeMessage(msg) != nil {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer servers[i].Close()
+	}
+
+	jms := make([]*JobManager, size)
+	for i := range jms {
+		cfg := ComputeConsensusConfig{Threshold: 2}
+		for j := range servers {
+			if j != i {
+				cfg.Peers = append(cfg.Peers, ComputePeer{NodeID: ids[j], URL: servers[j].URL})
+			}
+		}
+		mgr, err := newComputeConsensus(cfg, keys[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		managers[i] = mgr
+		jms[i] = &JobManager{repMgr: security.NewReputationManager()}
+		jms[i].SetComputeConsensus(mgr, 2*time.Second)
+	}
+
+	outputs := []uint64{42, 42, 41}
+	errs := make([]error, size)
+	var wg sync.WaitGroup
+	for i, jm := range jms {
+		wg.Add(1)
+		go func(i int, jm *JobManager) {
+			defer wg.Done()
+			errs[i] = jm.agreeOnOutput(context.Background(), "job-1", outputs[i])
+		}(i, jm)
+	}
+	wg.Wait()
+
+	for i := 0; i < 2; i++ {
+		if errs[i] != nil {
+			t.Fatalf("node %d: expected agreement, got %v", i, errs[i])
+		}
+		if score := jms[i].repMgr.GetScore(ids[2]); score != 50-computePenalty {
+			t.Fatalf("node %d: expected divergent node to be penalized, score %v", i, score)
+		}
+		if score := jms[i].repMgr.GetScore(ids[1-i]); score != 50 {
+			t.Fatalf("node %d: agreeing node lost reputation, score %v", i, score)
+		}
+	}
+	if errs[2] == nil || IsRetryable(errs[2]) {
+		t.Fatalf("expected the divergent node to fail permanently, got %v", errs[2])
+	}
+}
+
+func TestComputeConsensusConfigValidation(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	peers := []ComputePeer{{NodeID: crypto.PubkeyToAddress(key.PublicKey).Hex(), URL: "https://node-b:8080"}}
+	priv := hex.EncodeToString(crypto.FromECDSA(key))
+
+	if _, err := newComputeConsensus(ComputeConsensusConfig{Threshold: 3, Peers: peers}, priv); err == nil {
+		t.Fatal("expected threshold above committee size to be rejected")
+	}
+	if _, err := newComputeConsensus(ComputeConsensusConfig{Threshold: 2, Peers: []ComputePeer{{NodeID: "node-b", URL: "https://node-b:8080"}}}, priv); err == nil {
+		t.Fatal("expected non-address node_id to be rejected")
+	}
+	if _, err := newComputeConsensus(ComputeConsensusConfig{Threshold: 2, Peers: peers}, priv); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	if err := checkComputeDeterminism(functions.DefaultSandbox(), functions.Host{}); err != nil {
+		t.Fatalf("expected the default sandbox without feeds or fetches to be accepted, got %v", err)
+	}
+	if err := checkComputeDeterminism(functions.Sandbox{Clock: true}, functions.Host{}); err == nil {
+		t.Fatal("expected a clock grant to be rejected")
+	}
+	if err := checkComputeDeterminism(functions.DefaultSandbox(), functions.Host{HTTPAllowlist: []string{"https://api.example.com/"}}); err == nil {
+		t.Fatal("expected http_fetch to be rejected")
+	}
+	if err := checkComputeDeterminism(functions.DefaultSandbox(), functions.Host{Feeds: oracle.NewFeedManager()}); err == nil {
+		t.Fatal("expected feed_read to be rejected")
+	}
+}
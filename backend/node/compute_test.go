@@ -0,0 +1,148 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/obscura-network/obscura-node/adapters"
+	"github.com/obscura-network/obscura-node/functions"
+	"github.com/obscura-network/obscura-node/oracle"
+	"github.com/obscura-network/obscura-node/storage"
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+// addModule exports run(i64, i64) i64 returning the sum of its arguments
+var addModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	0x01, 0x07, 0x01, 0x60, 0x02, 0x7e, 0x7e, 0x01, 0x7e, // type (i64, i64) -> i64
+	0x03, 0x02, 0x01, 0x00, // one function of that type
+	0x07, 0x07, 0x01, 0x03, 'r', 'u', 'n', 0x00, 0x00, // export "run"
+	0x0a, 0x09, 0x01, 0x07, 0x00, 0x20, 0x00, 0x20, 0x01, 0x7c, 0x0b, // local.get 0, local.get 1, i64.add
+}
+
+func TestComputeRunsRegisteredFunction(t *testing.T) {
+	const token = "Bearer bank-key"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"checking": 1200.5, "savings": 800}`))
+	}))
+	defer server.Close()
+
+	secrets := storage.NewSecretManager()
+	secrets.AddSecret(server.URL, token)
+
+	ctx := context.Background()
+	cm, err := functions.NewComputeManager(ctx, "", functions.DefaultSandbox())
+	if err != nil {
+		t.Fatalf("Failed to start wasm runtime: %v", err)
+	}
+	defer cm.Close(ctx)
+
+	registry, err := functions.NewRegistry(t.TempDir(), cm)
+	if err != nil {
+		t.Fatalf("Failed to open registry: %v", err)
+	}
+	owner, _ := crypto.GenerateKey()
+	hash := functions.ModuleHash(addModule)
+	sig, _ := crypto.Sign(accounts.TextHash([]byte(functions.RegisterMessage(hash))), owner)
+	if _, err := registry.Register(ctx, addModule, crypto.PubkeyToAddress(owner.PublicKey).Hex(), hexutil.Encode(sig)); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	jm := &JobManager{adapters: adapters.NewAdapterManager(), secrets: secrets, computeMgr: cm, functions: registry}
+	job := oracle.JobRequest{
+		ID:   "1",
+		Type: oracle.JobTypeCompute,
+		Params: map[string]interface{}{
+			"function": "0x" + hash,
+			"inputs": []interface{}{
+				map[string]interface{}{"url": server.URL, "path": "checking"},
+				map[string]interface{}{"url": server.URL, "path": "savings"},
+			},
+			"decimals":  2.0,
+			"threshold": "200051", // one cent above the balance of 2000.50
+		},
+	}
+
+	output, err := jm.runFunction(ctx, job.ID, computeRequestFromJob(job))
+	if err != nil || output.Int64() != 200050 {
+		t.Fatalf("Expected the function to sum the scaled balances to 200050, got %v, %v", output, err)
+	}
+
+	// The function's output falls short, which is submitted like a satisfied
+	// result; without a private verifier registered it is refused up front
+	verifiers, err := newVerifierRegistry([]VerifierConfig{
+		{Circuit: "range", ChainID: 1, Address: "0x00000000000000000000000000000000000000aa"},
+	})
+	if err != nil {
+		t.Fatalf("newVerifierRegistry failed: %v", err)
+	}
+	jm.SetVerifierRegistry(verifiers, 1, nil)
+	if err := jm.handleCompute(ctx, job); !errors.Is(err, zkp.ErrUnknownVerifier) {
+		t.Fatalf("Expected the unsatisfied outcome to reach submission, got %v", err)
+	}
+
+	job.Params["function"] = functions.ModuleHash([]byte("not stored"))
+	if err := jm.handleCompute(ctx, job); err == nil || IsRetryable(err) {
+		t.Errorf("Expected an unknown function to fail permanently, got %v", err)
+	}
+
+	job.Params["function"] = hash
+	job.Params["entrypoint"] = "main"
+	if err := jm.handleCompute(ctx, job); err == nil || IsRetryable(err) {
+		t.Errorf("Expected a missing entrypoint to fail permanently, got %v", err)
+	}
+
+	delete(job.Params, "threshold")
+	if err := jm.handleCompute(ctx, job); err == nil || IsRetryable(err) {
+		t.Errorf("Expected a job without a threshold to fail permanently, got %v", err)
+	}
+}
+
+func TestListenerDecodesComputeRequests(t *testing.T) {
+	el, err := NewEventListener(nil, "", "0x0000000000000000000000000000000000000000", nil)
+	if err != nil {
+		t.Fatalf("NewEventListener failed: %v", err)
+	}
+	event := el.oracleABI.Events["ComputeRequested"]
+	hash := functions.ModuleHash(addModule)
+	var functionHash [32]byte
+	copy(functionHash[:], common.Hex2Bytes(hash))
+
+	data, err := event.Inputs.NonIndexed().Pack(functionHash, []string{"https://bank.example/a", "https://bank.example/b"}, []string{"checking", "savings"}, uint8(2), big.NewInt(200000))
+	if err != nil {
+		t.Fatalf("Failed to pack event: %v", err)
+	}
+	requester := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	job, err := el.computeJob(types.Log{
+		Topics: []common.Hash{event.ID, common.BigToHash(big.NewInt(7)), common.BytesToHash(requester.Bytes())},
+		Data:   data,
+	})
+	if err != nil {
+		t.Fatalf("computeJob failed: %v", err)
+	}
+	if job.ID != "7" || job.Type != oracle.JobTypeCompute || job.Requester != requester.Hex() {
+		t.Fatalf("Unexpected job %+v", job)
+	}
+
+	req := computeRequestFromJob(job)
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Decoded request is invalid: %v", err)
+	}
+	if req.Function != hash || len(req.Inputs) != 2 || req.Inputs[1].Path != "savings" || req.Decimals != 2 || req.Threshold.Int64() != 200000 {
+		t.Errorf("Unexpected compute request %+v", req)
+	}
+}
@@ -0,0 +1,253 @@
+package node
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	edwards "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/rs/zerolog/log"
+
+	"github.com/obscura-network/obscura-node/api"
+	"github.com/obscura-network/obscura-node/oracle"
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+const disclosurePrefix = "disclosure_"
+
+// SetDisclosureSources sets the private endpoints selective disclosures may
+// attest over. Without any, every disclosure request is refused.
+func (jm *JobManager) SetDisclosureSources(urls []string) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	jm.disclosureSources = make(map[string]bool, len(urls))
+	for _, url := range urls {
+		jm.disclosureSources[url] = true
+	}
+}
+
+func (jm *JobManager) disclosureSource(url string) bool {
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+	return jm.disclosureSources[url]
+}
+
+// RequestDisclosure validates a selective-disclosure request and queues it,
+// returning the job ID its result is stored under
+func (jm *JobManager) RequestDisclosure(req oracle.DisclosureRequest) (string, error) {
+	if req.URL == "" {
+		return "", fmt.Errorf("%w: url is required", oracle.ErrInvalidRequest)
+	}
+	if !jm.disclosureSource(req.URL) {
+		return "", fmt.Errorf("%w: url is not a registered disclosure source", oracle.ErrInvalidRequest)
+	}
+	if _, _, err := req.Predicate.Bounds(req.Decimals); err != nil {
+		return "", fmt.Errorf("%w: predicate: %v", oracle.ErrInvalidRequest, err)
+	}
+	if req.Recipient != "" {
+		if _, err := zkp.DecodeDisclosureKey(req.Recipient); err != nil {
+			return "", fmt.Errorf("%w: %v", oracle.ErrInvalidRequest, err)
+		}
+	}
+
+	job := oracle.JobRequest{
+		ID:   fmt.Sprintf("disclosure-%d", time.Now().UnixNano()),
+		Type: oracle.JobTypeSelectiveDisclosure,
+		Params: map[string]interface{}{
+			"url":        req.URL,
+			"field":      req.Predicate.Field,
+			"comparator": req.Predicate.Comparator,
+			"threshold":  req.Predicate.Threshold,
+			"upper":      req.Predicate.Upper,
+			"decimals":   req.Decimals,
+			"recipient":  req.Recipient,
+		},
+		Timestamp: time.Now(),
+	}
+	if err := jm.Dispatch(job); err != nil {
+		return "", err
+	}
+	return job.ID, nil
+}
+
+// GetDisclosure returns the stored result of a selective-disclosure job
+func (jm *JobManager) GetDisclosure(id string) (oracle.DisclosureResult, bool) {
+	if jm.persistence == nil {
+		return oracle.DisclosureResult{}, false
+	}
+	return jm.persistence.LoadDisclosure(id)
+}
+
+func disclosureRequestFromJob(job oracle.JobRequest) oracle.DisclosureRequest {
+	str := func(key string) string {
+		s, _ := job.Params[key].(string)
+		return s
+	}
+	num := func(key string) float64 {
+		switch v := job.Params[key].(type) {
+		case float64:
+			return v
+		case int:
+			return float64(v)
+		}
+		return 0
+	}
+	decimals, _ := toInt(job.Params["decimals"])
+	return oracle.DisclosureRequest{
+		URL: str("url"),
+		Predicate: oracle.Predicate{
+			Field:      str("field"),
+			Comparator: str("comparator"),
+			Threshold:  num("threshold"),
+			Upper:      num("upper"),
+		},
+		Decimals:  decimals,
+		Recipient: str("recipient"),
+	}
+}
+
+func (jm *JobManager) handleSelectiveDisclosure(ctx context.Context, job oracle.JobRequest) error {
+	req := disclosureRequestFromJob(job)
+	if !jm.disclosureSource(req.URL) {
+		return permanent(fmt.Errorf("%s is not a registered disclosure source", req.URL))
+	}
+	result, _, err := jm.attestPredicate(job.ID, req)
+	if err != nil {
+		return err
+	}
+
+	if jm.persistence != nil {
+		if err := jm.persistence.SaveDisclosure(result); err != nil {
+			return fmt.Errorf("failed to store disclosure result: %w", err)
+		}
+	}
+
+	log.Info().
+		Str("job_id", job.ID).
+		Str("field", req.Predicate.Field).
+		Str("comparator", req.Predicate.Comparator).
+		Bool("satisfied", result.Satisfied).
+		Msg("Selective Disclosure Attested")
+
+	// The public job history must not tell whether the predicate held
+	if jm.metrics != nil {
+		jm.metrics.AddJobRecord(api.JobRecord{
+			ID:        job.ID,
+			Type:      "Selective Disclosure",
+			Target:    req.Predicate.Field,
+			Status:    "Attested",
+			Timestamp: time.Now(),
+		})
+	}
+	jm.trackState(job.ID, oracle.JobStateConfirmed)
+	return nil
+}
+
+// attestPredicate fetches the private value with vault credentials and
+// proves the predicate over it. A predicate that does not hold is a result,
+// not a failure: it is reported without a proof.
+func (jm *JobManager) attestPredicate(jobID string, req oracle.DisclosureRequest) (oracle.DisclosureResult, zkp.Proof, error) {
+	result := oracle.DisclosureResult{ID: jobID, Predicate: req.Predicate, Timestamp: time.Now()}
+
+	min, max, err := req.Predicate.Bounds(req.Decimals)
+	if err != nil {
+		return result, nil, permanent(fmt.Errorf("invalid predicate: %w", err))
+	}
+
+	jm.trackState(jobID, oracle.JobStateFetching)
+	raw, err := jm.fetchSource(oracle.DataSource{URL: req.URL, Path: req.Predicate.Field})
+	if err != nil {
+		return result, nil, fmt.Errorf("failed to fetch private data: %w", err)
+	}
+	value, err := oracle.ScaleValue(raw, req.Decimals)
+	if err != nil {
+		return result, nil, permanent(fmt.Errorf("field %s: %w", req.Predicate.Field, err))
+	}
+	if value.Cmp(min) < 0 || value.Cmp(max) > 0 {
+		return result, nil, nil
+	}
+
+	recipient, err := jm.disclosureRecipient(req.Recipient)
+	if err != nil {
+		return result, nil, permanent(err)
+	}
+	blinding, err := zkp.RandomFieldElement()
+	if err != nil {
+		return result, nil, err
+	}
+
+	jm.trackState(jobID, oracle.JobStateProving)
+	proof, d, err := zkp.GenerateSelectiveDisclosureProof(value, blinding, min, max, recipient)
+	if err != nil {
+		return result, nil, proofFailure(fmt.Errorf("selective disclosure proof failed: %w", err))
+	}
+	proofBytes, err := zkp.SerializeProofBytes(proof)
+	if err != nil {
+		return result, nil, permanent(fmt.Errorf("proof serialization failed: %w", err))
+	}
+	inputs, err := d.PublicInputs()
+	if err != nil {
+		return result, nil, permanent(err)
+	}
+
+	result.Satisfied = true
+	result.DataCommitment = d.DataCommitment.String()
+	result.EphemeralKey = zkp.EncodeDisclosureKey(d.Ephemeral)
+	result.Ciphertext = d.Ciphertext.String()
+	result.RangeMin = d.RangeMin.String()
+	result.RangeMax = d.RangeMax.String()
+	result.Proof = "0x" + hex.EncodeToString(proofBytes)
+	result.PublicInputs = make([]string, len(inputs))
+	for i, in := range inputs {
+		result.PublicInputs[i] = in.String()
+	}
+	result.Recipient = zkp.EncodeDisclosureKey(recipient)
+	return result, proof, nil
+}
+
+// disclosureRecipient parses the consumer's key, or encrypts to a key whose
+// secret is dropped at once when no one is entitled to the value
+func (jm *JobManager) disclosureRecipient(encoded string) (edwards.PointAffine, error) {
+	if encoded != "" {
+		return zkp.DecodeDisclosureKey(encoded)
+	}
+	key, err := zkp.NewDisclosureKey()
+	if err != nil {
+		return edwards.PointAffine{}, err
+	}
+	return key.Public, nil
+}
+
+// SaveDisclosure stores a selective-disclosure result for the API
+func (jp *JobPersistence) SaveDisclosure(result oracle.DisclosureResult) error {
+	return jp.store.SaveJob(disclosurePrefix+result.ID, result)
+}
+
+// LoadDisclosure returns a stored selective-disclosure result
+func (jp *JobPersistence) LoadDisclosure(id string) (oracle.DisclosureResult, bool) {
+	data, ok := jp.store.GetJob(disclosurePrefix + id)
+	if !ok {
+		return oracle.DisclosureResult{}, false
+	}
+	return decodeDisclosure(data)
+}
+
+// decodeDisclosure handles both in-memory results and the generic maps
+// produced when the store is reloaded from JSON
+func decodeDisclosure(data interface{}) (oracle.DisclosureResult, bool) {
+	if result, ok := data.(oracle.DisclosureResult); ok {
+		return result, true
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return oracle.DisclosureResult{}, false
+	}
+	var result oracle.DisclosureResult
+	if err := json.Unmarshal(raw, &result); err != nil || result.ID == "" {
+		return oracle.DisclosureResult{}, false
+	}
+	return result, true
+}
@@ -0,0 +1,120 @@
+package node
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/obscura-network/obscura-node/adapters"
+	"github.com/obscura-network/obscura-node/oracle"
+	"github.com/obscura-network/obscura-node/storage"
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+func TestSelectiveDisclosureProvesPredicate(t *testing.T) {
+	const token = "X-API-Key: bureau-key"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"applicant": {"credit_score": 742}}`))
+	}))
+	defer server.Close()
+
+	secrets := storage.NewSecretManager()
+	secrets.AddSecret(server.URL, token)
+
+	store, err := storage.NewFileStore("./test_disclosure.json")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer store.Clear()
+
+	jm := &JobManager{adapters: adapters.NewAdapterManager(), secrets: secrets, persistence: NewJobPersistence(store)}
+	auditor, _ := zkp.NewDisclosureKey()
+	req := oracle.DisclosureRequest{
+		URL:       server.URL,
+		Predicate: oracle.Predicate{Field: "applicant.credit_score", Comparator: oracle.ComparatorGT, Threshold: 700},
+		Recipient: zkp.EncodeDisclosureKey(auditor.Public),
+	}
+
+	result, proof, err := jm.attestPredicate("disclosure-1", req)
+	if err != nil {
+		t.Fatalf("attestPredicate failed: %v", err)
+	}
+	if !result.Satisfied || result.Proof == "" || len(result.PublicInputs) != 8 {
+		t.Fatalf("Expected a proof with 8 public inputs, got %+v", result)
+	}
+	for _, in := range result.PublicInputs {
+		if in == "742" {
+			t.Error("Attested value appears in the public inputs")
+		}
+	}
+
+	// The result round-trips through the store and verifies as published
+	if err := jm.persistence.SaveDisclosure(result); err != nil {
+		t.Fatalf("SaveDisclosure failed: %v", err)
+	}
+	reloaded, _ := storage.NewFileStore("./test_disclosure.json")
+	stored, ok := NewJobPersistence(reloaded).LoadDisclosure("disclosure-1")
+	if !ok {
+		t.Fatal("Stored disclosure not found")
+	}
+	d := disclosureFromResult(t, stored)
+	if err := zkp.VerifySelectiveDisclosureProof(proof, d); err != nil {
+		t.Errorf("Stored disclosure does not verify: %v", err)
+	}
+	inputs, _ := d.PublicInputs()
+	for i, in := range inputs {
+		if in.String() != stored.PublicInputs[i] {
+			t.Errorf("Public input %d is %s, stored %s", i, in, stored.PublicInputs[i])
+		}
+	}
+	if got := auditor.Decrypt(d.Ciphertext, d.Ephemeral); got.Int64() != 742 {
+		t.Errorf("Auditor decrypted %v, want 742", got)
+	}
+
+	req.Predicate.Threshold = 750
+	result, _, err = jm.attestPredicate("disclosure-2", req)
+	if err != nil || result.Satisfied || result.Proof != "" {
+		t.Errorf("Expected an unsatisfied predicate without a proof, got %+v, %v", result, err)
+	}
+
+	if _, err := jm.RequestDisclosure(oracle.DisclosureRequest{URL: server.URL, Predicate: oracle.Predicate{Field: "x", Comparator: oracle.ComparatorGT}}); !errors.Is(err, oracle.ErrInvalidRequest) {
+		t.Errorf("Expected an unregistered source to be rejected, got %v", err)
+	}
+	jm.SetDisclosureSources([]string{server.URL})
+	if _, err := jm.RequestDisclosure(oracle.DisclosureRequest{URL: server.URL, Predicate: oracle.Predicate{Field: "x", Comparator: "ne"}}); err == nil {
+		t.Error("Expected an unknown comparator to be rejected before queueing")
+	}
+}
+
+func disclosureFromResult(t *testing.T, r oracle.DisclosureResult) zkp.SelectiveDisclosure {
+	t.Helper()
+	num := func(s string) *big.Int {
+		v, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			t.Fatalf("Invalid number %q in stored disclosure", s)
+		}
+		return v
+	}
+	recipient, err := zkp.DecodeDisclosureKey(r.Recipient)
+	if err != nil {
+		t.Fatalf("Invalid recipient: %v", err)
+	}
+	ephemeral, err := zkp.DecodeDisclosureKey(r.EphemeralKey)
+	if err != nil {
+		t.Fatalf("Invalid ephemeral key: %v", err)
+	}
+	return zkp.SelectiveDisclosure{
+		DataCommitment: num(r.DataCommitment),
+		Recipient:      recipient,
+		Ephemeral:      ephemeral,
+		Ciphertext:     num(r.Ciphertext),
+		RangeMin:       num(r.RangeMin),
+		RangeMax:       num(r.RangeMax),
+	}
+}
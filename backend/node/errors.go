@@ -0,0 +1,50 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+// permanentError marks a job failure that would recur on every retry,
+// such as an invalid proof witness or a malformed request
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// permanent wraps err so the retry scheduler sends the job straight to the dead letter queue
+func permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsRetryable reports whether a failed job may succeed if attempted again.
+// Fetch timeouts, RPC errors and nonce races are retryable; contract reverts
+// and errors marked permanent are not.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var perm *permanentError
+	if errors.As(err, &perm) {
+		return false
+	}
+	return !strings.Contains(err.Error(), "execution reverted")
+}
+
+// proofFailure marks a proving error permanent unless the prover was busy or
+// shutting down, or the job was cancelled while its proof waited
+func proofFailure(err error) error {
+	if errors.Is(err, zkp.ErrProverQueueFull) || errors.Is(err, zkp.ErrProverStopped) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return permanent(err)
+}
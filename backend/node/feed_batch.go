@@ -0,0 +1,168 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/obscura-network/obscura-node/oracle"
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+// FeedBatchConfig makes the node fulfill data feeds in batches of up to
+// zkp.AggregationBatchSize requests with one aggregated proof. Window is how
+// long the first update of a batch waits for others to join it.
+type FeedBatchConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Window  time.Duration `mapstructure:"window"`
+}
+
+// feedBatcher collects range-bounded feed updates and submits them through
+// ObscuraOracle.fulfillDataBatch. Submit blocks until the batch transaction
+// is sent, so every job is journaled and tracked like a single fulfillment.
+type feedBatcher struct {
+	jm      *JobManager
+	window  time.Duration
+	mu      sync.Mutex
+	pending []*batchedUpdate
+	timer   *time.Timer
+}
+
+type batchedUpdate struct {
+	ctx    context.Context
+	jobID  string
+	update zkp.RangeUpdate
+	done   chan error
+}
+
+// SetFeedBatching enables batched data feed fulfillment
+func (jm *JobManager) SetFeedBatching(cfg FeedBatchConfig) {
+	if !cfg.Enabled {
+		jm.batcher = nil
+		return
+	}
+	window := cfg.Window
+	if window <= 0 {
+		window = 2 * time.Second
+	}
+	jm.batcher = &feedBatcher{jm: jm, window: window}
+}
+
+// Submit adds an update to the open batch and waits for its submission
+func (b *feedBatcher) Submit(ctx context.Context, jobID string, update zkp.RangeUpdate) error {
+	u := &batchedUpdate{ctx: ctx, jobID: jobID, update: update, done: make(chan error, 1)}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, u)
+	switch {
+	case len(b.pending) >= zkp.AggregationBatchSize:
+		go b.flush(b.take())
+	case len(b.pending) == 1:
+		b.timer = time.AfterFunc(b.window, func() {
+			b.mu.Lock()
+			batch := b.take()
+			b.mu.Unlock()
+			b.flush(batch)
+		})
+	}
+	b.mu.Unlock()
+
+	select {
+	case err := <-u.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// take empties the open batch; callers hold b.mu
+func (b *feedBatcher) take() []*batchedUpdate {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	batch := b.pending
+	b.pending = nil
+	return batch
+}
+
+func (b *feedBatcher) flush(batch []*batchedUpdate) {
+	if len(batch) == 0 {
+		return
+	}
+	err := b.send(batch)
+	for _, u := range batch {
+		u.done <- err
+	}
+}
+
+func (b *feedBatcher) send(batch []*batchedUpdate) error {
+	ctx := batch[0].ctx
+	jobIDs := make([]string, len(batch))
+	requestIDs := make([]*big.Int, len(batch))
+	values := make([]*big.Int, len(batch))
+	updates := make([]zkp.RangeUpdate, len(batch))
+	for i, u := range batch {
+		id, ok := new(big.Int).SetString(u.jobID, 10)
+		if !ok {
+			return permanent(fmt.Errorf("invalid request ID %q in feed batch", u.jobID))
+		}
+		jobIDs[i] = u.jobID
+		requestIDs[i] = id
+		values[i] = u.update.Value
+		updates[i] = u.update
+	}
+
+	tag, err := b.jm.circuitVersion(ctx, "aggregation", b.jm.oracleAddr)
+	if err != nil {
+		return err
+	}
+
+	proof, err := zkp.AggregateRangeProofsContext(ctx, updates)
+	if err != nil {
+		return proofFailure(fmt.Errorf("aggregated proof generation failed: %w", err))
+	}
+	serialized, err := zkp.SerializeProof(proof)
+	if err != nil {
+		return permanent(fmt.Errorf("proof serialization failed: %w", err))
+	}
+	commitments, pok, err := zkp.SerializeProofCommitments(proof)
+	if err != nil {
+		return permanent(fmt.Errorf("proof serialization failed: %w", err))
+	}
+	if len(commitments) != 2 {
+		return permanent(fmt.Errorf("aggregated proof has %d commitment words, fulfillDataBatch takes 2", len(commitments)))
+	}
+
+	data, err := b.jm.oracleABI.Pack("fulfillDataBatch", requestIDs, values, serialized, [2]*big.Int{commitments[0], commitments[1]}, pok)
+	if err != nil {
+		return permanent(fmt.Errorf("failed to pack fulfillDataBatch: %w", err))
+	}
+
+	txHash, err := b.jm.sendToJobs(ctx, jobIDs, b.jm.oracleAddr, data, tag)
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("tx_hash", txHash.Hex()).
+		Int("requests", len(batch)).
+		Int("circuit_version", tag.Version).
+		Msg("Batched Fulfillment Transaction Sent")
+	return nil
+}
+
+// batchable reports whether a feed update can join a batch: the contract
+// checks batched values against the request's own thresholds, so only
+// requests that carry them qualify. Updates outside their bounds take the
+// single-proof path, which fails them without failing the rest of a batch.
+func (jm *JobManager) batchable(job oracle.JobRequest, value, min, max *big.Int) bool {
+	if jm.batcher == nil || job.Params["min"] == nil || job.Params["max"] == nil {
+		return false
+	}
+	return value.BitLen() <= zkp.AggregationValueBits && value.Cmp(min) >= 0 && value.Cmp(max) <= 0
+}
@@ -0,0 +1,74 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/obscura-network/obscura-node/oracle"
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+func TestFeedBatchFlushesFullBatchesAtOnce(t *testing.T) {
+	// Without an aggregation verifier registered every batch fails before
+	// proving, which is enough to see how updates are grouped
+	registry, err := newVerifierRegistry([]VerifierConfig{
+		{Circuit: "range", ChainID: 1, Address: "0x00000000000000000000000000000000000000aa"},
+	})
+	if err != nil {
+		t.Fatalf("newVerifierRegistry failed: %v", err)
+	}
+	jm := &JobManager{}
+	jm.SetVerifierRegistry(registry, 1, nil)
+	jm.SetFeedBatching(FeedBatchConfig{Enabled: true, Window: time.Hour})
+
+	errs := make(chan error, zkp.AggregationBatchSize)
+	for i := 0; i < zkp.AggregationBatchSize; i++ {
+		go func(i int) {
+			update := zkp.RangeUpdate{Value: big.NewInt(150), Min: big.NewInt(100), Max: big.NewInt(200)}
+			errs <- jm.batcher.Submit(context.Background(), fmt.Sprint(i), update)
+		}(i)
+	}
+
+	for i := 0; i < zkp.AggregationBatchSize; i++ {
+		select {
+		case err := <-errs:
+			if !errors.Is(err, zkp.ErrUnknownVerifier) || IsRetryable(err) {
+				t.Errorf("Expected every update to share the batch's permanent error, got %v", err)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("A full batch waited for its window")
+		}
+	}
+
+	// A lone update is sent once the window closes
+	jm.SetFeedBatching(FeedBatchConfig{Enabled: true, Window: 50 * time.Millisecond})
+	update := zkp.RangeUpdate{Value: big.NewInt(150), Min: big.NewInt(100), Max: big.NewInt(200)}
+	if err := jm.batcher.Submit(context.Background(), "9", update); !errors.Is(err, zkp.ErrUnknownVerifier) {
+		t.Errorf("Expected the lone update to be flushed, got %v", err)
+	}
+}
+
+func TestFeedBatchOnlyTakesBoundedUpdates(t *testing.T) {
+	jm := &JobManager{}
+	bounded := oracle.JobRequest{Params: map[string]interface{}{"min": "100", "max": "200"}}
+	min, max := big.NewInt(100), big.NewInt(200)
+
+	if jm.batchable(bounded, big.NewInt(150), min, max) {
+		t.Error("Expected no batching while it is disabled")
+	}
+
+	jm.SetFeedBatching(FeedBatchConfig{Enabled: true})
+	if !jm.batchable(bounded, big.NewInt(150), min, max) {
+		t.Error("Expected an update within its request's bounds to be batched")
+	}
+	if jm.batchable(bounded, big.NewInt(250), min, max) {
+		t.Error("Expected an out-of-bounds update to take the single-proof path")
+	}
+	if jm.batchable(oracle.JobRequest{Params: map[string]interface{}{}}, big.NewInt(150), min, max) {
+		t.Error("Expected an update without request thresholds to take the single-proof path")
+	}
+}
@@ -0,0 +1,226 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+
+	"github.com/obscura-network/obscura-node/storage"
+)
+
+// OracleRequestsABI holds the auto-generated getters of ObscuraOracle's
+// requests and randomnessRequests mappings, copied from the compiled artifact.
+// Getters return every struct field except arrays and mappings, in order.
+const OracleRequestsABI = `[
+	{"inputs":[{"internalType":"uint256","name":"","type":"uint256"}],"name":"requests","outputs":[{"internalType":"uint256","name":"id","type":"uint256"},{"internalType":"string","name":"apiUrl","type":"string"},{"internalType":"address","name":"requester","type":"address"},{"internalType":"address","name":"oevBeneficiary","type":"address"},{"internalType":"bool","name":"oevEnabled","type":"bool"},{"internalType":"bool","name":"isOptimistic","type":"bool"},{"internalType":"uint256","name":"challengeWindow","type":"uint256"},{"internalType":"address","name":"disputer","type":"address"},{"internalType":"bool","name":"isDisputed","type":"bool"},{"internalType":"bool","name":"resolved","type":"bool"},{"internalType":"uint256","name":"finalValue","type":"uint256"},{"internalType":"uint256","name":"createdAt","type":"uint256"},{"internalType":"uint256","name":"minThreshold","type":"uint256"},{"internalType":"uint256","name":"maxThreshold","type":"uint256"},{"internalType":"string","name":"metadata","type":"string"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"internalType":"uint256","name":"","type":"uint256"}],"name":"randomnessRequests","outputs":[{"internalType":"string","name":"seed","type":"string"},{"internalType":"address","name":"requester","type":"address"},{"internalType":"uint256","name":"randomness","type":"uint256"},{"internalType":"bool","name":"resolved","type":"bool"}],"stateMutability":"view","type":"function"}
+]`
+
+const fulfillmentJournalPrefix = "fulfillment_tx_"
+
+// TxState is what the chain knows about a journaled fulfillment transaction
+type TxState string
+
+const (
+	TxStateUnknown   TxState = "unknown"
+	TxStatePending   TxState = "pending"   // In the mempool
+	TxStateConfirmed TxState = "confirmed" // Mined successfully
+	TxStateReverted  TxState = "reverted"  // Mined but reverted
+	TxStateDropped   TxState = "dropped"   // Never broadcast or evicted from the mempool
+)
+
+// TxStatusReader looks up transactions previously sent by this node
+type TxStatusReader interface {
+	TxStatus(ctx context.Context, hash common.Hash) (TxState, error)
+}
+
+// RequestStatusReader reports whether the oracle contract has already resolved
+// a request. Data and compute requests share one ID counter, randomness
+// requests have their own.
+type RequestStatusReader interface {
+	IsResolved(ctx context.Context, requestID *big.Int) (bool, error)
+	IsRandomnessResolved(ctx context.Context, requestID *big.Int) (bool, error)
+}
+
+// OracleRequestReader reads request status through the oracle contract's
+// requests(id) and randomnessRequests(id) views
+type OracleRequestReader struct {
+	abi      abi.ABI
+	contract *bind.BoundContract
+}
+
+// NewOracleRequestReader binds the request views of the oracle contract
+func NewOracleRequestReader(caller bind.ContractCaller, contractAddr string) (*OracleRequestReader, error) {
+	parsed, err := abi.JSON(strings.NewReader(OracleRequestsABI))
+	if err != nil {
+		return nil, err
+	}
+	return &OracleRequestReader{
+		abi:      parsed,
+		contract: bind.NewBoundContract(common.HexToAddress(contractAddr), parsed, caller, nil, nil),
+	}, nil
+}
+
+// IsResolved returns the 'resolved' field of requests(id)
+func (r *OracleRequestReader) IsResolved(ctx context.Context, requestID *big.Int) (bool, error) {
+	return r.resolved(ctx, "requests", requestID)
+}
+
+// IsRandomnessResolved returns the 'resolved' field of randomnessRequests(id)
+func (r *OracleRequestReader) IsRandomnessResolved(ctx context.Context, requestID *big.Int) (bool, error) {
+	return r.resolved(ctx, "randomnessRequests", requestID)
+}
+
+func (r *OracleRequestReader) resolved(ctx context.Context, method string, requestID *big.Int) (bool, error) {
+	var out []interface{}
+	if err := r.contract.Call(&bind.CallOpts{Context: ctx}, &out, method, requestID); err != nil {
+		return false, err
+	}
+	for i, output := range r.abi.Methods[method].Outputs {
+		if output.Name == "resolved" && i < len(out) {
+			if resolved, ok := out[i].(bool); ok {
+				return resolved, nil
+			}
+		}
+	}
+	return false, fmt.Errorf("unexpected %s() output for %s", method, requestID)
+}
+
+// vrfJobPrefix keeps randomness request IDs apart from data request IDs,
+// which come from a separate on-chain counter
+const vrfJobPrefix = "vrf-"
+
+// vrfJobID is the job ID of on-chain randomness request id
+func vrfJobID(id *big.Int) string {
+	return vrfJobPrefix + id.String()
+}
+
+// randomnessRequestID recovers the on-chain randomness request ID of a VRF job
+func randomnessRequestID(jobID string) (*big.Int, bool) {
+	id, ok := strings.CutPrefix(jobID, vrfJobPrefix)
+	if !ok {
+		return nil, false
+	}
+	return new(big.Int).SetString(id, 10)
+}
+
+// FulfillmentDecision tells the job manager whether a job still needs a transaction
+type FulfillmentDecision int
+
+const (
+	FulfillmentRequired FulfillmentDecision = iota // Nothing on-chain yet
+	FulfillmentInFlight                            // A journaled transaction is still pending
+	FulfillmentDone                                // The request is already resolved
+)
+
+// FulfillmentGuard ensures each request is fulfilled at most once across restarts
+// by combining a local tx journal with the on-chain request status
+type FulfillmentGuard struct {
+	store    storage.Store
+	requests RequestStatusReader
+	txs      TxStatusReader
+}
+
+// NewFulfillmentGuard creates a guard; requests may be nil to rely on the journal alone
+func NewFulfillmentGuard(store storage.Store, requests RequestStatusReader, txs TxStatusReader) *FulfillmentGuard {
+	return &FulfillmentGuard{
+		store:    store,
+		requests: requests,
+		txs:      txs,
+	}
+}
+
+// Check decides whether a job still needs a fulfillment transaction. An error
+// means the status could not be established and the job must not be resubmitted yet.
+func (fg *FulfillmentGuard) Check(ctx context.Context, jobID string) (FulfillmentDecision, common.Hash, error) {
+	entry, journaled := fg.load(jobID)
+	var hash common.Hash
+
+	if journaled {
+		hash = common.HexToHash(entry["tx_hash"].(string))
+		if entry["status"] == string(TxStateConfirmed) {
+			return FulfillmentDone, hash, nil
+		}
+
+		state, err := fg.txs.TxStatus(ctx, hash)
+		if err != nil {
+			return FulfillmentRequired, hash, fmt.Errorf("failed to check journaled tx %s: %w", hash.Hex(), err)
+		}
+		switch state {
+		case TxStateConfirmed:
+			fg.Record(jobID, hash, TxStateConfirmed)
+			return FulfillmentDone, hash, nil
+		case TxStatePending:
+			return FulfillmentInFlight, hash, nil
+		case TxStateUnknown:
+			return FulfillmentRequired, hash, fmt.Errorf("journaled tx %s has unknown status", hash.Hex())
+		}
+		// Reverted or dropped: a revert usually means another node resolved the request first
+	}
+
+	resolved, err := fg.isResolved(ctx, jobID)
+	if err != nil {
+		if journaled {
+			return FulfillmentRequired, hash, fmt.Errorf("failed to read request status: %w", err)
+		}
+		log.Warn().Err(err).Str("job_id", jobID).Msg("Could not read on-chain request status")
+		return FulfillmentRequired, common.Hash{}, nil
+	}
+	if resolved {
+		return FulfillmentDone, hash, nil
+	}
+	return FulfillmentRequired, common.Hash{}, nil
+}
+
+// Journal records a signed fulfillment transaction before it is broadcast
+func (fg *FulfillmentGuard) Journal(jobID string, hash common.Hash) error {
+	return fg.Record(jobID, hash, TxStatePending)
+}
+
+// Record updates the journaled state of a fulfillment transaction
+func (fg *FulfillmentGuard) Record(jobID string, hash common.Hash, state TxState) error {
+	return fg.store.SaveJob(fulfillmentJournalPrefix+jobID, map[string]interface{}{
+		"job_id":     jobID,
+		"tx_hash":    hash.Hex(),
+		"status":     string(state),
+		"updated_at": time.Now().Unix(),
+	})
+}
+
+func (fg *FulfillmentGuard) load(jobID string) (map[string]interface{}, bool) {
+	data, ok := fg.store.GetJob(fulfillmentJournalPrefix + jobID)
+	if !ok {
+		return nil, false
+	}
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	if _, ok := m["tx_hash"].(string); !ok {
+		return nil, false
+	}
+	return m, true
+}
+
+// isResolved checks the oracle contract; job IDs that are not on-chain request IDs
+// (automation and feed jobs) are never resolved there
+func (fg *FulfillmentGuard) isResolved(ctx context.Context, jobID string) (bool, error) {
+	if fg.requests == nil {
+		return false, nil
+	}
+	if requestID, ok := randomnessRequestID(jobID); ok {
+		return fg.requests.IsRandomnessResolved(ctx, requestID)
+	}
+	requestID, ok := new(big.Int).SetString(jobID, 10)
+	if !ok {
+		return false, nil
+	}
+	return fg.requests.IsResolved(ctx, requestID)
+}
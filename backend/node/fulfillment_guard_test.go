@@ -0,0 +1,180 @@
+package node
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/obscura-network/obscura-node/storage"
+)
+
+type fakeChain struct {
+	resolved   map[string]bool
+	randomness map[string]bool
+	txs        map[common.Hash]TxState
+	requestErr error
+	calls      int
+}
+
+func (f *fakeChain) IsResolved(_ context.Context, requestID *big.Int) (bool, error) {
+	f.calls++
+	if f.requestErr != nil {
+		return false, f.requestErr
+	}
+	return f.resolved[requestID.String()], nil
+}
+
+func (f *fakeChain) IsRandomnessResolved(_ context.Context, requestID *big.Int) (bool, error) {
+	f.calls++
+	return f.randomness[requestID.String()], nil
+}
+
+func (f *fakeChain) TxStatus(_ context.Context, hash common.Hash) (TxState, error) {
+	if state, ok := f.txs[hash]; ok {
+		return state, nil
+	}
+	return TxStateDropped, nil
+}
+
+func TestFulfillmentGuardUsesJournal(t *testing.T) {
+	store, err := storage.NewFileStore("./test_fulfillment_journal.json")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer store.Clear()
+
+	chain := &fakeChain{resolved: map[string]bool{}, txs: map[common.Hash]TxState{}}
+	fg := NewFulfillmentGuard(store, chain, chain)
+	ctx := context.Background()
+
+	pending := common.HexToHash("0x01")
+	chain.txs[pending] = TxStatePending
+	fg.Journal("1001", pending)
+
+	// Crash after broadcast: the replayed job must wait for the pending tx
+	if decision, hash, err := fg.Check(ctx, "1001"); err != nil || decision != FulfillmentInFlight || hash != pending {
+		t.Fatalf("Expected in-flight for pending tx, got %v %s %v", decision, hash.Hex(), err)
+	}
+
+	chain.txs[pending] = TxStateConfirmed
+	if decision, _, _ := fg.Check(ctx, "1001"); decision != FulfillmentDone {
+		t.Fatalf("Expected done once the journaled tx is mined, got %v", decision)
+	}
+
+	// A confirmed journal entry needs no RPC at all
+	chain.calls = 0
+	chain.txs = map[common.Hash]TxState{}
+	if decision, _, _ := fg.Check(ctx, "1001"); decision != FulfillmentDone || chain.calls != 0 {
+		t.Errorf("Expected confirmed journal entry to short-circuit, got %v after %d calls", decision, chain.calls)
+	}
+
+	// Crash between journaling and broadcast: the tx never reached the chain
+	fg.Journal("1002", common.HexToHash("0x02"))
+	if decision, _, err := fg.Check(ctx, "1002"); err != nil || decision != FulfillmentRequired {
+		t.Errorf("Expected dropped tx to be resubmitted, got %v %v", decision, err)
+	}
+}
+
+func TestFulfillmentGuardChecksChain(t *testing.T) {
+	store, err := storage.NewFileStore("./test_fulfillment_chain.json")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer store.Clear()
+
+	chain := &fakeChain{resolved: map[string]bool{"2001": true}, txs: map[common.Hash]TxState{}}
+	fg := NewFulfillmentGuard(store, chain, chain)
+	ctx := context.Background()
+
+	if decision, _, _ := fg.Check(ctx, "2001"); decision != FulfillmentDone {
+		t.Errorf("Expected request resolved on-chain to be skipped, got %v", decision)
+	}
+	if decision, _, _ := fg.Check(ctx, "2002"); decision != FulfillmentRequired {
+		t.Errorf("Expected unresolved request to need fulfillment, got %v", decision)
+	}
+
+	// Non-numeric IDs are not on-chain requests
+	chain.calls = 0
+	fg.Check(ctx, "auto-1703750000")
+	if chain.calls != 0 {
+		t.Errorf("Expected no on-chain lookup for automation jobs")
+	}
+
+	// Without a journal entry an RPC failure does not block the first attempt,
+	// but after a send the job must wait until the status can be read
+	chain.requestErr = errors.New("connection refused")
+	if _, _, err := fg.Check(ctx, "2003"); err != nil {
+		t.Errorf("Expected first attempt to proceed, got %v", err)
+	}
+	fg.Record("2003", common.HexToHash("0x03"), TxStateReverted)
+	chain.txs[common.HexToHash("0x03")] = TxStateReverted
+	if _, _, err := fg.Check(ctx, "2003"); err == nil {
+		t.Error("Expected an error when a sent request's status cannot be verified")
+	}
+}
+
+func TestFulfillmentGuardSeparatesRandomnessRequests(t *testing.T) {
+	store, err := storage.NewFileStore("./test_fulfillment_vrf.json")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer store.Clear()
+
+	// Data request 5 is resolved; randomness request 5 is not
+	chain := &fakeChain{resolved: map[string]bool{"5": true}, randomness: map[string]bool{}, txs: map[common.Hash]TxState{}}
+	fg := NewFulfillmentGuard(store, chain, chain)
+	ctx := context.Background()
+
+	if decision, _, _ := fg.Check(ctx, vrfJobID(big.NewInt(5))); decision != FulfillmentRequired {
+		t.Errorf("Expected randomness request to be checked on its own counter, got %v", decision)
+	}
+	chain.randomness["5"] = true
+	if decision, _, _ := fg.Check(ctx, vrfJobID(big.NewInt(5))); decision != FulfillmentDone {
+		t.Errorf("Expected resolved randomness request to be skipped, got %v", decision)
+	}
+}
+
+// getterCaller answers eth_call with fixed return data per method selector
+type getterCaller map[string][]byte
+
+func (g getterCaller) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return []byte{0x1}, nil
+}
+
+func (g getterCaller) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	return g[hex.EncodeToString(call.Data[:4])], nil
+}
+
+// Return data of ObscuraOracle.requests(7) for a resolved OEV request, and of
+// randomnessRequests(0) while pending, encoded with the compiled contract ABI
+const (
+	resolvedOEVRequestPayload = "000000000000000000000000000000000000000000000000000000000000000700000000000000000000000000000000000000000000000000000000000001e0000000000000000000000000111111111111111111111111111111111111111100000000000000000000000022222222222222222222222222222222222222220000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000c30000000000000000000000000000000000000000000000000000000006553f1000000000000000000000000000000000000000000000000000000000000000bb80000000000000000000000000000000000000000000000000000000000000dac0000000000000000000000000000000000000000000000000000000000000220000000000000000000000000000000000000000000000000000000000000001b68747470733a2f2f6170692e6578616d706c652e636f6d2f657468000000000000000000000000000000000000000000000000000000000000000000000000074554482f55534400000000000000000000000000000000000000000000000000"
+	pendingRandomnessPayload  = "000000000000000000000000000000000000000000000000000000000000008000000000000000000000000011111111111111111111111111111111111111110000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000047365656400000000000000000000000000000000000000000000000000000000"
+)
+
+func TestOracleRequestReaderDecodesGetters(t *testing.T) {
+	reader, err := NewOracleRequestReader(nil, "0x0000000000000000000000000000000000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	caller := getterCaller{}
+	for method, payload := range map[string]string{"requests": resolvedOEVRequestPayload, "randomnessRequests": pendingRandomnessPayload} {
+		data, _ := hex.DecodeString(payload)
+		caller[hex.EncodeToString(reader.abi.Methods[method].ID)] = data
+	}
+	reader, _ = NewOracleRequestReader(caller, "0x0000000000000000000000000000000000000001")
+
+	resolved, err := reader.IsResolved(context.Background(), big.NewInt(7))
+	if err != nil || !resolved {
+		t.Fatalf("Expected OEV request to decode as resolved, got %v %v", resolved, err)
+	}
+	resolved, err = reader.IsRandomnessResolved(context.Background(), big.NewInt(0))
+	if err != nil || resolved {
+		t.Fatalf("Expected pending randomness request, got %v %v", resolved, err)
+	}
+}
@@ -0,0 +1,164 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/obscura-network/obscura-node/oracle"
+	"github.com/obscura-network/obscura-node/storage"
+)
+
+const jobRecordPrefix = "job_record_"
+
+// JobTracker keeps one lifecycle record per job in the node's store
+type JobTracker struct {
+	store storage.Store
+	mu    sync.Mutex
+}
+
+// NewJobTracker creates a tracker backed by the given store
+func NewJobTracker(store storage.Store) *JobTracker {
+	return &JobTracker{store: store}
+}
+
+// Received records that a job entered the pipeline. A job seen before
+// (restored after restart or requeued for retry) keeps its history.
+func (jt *JobTracker) Received(job oracle.JobRequest) error {
+	jt.mu.Lock()
+	defer jt.mu.Unlock()
+
+	now := time.Now()
+	record, exists := jt.load(job.ID)
+	if !exists {
+		record = oracle.JobStatus{
+			ID:        job.ID,
+			Type:      job.Type,
+			Requester: job.Requester,
+			State:     oracle.JobStateReceived,
+			CreatedAt: now,
+		}
+	} else if record.State != oracle.JobStateReceived && !record.State.CanTransition(oracle.JobStateReceived) {
+		return fmt.Errorf("job %s cannot be received again from state %s", job.ID, record.State)
+	}
+
+	record.State = oracle.JobStateReceived
+	record.Error = ""
+	record.Attempts++
+	record.UpdatedAt = now
+	record.History = append(record.History, oracle.JobTransition{State: oracle.JobStateReceived, At: now})
+
+	return jt.save(record)
+}
+
+// Transition moves a job to the given state
+func (jt *JobTracker) Transition(jobID string, state oracle.JobState) error {
+	return jt.update(jobID, state, func(*oracle.JobStatus) {})
+}
+
+// Submitted records the fulfillment transaction hash and, for a proof, the
+// circuit version it was made for
+func (jt *JobTracker) Submitted(jobID, txHash, circuit string, version int) error {
+	return jt.update(jobID, oracle.JobStateSubmitted, func(r *oracle.JobStatus) {
+		r.TxHash = txHash
+		if circuit != "" {
+			r.Circuit = circuit
+			r.CircuitVersion = version
+		}
+	})
+}
+
+// Fail records an error and moves the job to the failed state
+func (jt *JobTracker) Fail(jobID string, cause error) error {
+	return jt.update(jobID, oracle.JobStateFailed, func(r *oracle.JobStatus) {
+		r.Error = cause.Error()
+	})
+}
+
+// Get returns the record for a job
+func (jt *JobTracker) Get(jobID string) (oracle.JobStatus, bool) {
+	jt.mu.Lock()
+	defer jt.mu.Unlock()
+	return jt.load(jobID)
+}
+
+// List returns records matching the filter, most recently updated first
+func (jt *JobTracker) List(filter oracle.JobFilter) []oracle.JobStatus {
+	var jobs []oracle.JobStatus
+	for key, data := range jt.store.GetAllJobs() {
+		if !strings.HasPrefix(key, jobRecordPrefix) {
+			continue
+		}
+		record, ok := decodeJobStatus(data)
+		if !ok || !filter.Matches(record) {
+			continue
+		}
+		jobs = append(jobs, record)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].UpdatedAt.After(jobs[j].UpdatedAt)
+	})
+
+	if filter.Limit > 0 && len(jobs) > filter.Limit {
+		jobs = jobs[:filter.Limit]
+	}
+	return jobs
+}
+
+func (jt *JobTracker) update(jobID string, state oracle.JobState, apply func(*oracle.JobStatus)) error {
+	jt.mu.Lock()
+	defer jt.mu.Unlock()
+
+	record, ok := jt.load(jobID)
+	if !ok {
+		return fmt.Errorf("unknown job: %s", jobID)
+	}
+	if !record.State.CanTransition(state) {
+		return fmt.Errorf("invalid transition for job %s: %s -> %s", jobID, record.State, state)
+	}
+
+	now := time.Now()
+	apply(&record)
+	record.State = state
+	record.UpdatedAt = now
+	record.History = append(record.History, oracle.JobTransition{State: state, At: now, Error: record.Error})
+
+	log.Debug().Str("job_id", jobID).Str("state", string(state)).Msg("Job state changed")
+	return jt.save(record)
+}
+
+func (jt *JobTracker) load(jobID string) (oracle.JobStatus, bool) {
+	data, ok := jt.store.GetJob(jobRecordPrefix + jobID)
+	if !ok {
+		return oracle.JobStatus{}, false
+	}
+	return decodeJobStatus(data)
+}
+
+func (jt *JobTracker) save(record oracle.JobStatus) error {
+	return jt.store.SaveJob(jobRecordPrefix+record.ID, record)
+}
+
+// decodeJobStatus handles both in-memory records and the generic maps
+// produced when the store is reloaded from JSON
+func decodeJobStatus(data interface{}) (oracle.JobStatus, bool) {
+	if record, ok := data.(oracle.JobStatus); ok {
+		return record, true
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return oracle.JobStatus{}, false
+	}
+	var record oracle.JobStatus
+	if err := json.Unmarshal(raw, &record); err != nil || record.ID == "" {
+		return oracle.JobStatus{}, false
+	}
+	return record, true
+}
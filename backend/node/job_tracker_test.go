@@ -0,0 +1,111 @@
+package node
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/obscura-network/obscura-node/oracle"
+	"github.com/obscura-network/obscura-node/storage"
+)
+
+func TestJobTrackerLifecycle(t *testing.T) {
+	store, err := storage.NewFileStore("./test_job_tracker.json")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer store.Clear()
+
+	jt := NewJobTracker(store)
+	job := oracle.JobRequest{
+		ID:        "1703750800",
+		Type:      oracle.JobTypeDataFeed,
+		Requester: "0x742d35Cc6634C0532925a3b844Bc9e7595f4e032",
+		Timestamp: time.Now(),
+	}
+
+	if err := jt.Received(job); err != nil {
+		t.Fatalf("Received failed: %v", err)
+	}
+	if err := jt.Transition(job.ID, oracle.JobStateFetching); err != nil {
+		t.Fatalf("Transition to fetching failed: %v", err)
+	}
+	if err := jt.Transition(job.ID, oracle.JobStateProving); err != nil {
+		t.Fatalf("Transition to proving failed: %v", err)
+	}
+	if err := jt.Submitted(job.ID, "0xabc", "range", 1); err != nil {
+		t.Fatalf("Submitted failed: %v", err)
+	}
+
+	// A submitted job cannot go back to proving
+	if err := jt.Transition(job.ID, oracle.JobStateProving); err == nil {
+		t.Error("Expected invalid transition to be rejected")
+	}
+
+	if err := jt.Transition(job.ID, oracle.JobStateConfirmed); err != nil {
+		t.Fatalf("Transition to confirmed failed: %v", err)
+	}
+
+	// Reload from disk to make sure records survive a restart
+	reloaded, err := storage.NewFileStore("./test_job_tracker.json")
+	if err != nil {
+		t.Fatalf("Failed to reload storage: %v", err)
+	}
+
+	record, ok := NewJobTracker(reloaded).Get(job.ID)
+	if !ok {
+		t.Fatal("Job record not found after reload")
+	}
+	if record.State != oracle.JobStateConfirmed {
+		t.Errorf("Expected confirmed, got %s", record.State)
+	}
+	if record.Circuit != "range" || record.CircuitVersion != 1 {
+		t.Errorf("Expected the proof tagged range v1, got %s v%d", record.Circuit, record.CircuitVersion)
+	}
+	if record.TxHash != "0xabc" {
+		t.Errorf("Expected tx hash 0xabc, got %s", record.TxHash)
+	}
+	if len(record.History) != 5 {
+		t.Errorf("Expected 5 transitions, got %d", len(record.History))
+	}
+}
+
+func TestJobTrackerFailureAndFilter(t *testing.T) {
+	store, err := storage.NewFileStore("./test_job_tracker_filter.json")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer store.Clear()
+
+	jt := NewJobTracker(store)
+	for i := 0; i < 3; i++ {
+		jt.Received(oracle.JobRequest{ID: fmt.Sprintf("vrf-%d", i), Type: oracle.JobTypeVRF})
+	}
+	jt.Received(oracle.JobRequest{ID: "feed-1", Type: oracle.JobTypeDataFeed})
+
+	if err := jt.Fail("vrf-1", fmt.Errorf("insufficient funds for gas")); err != nil {
+		t.Fatalf("Fail failed: %v", err)
+	}
+
+	failed := jt.List(oracle.JobFilter{State: oracle.JobStateFailed})
+	if len(failed) != 1 || failed[0].ID != "vrf-1" {
+		t.Fatalf("Expected only vrf-1 to be failed, got %+v", failed)
+	}
+	if failed[0].Error != "insufficient funds for gas" {
+		t.Errorf("Expected error details to be recorded, got %q", failed[0].Error)
+	}
+
+	vrfJobs := jt.List(oracle.JobFilter{Type: oracle.JobTypeVRF, Limit: 2})
+	if len(vrfJobs) != 2 {
+		t.Errorf("Expected limit to cap results at 2, got %d", len(vrfJobs))
+	}
+
+	// Retrying a failed job keeps its history and counts the attempt
+	if err := jt.Received(oracle.JobRequest{ID: "vrf-1", Type: oracle.JobTypeVRF}); err != nil {
+		t.Fatalf("Requeue failed: %v", err)
+	}
+	record, _ := jt.Get("vrf-1")
+	if record.Attempts != 2 || record.Error != "" {
+		t.Errorf("Expected 2 attempts and cleared error, got %d / %q", record.Attempts, record.Error)
+	}
+}
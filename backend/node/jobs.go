@@ -2,6 +2,7 @@ package node
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"math"
 	"math/big"
@@ -10,12 +11,15 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/rs/zerolog/log"
 
 	"github.com/obscura-network/obscura-node/adapters"
 	"github.com/obscura-network/obscura-node/ai"
 	"github.com/obscura-network/obscura-node/api"
+	ocr "github.com/obscura-network/obscura-node/consensus"
 	"github.com/obscura-network/obscura-node/functions"
 	"github.com/obscura-network/obscura-node/oracle"
 	"github.com/obscura-network/obscura-node/security"
@@ -40,10 +44,35 @@ type JobManager struct {
 	feedManager *oracle.FeedManager
 	ai          *ai.PredictiveModel
 	secrets     *storage.SecretManager
+	access      *security.AccessController
+	scheduler   *JobScheduler
+	tracker     *JobTracker
+	retries     *RetryQueue
+	guard       *FulfillmentGuard
+	porAddr     common.Address
+	porABI      abi.ABI
+	reserves    map[string]oracle.ReserveConfig
+	history     *oracle.FeedHistory
+	twaps       map[string]oracle.TWAPConfig
+	verifiers   *zkp.VerifierRegistry
+	chainID     uint64
+	// verifierCaller reads the verifier a target contract checks proofs with
+	verifierCaller bind.ContractCaller
+	functions   *functions.Registry
+	batcher     *feedBatcher
+
+	// Private endpoints selective disclosures may attest over
+	disclosureSources map[string]bool
+
+	// Optional commit/reveal agreement on compute outputs
+	consensus        *ocr.OCRManager
+	consensusTimeout time.Duration
 }
 
 const OracleWriteABI = `[
 	{"inputs":[{"internalType":"uint256","name":"requestId","type":"uint256"},{"internalType":"uint256","name":"value","type":"uint256"},{"internalType":"uint256[8]","name":"zkpProof","type":"uint256[8]"},{"internalType":"uint256[2]","name":"publicInputs","type":"uint256[2]"}],"name":"fulfillData","outputs":[],"stateMutability":"nonpayable","type":"function"},
+	{"inputs":[{"internalType":"uint256[]","name":"requestIds","type":"uint256[]"},{"internalType":"uint256[]","name":"values","type":"uint256[]"},{"internalType":"uint256[8]","name":"zkpProof","type":"uint256[8]"},{"internalType":"uint256[2]","name":"commitments","type":"uint256[2]"},{"internalType":"uint256[2]","name":"commitmentPok","type":"uint256[2]"}],"name":"fulfillDataBatch","outputs":[],"stateMutability":"nonpayable","type":"function"},
+	{"inputs":[{"internalType":"uint256","name":"requestId","type":"uint256"},{"internalType":"bool","name":"satisfied","type":"bool"},{"internalType":"uint256[8]","name":"zkpProof","type":"uint256[8]"}],"name":"fulfillCompute","outputs":[],"stateMutability":"nonpayable","type":"function"},
 	{"inputs":[{"internalType":"uint256","name":"requestId","type":"uint256"},{"internalType":"uint256","name":"value","type":"uint256"}],"name":"fulfillDataOptimistic","outputs":[],"stateMutability":"nonpayable","type":"function"},
 	{"inputs":[{"internalType":"uint256","name":"requestId","type":"uint256"},{"internalType":"uint256","name":"randomness","type":"uint256"},{"internalType":"bytes","name":"proof","type":"bytes"}],"name":"fulfillRandomness","outputs":[],"stateMutability":"nonpayable","type":"function"}
 ]`
@@ -55,7 +84,7 @@ func NewJobManager(am *adapters.AdapterManager, txMgr *TxManager, vrfMgr *vrf.Ra
 		return nil, err
 	}
  
-	return &JobManager{
+	jm := &JobManager{
 		JobQueue:    make(chan oracle.JobRequest, 100),
 		adapters:    am,
 		txMgr:       txMgr,
@@ -69,11 +98,40 @@ func NewJobManager(am *adapters.AdapterManager, txMgr *TxManager, vrfMgr *vrf.Ra
 		feedManager: fm,
 		ai:          aiModel,
 		secrets:     sm,
-	}, nil
+	}
+	jm.SetSchedulerConfig(DefaultSchedulerConfig())
+
+	return jm, nil
+}
+
+// SetSchedulerConfig replaces the worker pool configuration. Must be called before Start.
+func (jm *JobManager) SetSchedulerConfig(config SchedulerConfig) {
+	jm.scheduler = NewJobScheduler(config)
+	jm.scheduler.onDepthChange = jm.recordQueueDepth
+}
+
+// SetJobTracker enables lifecycle tracking of every job
+func (jm *JobManager) SetJobTracker(jt *JobTracker) {
+	jm.tracker = jt
+}
+
+// SetRetryQueue enables retries with backoff for failed jobs
+func (jm *JobManager) SetRetryQueue(rq *RetryQueue) {
+	jm.retries = rq
+}
+
+// SetFulfillmentGuard makes fulfillment idempotent across restarts and retries
+func (jm *JobManager) SetFulfillmentGuard(fg *FulfillmentGuard) {
+	jm.guard = fg
+}
+
+// SetAccessController enables tier-based priority scheduling
+func (jm *JobManager) SetAccessController(ac *security.AccessController) {
+	jm.access = ac
 }
 
-// Dispatch adds a job to the queue
-func (jm *JobManager) Dispatch(job oracle.JobRequest) {
+// Dispatch adds a job to the queue, returning ErrQueueFull if it was shed
+func (jm *JobManager) Dispatch(job oracle.JobRequest) error {
 	// Persist before dispatching
 	if jm.persistence != nil {
 		if err := jm.persistence.SavePendingJob(job); err != nil {
@@ -81,8 +139,79 @@ func (jm *JobManager) Dispatch(job oracle.JobRequest) {
 		}
 	}
 
-	jm.JobQueue <- job
+	if err := jm.enqueue(job); err != nil {
+		if jm.persistence != nil {
+			if perr := jm.persistence.MarkJobRejected(job.ID, err.Error()); perr != nil {
+				log.Error().Err(perr).Str("job_id", job.ID).Msg("Failed to record job rejection")
+			}
+		}
+		return err
+	}
+
 	log.Info().Str("job_id", job.ID).Str("type", string(job.Type)).Msg("Job submitted")
+	return nil
+}
+
+// enqueue hands a job to the scheduler on the lane matching its priority
+func (jm *JobManager) enqueue(job oracle.JobRequest) error {
+	if jm.tracker != nil {
+		if err := jm.tracker.Received(job); err != nil {
+			log.Warn().Err(err).Str("job_id", job.ID).Msg("Failed to record received job")
+		}
+	}
+
+	priority := jm.priorityFor(job)
+	if err := jm.scheduler.Submit(job, priority); err != nil {
+		jm.trackFailure(job.ID, err)
+		log.Warn().
+			Str("job_id", job.ID).
+			Str("type", string(job.Type)).
+			Str("lane", priority.String()).
+			Msg("Job rejected: queue full")
+		if jm.metrics != nil {
+			jm.metrics.IncrementJobsRejected()
+		}
+		return err
+	}
+	return nil
+}
+
+// priorityFor places OEV auctions and premium-tier requesters in the high lane
+func (jm *JobManager) priorityFor(job oracle.JobRequest) JobPriority {
+	if job.OEVEnabled {
+		return PriorityHigh
+	}
+	if jm.access != nil {
+		if consumer, ok := jm.access.GetConsumer(job.Requester); ok && consumer.Active && consumer.Tier.HasPriority() {
+			return PriorityHigh
+		}
+	}
+	return PriorityNormal
+}
+
+// trackState records a lifecycle transition, logging rather than failing the job on error
+func (jm *JobManager) trackState(jobID string, state oracle.JobState) {
+	if jm.tracker == nil {
+		return
+	}
+	if err := jm.tracker.Transition(jobID, state); err != nil {
+		log.Warn().Err(err).Str("job_id", jobID).Msg("Failed to record job state")
+	}
+}
+
+func (jm *JobManager) trackFailure(jobID string, cause error) {
+	if jm.tracker == nil {
+		return
+	}
+	if err := jm.tracker.Fail(jobID, cause); err != nil {
+		log.Warn().Err(err).Str("job_id", jobID).Msg("Failed to record job failure")
+	}
+}
+
+func (jm *JobManager) recordQueueDepth(lane string, depth int) {
+	if jm.metrics != nil {
+		jm.metrics.SetQueueDepth(lane, depth)
+	}
 }
 
 // Start begins processing jobs from the queue
@@ -100,18 +229,32 @@ func (jm *JobManager) Start(ctx context.Context) {
 		if err == nil {
 			for _, job := range pending {
 				log.Info().Str("job_id", job.ID).Msg("Restoring pending job from storage")
-				jm.JobQueue <- job
+				if err := jm.enqueue(job); err != nil {
+					log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to restore pending job")
+				}
 			}
 		}
 	}
 
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		jm.scheduler.Run(ctx, jm.processJob)
+	}()
+
+	// JobQueue remains the intake for internal producers such as automation triggers
 	for {
 		select {
 		case <-ctx.Done():
 			log.Info().Msg("Job Manager stopping")
+			wg.Wait()
 			return
 		case job := <-jm.JobQueue:
-			go jm.processJob(ctx, job) // Process in goroutine for concurrency
+			// Dispatch persists the job and records the rejection if it is shed
+			if err := jm.Dispatch(job); err != nil {
+				log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to dispatch job from internal producer")
+			}
 		}
 	}
 }
@@ -119,52 +262,135 @@ func (jm *JobManager) Start(ctx context.Context) {
 func (jm *JobManager) processJob(ctx context.Context, job oracle.JobRequest) {
 	log.Info().Str("job_id", job.ID).Str("type", string(job.Type)).Msg("Processing Job")
 	
+	fulfilled, err := jm.alreadyFulfilled(ctx, job)
+	if err == nil && !fulfilled {
+		err = jm.runJob(ctx, job)
+	}
+
+	if err != nil {
+		log.Error().Err(err).Str("job_id", job.ID).Msg("Job failed")
+		jm.trackFailure(job.ID, err)
+		jm.scheduleRetry(job, err)
+	} else if jm.retries != nil {
+		if err := jm.retries.ClearRetry(job.ID); err != nil {
+			log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to clear retry entry")
+		}
+	}
+
+	// Mark as completed in persistence
+	if jm.persistence != nil {
+		if err := jm.persistence.MarkJobCompleted(job.ID); err != nil {
+			log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to mark job as completed in storage")
+		}
+	}
+}
+
+func (jm *JobManager) runJob(ctx context.Context, job oracle.JobRequest) error {
 	switch job.Type {
 	case oracle.JobTypeDataFeed:
-		jm.handleDataFeed(ctx, job)
+		return jm.handleDataFeed(ctx, job)
 	case oracle.JobTypeVRF:
-		jm.handleVRF(ctx, job)
+		return jm.handleVRF(ctx, job)
 	case oracle.JobTypeCompute:
-		jm.handleCompute(ctx, job)
+		return jm.handleCompute(ctx, job)
+	case oracle.JobTypeProofOfReserves:
+		return jm.handleProofOfReserves(ctx, job)
+	case oracle.JobTypeTWAP:
+		return jm.handleTWAP(ctx, job)
+	case oracle.JobTypeSelectiveDisclosure:
+		return jm.handleSelectiveDisclosure(ctx, job)
 	default:
 		log.Warn().Str("type", string(job.Type)).Msg("Unknown job type")
+		return permanent(fmt.Errorf("unknown job type: %s", job.Type))
 	}
+}
 
-	// Mark as completed in persistence
-	if jm.persistence != nil {
-		if err := jm.persistence.MarkJobCompleted(job.ID); err != nil {
-			log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to mark job as completed in storage")
+// alreadyFulfilled consults the fulfillment guard so a job replayed after a crash
+// or retry is never fulfilled twice
+func (jm *JobManager) alreadyFulfilled(ctx context.Context, job oracle.JobRequest) (bool, error) {
+	if jm.guard == nil {
+		return false, nil
+	}
+
+	decision, txHash, err := jm.guard.Check(ctx, job.ID)
+	if err != nil {
+		return false, fmt.Errorf("cannot verify fulfillment status: %w", err)
+	}
+
+	switch decision {
+	case FulfillmentInFlight:
+		log.Info().Str("job_id", job.ID).Str("tx_hash", txHash.Hex()).Msg("Fulfillment already in flight, awaiting confirmation")
+		if jm.tracker != nil {
+			jm.tracker.Submitted(job.ID, txHash.Hex(), "", 0)
+		}
+		go jm.awaitConfirmation(ctx, job.ID, txHash)
+		return true, nil
+	case FulfillmentDone:
+		log.Info().Str("job_id", job.ID).Msg("Request already fulfilled on-chain, skipping")
+		if jm.tracker != nil && txHash != (common.Hash{}) {
+			jm.tracker.Submitted(job.ID, txHash.Hex(), "", 0)
 		}
+		jm.trackState(job.ID, oracle.JobStateConfirmed)
+		return true, nil
 	}
+	return false, nil
 }
 
-func (jm *JobManager) handleDataFeed(ctx context.Context, job oracle.JobRequest) {
-	// 1. Fetch Data
-	url, _ := job.Params["url"].(string)
-	
-	// Feature #5: Inject Authentication for Private Sources
-	headers := make(map[string]string)
-	if cred, ok := jm.secrets.GetCredential(url); ok {
-		log.Info().Str("url", url).Msg("First-Party Authenticated Source Detected. Injecting Vault Credentials.")
-		// In real usage we'd parse the header vs key, simplified for demo
-		headers["Authorization"] = cred
-	}
-
-	result, err := jm.adapters.Fetch(adapters.FetchDataRequest{
-		URL:      url,
-		Method:   "GET",
-		Path:     "price", 
-		Obscured: false,
-		Headers:  headers,
-		Retries:  3,
-	})
+// scheduleRetry queues a failed job for another attempt, or dead-letters it when
+// the failure is permanent or its retries are exhausted
+func (jm *JobManager) scheduleRetry(job oracle.JobRequest, cause error) {
+	if jm.retries == nil {
+		return
+	}
+
+	deadLettered := true
+	var err error
+	if IsRetryable(cause) {
+		deadLettered, err = jm.retries.ScheduleRetry(job, cause.Error())
+	} else {
+		err = jm.retries.DeadLetter(job, cause.Error())
+	}
 	if err != nil {
-		log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to fetch external data")
-		jm.repMgr.UpdateReputation("self", -1.0)
+		log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to schedule retry")
 		return
 	}
 
-	log.Info().Interface("result", result).Msg("Data Fetched")
+	if deadLettered {
+		log.Warn().Str("job_id", job.ID).Bool("retryable", IsRetryable(cause)).Msg("Job moved to dead letter queue")
+		jm.trackState(job.ID, oracle.JobStateDeadLettered)
+	}
+}
+
+func (jm *JobManager) handleDataFeed(ctx context.Context, job oracle.JobRequest) error {
+	// 1. Fetch Data from every configured source
+	jm.trackState(job.ID, oracle.JobStateFetching)
+	sources, method, minResponses := jm.resolveDataSources(job)
+	target := sources[0].URL
+
+	feedID := job.ID
+	if id, ok := job.Params["feed_id"].(string); ok && id != "" {
+		feedID = id
+		target = id
+	}
+
+	agg, err := jm.fetchAndAggregate(job.ID, sources, method, minResponses)
+	if err != nil {
+		jm.repMgr.UpdateReputation("self", -1.0)
+		return fmt.Errorf("failed to fetch external data: %w", err)
+	}
+
+	log.Info().
+		Float64("result", agg.Value).
+		Int("sources", agg.Responses).
+		Int("outliers", agg.Outliers).
+		Msg("Data Fetched")
+
+	if jm.metrics != nil {
+		jm.metrics.IncrementAggregationsCompleted()
+		for i := 0; i < agg.Outliers; i++ {
+			jm.metrics.IncrementOutliersDetected()
+		}
+	}
  
 	if job.OEVEnabled {
 		log.Info().Str("beneficiary", job.OEVBeneficiary).Msg("OEV RECAPTURE ACTIVE - Processing high-value priority feed")
@@ -173,19 +399,16 @@ func (jm *JobManager) handleDataFeed(ctx context.Context, job oracle.JobRequest)
 		}
 	}
 
-	valFloat, ok := result.(float64)
-	if !ok {
-		log.Error().Msg("Result is not a float number")
-		return 
-	}
+	valFloat := agg.Value
 	
 	// Standardizing to 8 decimal places for price feeds
-	valInt := new(big.Int).SetUint64(uint64(valFloat * 1e8))
+	valInt := new(big.Int).SetUint64(uint64(valFloat * feedScale))
+	jm.recordObservation(feedID, valInt, time.Now())
 	
 	// 1.5 Update Local Feed Tracking with Stats (Feature #4)
 	if jm.feedManager != nil {
-		jm.ai.AddDataPoint(job.ID, valFloat)
-		volatility := jm.ai.PredictVolatility(job.ID)
+		jm.ai.AddDataPoint(feedID, valFloat)
+		volatility := jm.ai.PredictVolatility(feedID)
 		
 		// Confidence calculation: 100% minus relative volatility
 		conf := 100.0
@@ -193,19 +416,18 @@ func (jm *JobManager) handleDataFeed(ctx context.Context, job oracle.JobRequest)
 			conf = math.Max(0, 100.0-(volatility/valFloat*100.0))
 		}
 
-		// Outlier Detection: If value is more than 2x standard deviation from recent volatility
-		// (Simplified Z-score check)
-		outliers := 0
-		if volatility > 0 && math.Abs(valFloat - (valFloat - volatility)) > 2*volatility {
-			outliers = 1
-			log.Warn().Str("feed", job.ID).Float64("value", valFloat).Msg("Potential Outlier Detected!")
+		if agg.Outliers > 0 {
+			log.Warn().Str("feed", feedID).Int("outliers", agg.Outliers).Msg("Outlier sources filtered from aggregation")
 		}
 
 		jm.feedManager.UpdateFeedValue(oracle.FeedLiveStatus{
-			ID:                 job.ID,
+			ID:                 feedID,
 			Value:              fmt.Sprintf("$%.2f", valFloat),
+			Answer:             valFloat,
 			Confidence:         conf,
-			Outliers:           outliers, 
+			Outliers:           agg.Outliers,
+			Sources:            agg.Responses,
+			SourceAgreement:    agg.Agreement,
 			RoundID:            0, 
 			Timestamp:          time.Now(),
 			IsZK:               true,
@@ -216,12 +438,13 @@ func (jm *JobManager) handleDataFeed(ctx context.Context, job oracle.JobRequest)
 
 	if job.IsOptimistic {
 		log.Info().Str("job_id", job.ID).Msg("Optimistic Mode Active - Skipping ZK proof for initial fulfillment")
-		jm.submitFulfillmentOptimistic(ctx, job.ID, valInt)
-		return
+		_, err := jm.submitFulfillmentOptimistic(ctx, job.ID, valInt)
+		return err
 	}
 
 	// 2. Generate ZK Proof
 	log.Info().Str("job_id", job.ID).Msg("Generating Zero-Knowledge Range Proof")
+	jm.trackState(job.ID, oracle.JobStateProving)
 	
 	// Range verification: value ± 10% or similar. For demo/MVP using hardcoded range or job params.
 	minInt := toBigInt(job.Params["min"])
@@ -229,104 +452,198 @@ func (jm *JobManager) handleDataFeed(ctx context.Context, job oracle.JobRequest)
 	if minInt == nil { minInt = new(big.Int).Sub(valInt, big.NewInt(1000000)) }
 	if maxInt == nil { maxInt = new(big.Int).Add(valInt, big.NewInt(1000000)) }
 
-	proof, err := zkp.GenerateRangeProof(valInt, minInt, maxInt)
-	if err != nil {
-		log.Error().Err(err).Msg("ZK Proof Generation failed")
-		return
-	}
+	if jm.batchable(job, valInt, minInt, maxInt) {
+		// 2b. Prove and submit together with other feed updates
+		update := zkp.RangeUpdate{Value: valInt, Min: minInt, Max: maxInt}
+		if err := jm.batcher.Submit(ctx, job.ID, update); err != nil {
+			return err
+		}
+	} else {
+		proof, err := zkp.GenerateRangeProofContext(ctx, valInt, minInt, maxInt)
+		if err != nil {
+			return proofFailure(fmt.Errorf("ZK proof generation failed: %w", err))
+		}
 
-	serialized, err := zkp.SerializeProof(proof)
-	if err != nil {
-		log.Error().Err(err).Msg("Proof serialization failed")
-		return
-	}
+		serialized, err := zkp.SerializeProof(proof)
+		if err != nil {
+			return permanent(fmt.Errorf("proof serialization failed: %w", err))
+		}
 
-	// 3. Submit to Blockchain
-	jm.submitFulfillment(ctx, job.ID, valInt, serialized, [2]*big.Int{minInt, maxInt})
+		// 3. Submit to Blockchain
+		if _, err := jm.submitFulfillment(ctx, job.ID, valInt, serialized, [2]*big.Int{minInt, maxInt}); err != nil {
+			return err
+		}
+	}
 
 	// Update Job History for Dashboard
 	jm.metrics.AddJobRecord(api.JobRecord{
 		ID:        job.ID,
 		Type:      "Data Feed",
-		Target:    url,
+		Target:    target,
 		Status:    "Fulfilled",
 		Hash:      "0x" + job.ID[:8] + "...data",
 		RoundID:   0,
 		Timestamp: time.Now(),
 	})
+	return nil
 }
 
-func (jm *JobManager) submitFulfillment(ctx context.Context, jobIDStr string, value *big.Int, proof [8]*big.Int, pubInputs [2]*big.Int) {
+// submitFulfillment posts a value with its range proof
+func (jm *JobManager) submitFulfillment(ctx context.Context, jobIDStr string, value *big.Int, proof [8]*big.Int, pubInputs [2]*big.Int) (common.Hash, error) {
 	// Parse ID
 	reqID := new(big.Int)
 	reqID.SetString(jobIDStr, 10)
 
+	tag, err := jm.circuitVersion(ctx, "range", jm.oracleAddr)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
 	// Pack Data
 	data, err := jm.oracleABI.Pack("fulfillData", reqID, value, proof, pubInputs)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to pack ABI")
-		return
+		return common.Hash{}, permanent(fmt.Errorf("failed to pack ABI: %w", err))
 	}
 
-	txHash, err := jm.txMgr.SendTransaction(ctx, jm.oracleAddr, data, big.NewInt(0))
+	txHash, err := jm.sendToJobs(ctx, []string{jobIDStr}, jm.oracleAddr, data, tag)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to send fulfillment transaction")
-		return
+		return common.Hash{}, err
 	}
 
-	log.Info().Str("tx_hash", txHash.Hex()).Msg("Fulfillment Transaction Sent")
+	log.Info().Str("tx_hash", txHash.Hex()).Int("circuit_version", tag.Version).Msg("Fulfillment Transaction Sent")
 
 	// Note: The AddJobRecord for DataFeed is now handled directly in handleDataFeed
 	// to ensure 'url' and 'job.ID' are in scope.
-	// This function is also used by handleCompute, which will add its own record.
+	return txHash, nil
 }
 
-func (jm *JobManager) submitFulfillmentOptimistic(ctx context.Context, jobIDStr string, value *big.Int) {
+func (jm *JobManager) submitFulfillmentOptimistic(ctx context.Context, jobIDStr string, value *big.Int) (common.Hash, error) {
 	reqID := new(big.Int)
 	reqID.SetString(jobIDStr, 10)
 
 	data, err := jm.oracleABI.Pack("fulfillDataOptimistic", reqID, value)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to pack fulfillDataOptimistic")
-		return
+		return common.Hash{}, permanent(fmt.Errorf("failed to pack fulfillDataOptimistic: %w", err))
 	}
 
-	txHash, err := jm.txMgr.SendTransaction(ctx, jm.oracleAddr, data, big.NewInt(0))
+	txHash, err := jm.sendFulfillment(ctx, jobIDStr, data)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to send optimistic fulfillment")
-		return
+		return common.Hash{}, err
 	}
 
 	log.Info().Str("tx_hash", txHash.Hex()).Msg("Optimistic Fulfillment Sent (Challenge Window Open)")
+	return txHash, nil
+}
+
+// sendFulfillment sends a packed fulfillment call and tracks the job until the
+// transaction is mined
+func (jm *JobManager) sendFulfillment(ctx context.Context, jobID string, data []byte) (common.Hash, error) {
+	return jm.sendTo(ctx, jobID, jm.oracleAddr, data)
+}
+
+// sendTo sends a packed call to any contract the node reports to, journaled
+// and tracked like an oracle fulfillment
+func (jm *JobManager) sendTo(ctx context.Context, jobID string, to common.Address, data []byte) (common.Hash, error) {
+	return jm.sendToJobs(ctx, []string{jobID}, to, data, proofTag{})
 }
 
-func (jm *JobManager) handleVRF(ctx context.Context, job oracle.JobRequest) {
+// sendToJobs sends one call fulfilling several jobs, journaling and tracking
+// each of them against its transaction and the circuit version of its proof
+func (jm *JobManager) sendToJobs(ctx context.Context, jobIDs []string, to common.Address, data []byte, tag proofTag) (common.Hash, error) {
+	var journal func(common.Hash) error
+	if jm.guard != nil {
+		journal = func(hash common.Hash) error {
+			for _, jobID := range jobIDs {
+				if err := jm.guard.Journal(jobID, hash); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	txHash, err := jm.txMgr.SendJournaledTransaction(ctx, to, data, big.NewInt(0), journal)
+	if err != nil {
+		if jm.metrics != nil {
+			jm.metrics.IncrementTransactionsFailed()
+		}
+		return common.Hash{}, fmt.Errorf("failed to send fulfillment transaction: %w", err)
+	}
+
+	if jm.metrics != nil {
+		jm.metrics.IncrementTransactionsSent()
+	}
+
+	for _, jobID := range jobIDs {
+		if jm.tracker != nil {
+			if err := jm.tracker.Submitted(jobID, txHash.Hex(), tag.Circuit, tag.Version); err != nil {
+				log.Warn().Err(err).Str("job_id", jobID).Msg("Failed to record submission")
+			}
+		}
+		if jm.tracker != nil || jm.guard != nil {
+			go jm.awaitConfirmation(ctx, jobID, txHash)
+		}
+	}
+
+	return txHash, nil
+}
+
+// awaitConfirmation moves a submitted job to confirmed or failed once its transaction is mined
+func (jm *JobManager) awaitConfirmation(ctx context.Context, jobID string, txHash common.Hash) {
+	receipt, err := jm.txMgr.WaitForReceipt(ctx, txHash)
+	if err != nil {
+		// Shutdown before the receipt arrived; the record stays in submitted
+		return
+	}
+
+	state := TxStateConfirmed
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		state = TxStateReverted
+	}
+	if jm.guard != nil {
+		if err := jm.guard.Record(jobID, txHash, state); err != nil {
+			log.Error().Err(err).Str("job_id", jobID).Msg("Failed to journal fulfillment outcome")
+		}
+	}
+
+	if state == TxStateReverted {
+		jm.trackFailure(jobID, fmt.Errorf("fulfillment transaction %s reverted", txHash.Hex()))
+		return
+	}
+	jm.trackState(jobID, oracle.JobStateConfirmed)
+}
+
+func (jm *JobManager) handleVRF(ctx context.Context, job oracle.JobRequest) error {
 	seed, _ := job.Params["seed"].(string)
 	
+	jm.trackState(job.ID, oracle.JobStateProving)
 	valStr, proofStr, err := jm.vrfMgr.GenerateRandomness(seed)
 	if err != nil {
-		log.Error().Err(err).Msg("VRF Generation failed")
-		return
+		return fmt.Errorf("VRF generation failed: %w", err)
 	}
 
 	randomValue := new(big.Int)
 	randomValue.SetString(valStr, 10)
 	
-	// Convert proof hex to bytes
-	// Note: job.ID is the string decimal ID
-	reqID := new(big.Int)
-	reqID.SetString(job.ID, 10)
+	// The ECVRF proof pi lets consumers check randomValue against the node's VRF key
+	proof, err := hex.DecodeString(proofStr)
+	if err != nil {
+		return permanent(fmt.Errorf("invalid VRF proof encoding: %w", err))
+	}
 
-	data, err := jm.oracleABI.Pack("fulfillRandomness", reqID, randomValue, []byte(proofStr))
+	reqID, ok := randomnessRequestID(job.ID)
+	if !ok {
+		return permanent(fmt.Errorf("invalid VRF job ID %q", job.ID))
+	}
+
+	data, err := jm.oracleABI.Pack("fulfillRandomness", reqID, randomValue, proof)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to pack fulfillRandomness")
-		return
+		return permanent(fmt.Errorf("failed to pack fulfillRandomness: %w", err))
 	}
 
-	txHash, err := jm.txMgr.SendTransaction(ctx, jm.oracleAddr, data, big.NewInt(0))
+	txHash, err := jm.sendFulfillment(ctx, job.ID, data)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to send fulfillment transaction")
-		return
+		return err
 	}
 
 	log.Info().Str("tx_hash", txHash.Hex()).Msg("VRF Fulfillment Transaction Sent")
@@ -341,6 +658,7 @@ func (jm *JobManager) handleVRF(ctx context.Context, job oracle.JobRequest) {
 		RoundID:   0,
 		Timestamp: time.Now(),
 	})
+	return nil
 }
 
 func toBigInt(val interface{}) *big.Int {
@@ -359,44 +677,3 @@ func toBigInt(val interface{}) *big.Int {
 	}
 	return nil
 }
-
-func (jm *JobManager) handleCompute(ctx context.Context, job oracle.JobRequest) {
-	// 1. Execute Private Logic (Simulated for Phase 2)
-	// In a real scenario, this would fetch from a private API or TEE
-	secretValue := big.NewInt(75000) // Assume secret data is $75k
-	threshold := toBigInt(job.Params["threshold"])
-	if threshold == nil { threshold = big.NewInt(50000) }
-
-	log.Info().Str("job_id", job.ID).Msg("Executing Confidential Computation...")
-
-	// 2. Generate ZK Proof of Computation
-	// Prove that secretValue >= threshold without revealing secretValue
-	proof, err := zkp.GeneratePrivateComputationProof(secretValue, threshold, 0)
-	if err != nil {
-		log.Error().Err(err).Msg("Private ZK Proof Generation failed")
-		return
-	}
-
-	serialized, err := zkp.SerializeProof(proof)
-	if err != nil {
-		log.Error().Err(err).Msg("Proof serialization failed")
-		return
-	}
-
-	// 3. Submit Proof to Blockchain
-	// In this mode, we reveal the 'threshold' as public input, but 'secretValue' remains hidden
-	jm.submitFulfillment(ctx, job.ID, big.NewInt(1), serialized, [2]*big.Int{threshold, big.NewInt(0)})
-	
-	log.Info().Str("job_id", job.ID).Msg("Confidential Compute Proof Generated and Dispatched")
-
-	// Update Job History for Dashboard
-	jm.metrics.AddJobRecord(api.JobRecord{
-		ID:        job.ID,
-		Type:      "Private Compute",
-		Target:    "ZK-Runtime",
-		Status:    "Proven",
-		Hash:      "0x" + job.ID[:8] + "...zkp",
-		RoundID:   0,
-		Timestamp: time.Now(),
-	})
-}
@@ -30,7 +30,8 @@ type EventListener struct {
 // Hardcoded ABI for Event Parsing (Partial)
 const OracleEventABI = `[
 	{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint256","name":"requestId","type":"uint256"},{"indexed":false,"internalType":"string","name":"apiUrl","type":"string"},{"indexed":false,"internalType":"uint256","name":"min","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"max","type":"uint256"},{"indexed":true,"internalType":"address","name":"requester","type":"address"},{"indexed":false,"internalType":"bool","name":"oevEnabled","type":"bool"},{"indexed":false,"internalType":"address","name":"oevBeneficiary","type":"address"},{"indexed":false,"internalType":"bool","name":"isOptimistic","type":"bool"}],"name":"RequestData","type":"event"},
-	{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint256","name":"requestId","type":"uint256"},{"indexed":false,"internalType":"string","name":"seed","type":"string"},{"indexed":true,"internalType":"address","name":"requester","type":"address"}],"name":"RandomnessRequested","type":"event"}
+	{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint256","name":"requestId","type":"uint256"},{"indexed":false,"internalType":"string","name":"seed","type":"string"},{"indexed":true,"internalType":"address","name":"requester","type":"address"}],"name":"RandomnessRequested","type":"event"},
+	{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint256","name":"requestId","type":"uint256"},{"indexed":false,"internalType":"bytes32","name":"functionHash","type":"bytes32"},{"indexed":false,"internalType":"string[]","name":"inputUrls","type":"string[]"},{"indexed":false,"internalType":"string[]","name":"inputPaths","type":"string[]"},{"indexed":false,"internalType":"uint8","name":"decimals","type":"uint8"},{"indexed":false,"internalType":"uint256","name":"threshold","type":"uint256"},{"indexed":true,"internalType":"address","name":"requester","type":"address"}],"name":"ComputeRequested","type":"event"}
 ]`
 
 // NewEventListener creates a new listener
@@ -125,7 +126,8 @@ func (el *EventListener) handleLog(vLog types.Log) {
 
 	switch event.Name {
 	case "RequestData":
-		// requestId := new(big.Int).SetBytes(vLog.Topics[1].Bytes()) // Not used in new logic
+		// requestId and requester are indexed, so they are topics, not data
+		id := new(big.Int).SetBytes(vLog.Topics[1].Bytes()).String()
 		requester := common.BytesToAddress(vLog.Topics[2].Bytes())
 
 		vals, err := el.oracleABI.Unpack("RequestData", vLog.Data)
@@ -134,15 +136,14 @@ func (el *EventListener) handleLog(vLog types.Log) {
 			return
 		}
 		
-		id := vals[0].(*big.Int).String()
-		url := vals[1].(string)
-		min := vals[2].(*big.Int)
-		max := vals[3].(*big.Int)
-		oevEnabled := vals[4].(bool)
-		oevBeneficiary := vals[5].(common.Address)
-		isOptimistic := vals[6].(bool)
+		url := vals[0].(string)
+		min := vals[1].(*big.Int)
+		max := vals[2].(*big.Int)
+		oevEnabled := vals[3].(bool)
+		oevBeneficiary := vals[4].(common.Address)
+		isOptimistic := vals[5].(bool)
 		
-		el.JobManager.Dispatch(oracle.JobRequest{
+		if err := el.JobManager.Dispatch(oracle.JobRequest{
 			ID:             id,
 			Type:           oracle.JobTypeDataFeed,
 			Params:         map[string]interface{}{"url": url, "min": min, "max": max},
@@ -151,10 +152,13 @@ func (el *EventListener) handleLog(vLog types.Log) {
 			OEVEnabled:     oevEnabled,
 			OEVBeneficiary: oevBeneficiary.Hex(),
 			IsOptimistic:   isOptimistic,
-		})
+		}); err != nil {
+			log.Error().Err(err).Str("request_id", id).Msg("Data request rejected")
+		}
 
 	case "RandomnessRequested":
-		// requestId := new(big.Int).SetBytes(vLog.Topics[1].Bytes()) // Not used in new logic
+		// Randomness requests are numbered apart from data requests
+		id := vrfJobID(new(big.Int).SetBytes(vLog.Topics[1].Bytes()))
 		requester := common.BytesToAddress(vLog.Topics[2].Bytes())
 
 		vals, err := el.oracleABI.Unpack("RandomnessRequested", vLog.Data)
@@ -163,16 +167,27 @@ func (el *EventListener) handleLog(vLog types.Log) {
 			return
 		}
 		
-		id := vals[0].(*big.Int).String()
-		seed := vals[1].(string)
+		seed := vals[0].(string)
 		
-		el.JobManager.Dispatch(oracle.JobRequest{
+		if err := el.JobManager.Dispatch(oracle.JobRequest{
 			ID:        id,
 			Type:      oracle.JobTypeVRF,
 			Params:    map[string]interface{}{"seed": seed},
 			Requester: requester.Hex(),
 			Timestamp: time.Now(),
-		})
+		}); err != nil {
+			log.Error().Err(err).Str("request_id", id).Msg("Randomness request rejected")
+		}
+
+	case "ComputeRequested":
+		job, err := el.computeJob(vLog)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to unpack ComputeRequested")
+			return
+		}
+		if err := el.JobManager.Dispatch(job); err != nil {
+			log.Error().Err(err).Str("request_id", job.ID).Msg("Compute request rejected")
+		}
 	}
 	
 	// Mark event as processed
@@ -180,3 +195,39 @@ func (el *EventListener) handleLog(vLog types.Log) {
 		el.reorgProtector.MarkEventProcessed(vLog.BlockNumber, vLog.TxHash, vLog.Index)
 	}
 }
+
+// computeJob decodes a ComputeRequested event. The function is referenced by
+// the SHA-256 of its module, which must be in the node's function registry.
+func (el *EventListener) computeJob(vLog types.Log) (oracle.JobRequest, error) {
+	if len(vLog.Topics) < 3 {
+		return oracle.JobRequest{}, fmt.Errorf("expected 3 topics, got %d", len(vLog.Topics))
+	}
+	vals, err := el.oracleABI.Unpack("ComputeRequested", vLog.Data)
+	if err != nil {
+		return oracle.JobRequest{}, err
+	}
+
+	functionHash := vals[0].([32]byte)
+	urls := vals[1].([]string)
+	paths := vals[2].([]string)
+	if len(urls) != len(paths) {
+		return oracle.JobRequest{}, fmt.Errorf("%d input urls but %d paths", len(urls), len(paths))
+	}
+	inputs := make([]oracle.DataSource, len(urls))
+	for i := range urls {
+		inputs[i] = oracle.DataSource{URL: urls[i], Path: paths[i]}
+	}
+
+	return oracle.JobRequest{
+		ID:   new(big.Int).SetBytes(vLog.Topics[1].Bytes()).String(),
+		Type: oracle.JobTypeCompute,
+		Params: map[string]interface{}{
+			"function":  common.Bytes2Hex(functionHash[:]),
+			"inputs":    inputs,
+			"decimals":  int(vals[3].(uint8)),
+			"threshold": vals[4].(*big.Int),
+		},
+		Requester: common.BytesToAddress(vLog.Topics[2].Bytes()).Hex(),
+		Timestamp: time.Now(),
+	}, nil
+}
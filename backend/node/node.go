@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
 	"sync"
 	"syscall"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"github.com/obscura-network/obscura-node/ai"
 	"github.com/obscura-network/obscura-node/api"
 	"github.com/obscura-network/obscura-node/automation"
+	ocr "github.com/obscura-network/obscura-node/consensus"
 	"github.com/obscura-network/obscura-node/crosschain"
 	"github.com/obscura-network/obscura-node/functions"
 	"github.com/obscura-network/obscura-node/security"
@@ -25,6 +27,7 @@ import (
 	"github.com/obscura-network/obscura-node/storage"
 	"github.com/obscura-network/obscura-node/vrf"
 	"github.com/obscura-network/obscura-node/oracle"
+	"github.com/obscura-network/obscura-node/zkp"
 )
 
 // Config holds the configuration for the Obscura Node
@@ -33,8 +36,75 @@ type Config struct {
 	LogLevel      string `mapstructure:"log_level"`
 	EthereumURL   string `mapstructure:"ethereum_url"`
 	PrivateKey    string `mapstructure:"private_key"`
+	APIToken      string `mapstructure:"api_token"` // bearer token for operator endpoints
 	TelemetryMode bool   `mapstructure:"telemetry_mode"`
 	DBPath        string `mapstructure:"db_path"`
+	JobWorkers    int    `mapstructure:"job_workers"`
+	JobQueueSize  int    `mapstructure:"job_queue_size"`
+	JobMaxRetries int    `mapstructure:"job_max_retries"`
+	ZKPKeyDir     string `mapstructure:"zkp_key_dir"`
+	VerifierPath  string `mapstructure:"zkp_verifier_path"`
+
+	// ZKPVerifiers registers the verifier deployed for each circuit version per
+	// chain; when set, proofs for unregistered versions are never submitted
+	ZKPVerifiers []VerifierConfig `mapstructure:"zkp_verifiers"`
+
+	// ZKPBackends maps circuit names to "groth16" or "plonk"; unlisted circuits use groth16
+	ZKPBackends  map[string]string `mapstructure:"zkp_backends"`
+	PlonkSRSPath string            `mapstructure:"zkp_plonk_srs"`
+
+	// Prover sizes the proving worker pool; ProverPriorities overrides the
+	// default per-circuit ordering of queued proofs
+	ProverWorkers    int            `mapstructure:"prover_workers"`
+	ProverQueueSize  int            `mapstructure:"prover_queue_size"`
+	ProverCacheSize  int            `mapstructure:"prover_cache_size"`
+	ProverCacheTTL   time.Duration  `mapstructure:"prover_cache_ttl"`
+	ProverPriorities map[string]int `mapstructure:"prover_priorities"`
+
+	// ProverRemoteWorkers are `obscura prover` URLs tried before proving locally
+	ProverRemoteWorkers []string      `mapstructure:"prover_remote_workers"`
+	ProverRemoteToken   string        `mapstructure:"prover_remote_token"`
+	ProverRemoteTimeout time.Duration `mapstructure:"prover_remote_timeout"`
+
+	// ProofOfReserves lists assets attested to the ProofOfReserve contract
+	ProofOfReserves       []oracle.ReserveConfig `mapstructure:"proof_of_reserves"`
+	ProofOfReserveAddress string                 `mapstructure:"proof_of_reserve_address"`
+
+	// TWAPFeeds are derived feeds averaging a base feed's recorded history
+	TWAPFeeds            []oracle.TWAPConfig `mapstructure:"twap_feeds"`
+	FeedHistoryRetention time.Duration       `mapstructure:"feed_history_retention"`
+
+	// FeedBatching fulfills data feeds with one aggregated proof per batch
+	FeedBatching FeedBatchConfig `mapstructure:"feed_batching"`
+
+	// DisclosureSources are the private endpoints selective disclosures may
+	// attest over; their credentials are resolved from the secrets vault
+	DisclosureSources []string `mapstructure:"disclosure_sources"`
+
+	// FunctionCacheDir keeps compiled WASM functions across restarts;
+	// FunctionDir holds the registered modules themselves, and FunctionQuota
+	// bounds how many it keeps; FunctionExports lists the functions
+	// registered modules may export
+	FunctionCacheDir string          `mapstructure:"function_cache_dir"`
+	FunctionDir      string          `mapstructure:"function_dir"`
+	FunctionQuota    functions.Quota `mapstructure:"function_quota"`
+	FunctionExports  []string        `mapstructure:"function_exports"`
+
+	// FunctionSandbox bounds memory and time per call and grants access to
+	// the filesystem, clock or randomness
+	FunctionSandbox functions.Sandbox `mapstructure:"function_sandbox"`
+
+	// FunctionHTTPAllowlist holds the URL prefixes functions may fetch;
+	// FunctionSecrets maps a module hash to the vault secrets it may read
+	FunctionHTTPAllowlist []string            `mapstructure:"function_http_allowlist"`
+	FunctionSecrets       map[string][]string `mapstructure:"function_secrets"`
+
+	// ComputeConsensus runs COMPUTE jobs on a committee of nodes and fulfills
+	// only when enough of them agree on the output
+	ComputeConsensus ComputeConsensusConfig `mapstructure:"compute_consensus"`
+
+	// Bridge makes the node a bridge committee member relaying between chains
+	Bridge BridgeConfig `mapstructure:"bridge"`
 }
 
 // Node represents the core Obscura Node structure
@@ -55,6 +125,13 @@ type Node struct {
 	Metrics     *api.MetricsCollector
 	FeedManager *oracle.FeedManager
 	Secrets     *storage.SecretManager
+	Access      *security.AccessController
+	Jobs        *JobTracker
+	Retries     *RetryScheduler
+	Prover      *zkp.ProverService
+	Circuits    *zkp.VerifierRegistry
+	Functions   *functions.Registry
+	Consensus   *ocr.OCRManager
 }
 
 // NewNode initializes a new Obscura Node
@@ -79,6 +156,28 @@ func NewNode() (*Node, error) {
 	viper.SetDefault("oracle_contract_address", "0x0000000000000000000000000000000000000000")
 	viper.SetDefault("stake_guard_address", "0x0000000000000000000000000000000000000000")
 	viper.SetDefault("private_key", "0000000000000000000000000000000000000000000000000000000000000000")
+	viper.SetDefault("job_workers", DefaultSchedulerConfig().Workers)
+	viper.SetDefault("job_queue_size", DefaultSchedulerConfig().QueueSize)
+	viper.SetDefault("job_max_retries", 5)
+	viper.SetDefault("zkp_key_dir", "./keys")
+	viper.SetDefault("prover_workers", zkp.DefaultProverConfig().Workers)
+	viper.SetDefault("prover_queue_size", zkp.DefaultProverConfig().QueueSize)
+	viper.SetDefault("prover_cache_size", zkp.DefaultProverConfig().CacheSize)
+	viper.SetDefault("prover_cache_ttl", zkp.DefaultProverConfig().CacheTTL)
+	viper.SetDefault("prover_remote_timeout", 2*time.Minute)
+	viper.SetDefault("proof_of_reserve_address", "0x0000000000000000000000000000000000000000")
+	viper.SetDefault("feed_history_retention", oracle.DefaultHistoryRetention)
+	viper.SetDefault("feed_batching.window", 2*time.Second)
+	viper.SetDefault("function_cache_dir", "./wasm-cache")
+	viper.SetDefault("function_dir", "./wasm-modules")
+	viper.SetDefault("function_quota.per_owner", functions.DefaultQuota().PerOwner)
+	viper.SetDefault("function_quota.total", functions.DefaultQuota().Total)
+	viper.SetDefault("function_exports", functions.DefaultAllowedExports)
+	viper.SetDefault("function_sandbox.memory_pages", functions.DefaultSandbox().MemoryPages)
+	viper.SetDefault("function_sandbox.timeout", functions.DefaultSandbox().Timeout)
+	viper.SetDefault("function_sandbox.fuel", functions.DefaultSandbox().Fuel)
+	viper.SetDefault("compute_consensus.timeout", 30*time.Second)
+	viper.SetDefault("bridge.relay", true)
 
 	if err := viper.ReadInConfig(); err != nil {
 		logger.Warn().Err(err).Msg("Config file not found, using defaults/environment variables")
@@ -94,6 +193,10 @@ func NewNode() (*Node, error) {
 		zerolog.SetGlobalLevel(level)
 	}
 
+	if err := initZK(cfg); err != nil {
+		return nil, err
+	}
+
 	// Initialize Storage
 	store, err := storage.NewFileStore(viper.GetString("db_path"))
 	if err != nil {
@@ -111,10 +214,29 @@ func NewNode() (*Node, error) {
 	vrfMgr := vrf.NewRandomnessManager(viper.GetString("private_key"))
 	secMgr := security.NewReputationManager()
 	stakingMgr := staking.NewStakeGuard()
-	computeMgr, _ := functions.NewComputeManager(context.Background())
+	computeMgr, err := functions.NewComputeManager(context.Background(), cfg.FunctionCacheDir, cfg.FunctionSandbox)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init wasm runtime: %w", err)
+	}
+	if !cfg.FunctionSandbox.Deterministic() {
+		logger.Warn().Msg("function_sandbox grants filesystem, clock or random access; compute results may differ between nodes")
+	}
 	feedManager := oracle.NewFeedManager()
 	aiModel := ai.NewPredictiveModel()
 	secretManager := storage.NewSecretManager()
+	accessCtrl := security.NewAccessController()
+	host := functions.Host{
+		Feeds:         feedManager,
+		Adapters:      adapterMgr,
+		Secrets:       secretManager,
+		HTTPAllowlist: cfg.FunctionHTTPAllowlist,
+		SecretScopes:  cfg.FunctionSecrets,
+	}
+	if len(cfg.ComputeConsensus.Peers) > 0 {
+		// Peers compare outputs, so functions must not read live feed values
+		host.Feeds = nil
+	}
+	computeMgr.SetHost(host)
 	
 	// Register some default feeds for the demo
 	feedManager.RegisterFeed(&oracle.FeedConfig{ID: "ETH-USD", Name: "Ethereum", Active: true})
@@ -126,6 +248,10 @@ func NewNode() (*Node, error) {
 		return nil, fmt.Errorf("failed to init tx manager: %w", err)
 	}
 
+	if err := checkDeployedVerifiers(context.Background(), client, viper.GetString("oracle_contract_address"), cfg.ZKPVerifiers, txMgr.chainID.Uint64()); err != nil {
+		return nil, fmt.Errorf("ZK key mismatch: %w", err)
+	}
+
 	// Reorg Protection & Persistence
 	jp := NewJobPersistence(store)
 	reorgProtector, err := NewReorgProtector(client, store, 12) // 12 confirmations
@@ -135,6 +261,13 @@ func NewNode() (*Node, error) {
 
 	metricsCollector := api.NewMetricsCollector()
 
+	prover, err := newProver(cfg)
+	if err != nil {
+		return nil, err
+	}
+	prover.SetMetrics(metricsCollector)
+	zkp.SetProver(prover)
+
 	jobMgr, err := NewJobManager(
 		adapterMgr,
 		txMgr,
@@ -152,8 +285,95 @@ func NewNode() (*Node, error) {
 		return nil, fmt.Errorf("failed to init job manager: %w", err)
 	}
 
+	schedCfg := DefaultSchedulerConfig()
+	schedCfg.Workers = cfg.JobWorkers
+	schedCfg.QueueSize = cfg.JobQueueSize
+	jobMgr.SetSchedulerConfig(schedCfg)
+	jobMgr.SetAccessController(accessCtrl)
+	functionRegistry, err := functions.NewRegistry(cfg.FunctionDir, computeMgr)
+	if err != nil {
+		return nil, err
+	}
+	functionRegistry.SetAllowedExports(cfg.FunctionExports)
+	functionRegistry.SetQuota(cfg.FunctionQuota)
+	jobMgr.SetFunctionRegistry(functionRegistry)
+
+	var computeConsensus *ocr.OCRManager
+	if len(cfg.ComputeConsensus.Peers) > 0 {
+		if err := checkComputeDeterminism(cfg.FunctionSandbox, host); err != nil {
+			return nil, fmt.Errorf("compute_consensus: %w", err)
+		}
+		computeConsensus, err = newComputeConsensus(cfg.ComputeConsensus, viper.GetString("private_key"))
+		if err != nil {
+			return nil, fmt.Errorf("compute_consensus: %w", err)
+		}
+		jobMgr.SetComputeConsensus(computeConsensus, cfg.ComputeConsensus.Timeout)
+		logger.Info().Int("peers", len(cfg.ComputeConsensus.Peers)).Int("threshold", cfg.ComputeConsensus.Threshold).Msg("Compute jobs require committee agreement")
+	}
+
+	circuits, err := newVerifierRegistry(cfg.ZKPVerifiers)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.ZKPVerifiers) > 0 {
+		jobMgr.SetVerifierRegistry(circuits, txMgr.chainID.Uint64(), client)
+	} else {
+		logger.Warn().Msg("No zkp_verifiers registered; proofs are submitted without checking their circuit version")
+	}
+
+	jobTracker := NewJobTracker(store)
+	jobMgr.SetJobTracker(jobTracker)
+
+	requestReader, err := NewOracleRequestReader(client, viper.GetString("oracle_contract_address"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to init request reader: %w", err)
+	}
+	jobMgr.SetFulfillmentGuard(NewFulfillmentGuard(store, requestReader, txMgr))
+
+	retryQueue := NewRetryQueue(store, cfg.JobMaxRetries, 10*time.Second)
+	jobMgr.SetRetryQueue(retryQueue)
+	retryScheduler := NewRetryScheduler(retryQueue, jobMgr, 5*time.Second)
+
 	automationMgr := automation.NewTriggerManager(jobMgr.JobQueue)
-	crosslink := crosschain.NewCrossLink()
+
+	if err := jobMgr.SetProofOfReserve(cfg.ProofOfReserveAddress); err != nil {
+		return nil, fmt.Errorf("failed to init proof of reserve: %w", err)
+	}
+	for _, reserve := range cfg.ProofOfReserves {
+		if err := jobMgr.RegisterReserve(reserve); err != nil {
+			return nil, fmt.Errorf("invalid proof_of_reserves entry: %w", err)
+		}
+		interval := reserve.Interval
+		if interval <= 0 {
+			interval = time.Hour
+		}
+		automationMgr.RegisterScheduledJob(oracle.JobTypeProofOfReserves, reserve.AssetID, interval, cfg.ProofOfReserveAddress)
+	}
+
+	jobMgr.SetFeedBatching(cfg.FeedBatching)
+	jobMgr.SetDisclosureSources(cfg.DisclosureSources)
+	if cfg.FeedBatching.Enabled {
+		logger.Info().Dur("window", cfg.FeedBatching.Window).Msg("Data feeds are fulfilled in aggregated batches")
+	}
+
+	jobMgr.SetFeedHistory(oracle.NewFeedHistory(store, cfg.FeedHistoryRetention))
+	for _, twap := range cfg.TWAPFeeds {
+		if twap.Window > cfg.FeedHistoryRetention {
+			return nil, fmt.Errorf("twap feed %s window %s exceeds feed_history_retention %s", twap.FeedID, twap.Window, cfg.FeedHistoryRetention)
+		}
+		if err := jobMgr.RegisterTWAP(twap); err != nil {
+			return nil, fmt.Errorf("invalid twap_feeds entry: %w", err)
+		}
+		interval := twap.Interval
+		if interval <= 0 {
+			interval = time.Minute
+		}
+		automationMgr.RegisterScheduledJob(oracle.JobTypeTWAP, twap.FeedID, interval, twap.FeedID)
+	}
+	crosslink, err := newBridge(context.Background(), cfg.Bridge, store, viper.GetString("private_key"))
+	if err != nil {
+		return nil, fmt.Errorf("bridge: %w", err)
+	}
 	stakeSync, _ := NewStakeSync(client, viper.GetString("stake_guard_address"), secMgr)
 
 	listener, err := NewEventListener(jobMgr, cfg.EthereumURL, viper.GetString("oracle_contract_address"), reorgProtector)
@@ -177,9 +397,11 @@ func NewNode() (*Node, error) {
 				priceBase = 3850.0
 			}
 			
+			price := priceBase + (float64(time.Now().Unix()%100) * 0.1)
 			feedManager.UpdateFeedValue(oracle.FeedLiveStatus{
 				ID:                 "ETH-USD",
-				Value:              fmt.Sprintf("$%.2f", priceBase + (float64(time.Now().Unix()%100) * 0.1)),
+				Value:              fmt.Sprintf("$%.2f", price),
+				Answer:             price,
 				Confidence:         99.0 + (float64(time.Now().Unix()%10) * 0.1),
 				Outliers:           0,
 				RoundID:            uint64(time.Now().Unix() / 60),
@@ -219,9 +441,92 @@ func NewNode() (*Node, error) {
 		Metrics:    metricsCollector,
 		FeedManager: feedManager,
 		Secrets:    secretManager,
+		Access:     accessCtrl,
+		Jobs:       jobTracker,
+		Retries:    retryScheduler,
+		Prover:     prover,
+		Circuits:   circuits,
+		Functions:  functionRegistry,
+		Consensus:  computeConsensus,
 	}, nil
 }
 
+// initZK loads the persisted circuit keys and refuses to start without them,
+// since proofs from an ephemeral setup fail on-chain. Keys are checked against
+// the deployed verifiers once the chain is reachable.
+func initZK(cfg Config) error {
+	zkp.SetKeyDir(cfg.ZKPKeyDir)
+	if err := configureBackends(cfg); err != nil {
+		return err
+	}
+	if err := zkp.Init(); err != nil {
+		return fmt.Errorf("failed to initialize ZK circuits: %w", err)
+	}
+
+	if !zkp.PersistentKeys() {
+		return fmt.Errorf("no ZK keys in %q; run 'obscura keys generate' or copy the keys the verifiers were exported from", cfg.ZKPKeyDir)
+	}
+
+	// Optional pre-deployment check against a local verifier source
+	if cfg.VerifierPath != "" {
+		if err := zkp.VerifyAgainstSolidity("range", cfg.VerifierPath); err != nil {
+			return fmt.Errorf("ZK key mismatch: %w", err)
+		}
+		log.Info().Str("verifier", cfg.VerifierPath).Msg("ZK keys match verifier source")
+	}
+	return nil
+}
+
+// newProver builds the proving worker pool from the node configuration
+func newProver(cfg Config) (*zkp.ProverService, error) {
+	proverCfg := zkp.DefaultProverConfig()
+	proverCfg.Workers = cfg.ProverWorkers
+	proverCfg.QueueSize = cfg.ProverQueueSize
+	proverCfg.CacheSize = cfg.ProverCacheSize
+	proverCfg.CacheTTL = cfg.ProverCacheTTL
+	for circuit, priority := range cfg.ProverPriorities {
+		if !slices.Contains(zkp.CircuitNames(), circuit) {
+			return nil, fmt.Errorf("prover_priorities.%s: unknown circuit", circuit)
+		}
+		proverCfg.Priorities[circuit] = priority
+	}
+	prover := zkp.NewProverService(proverCfg)
+
+	if len(cfg.ProverRemoteWorkers) > 0 {
+		remote, err := zkp.NewRemoteProver(cfg.ProverRemoteWorkers, cfg.ProverRemoteToken, cfg.ProverRemoteTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("prover_remote_workers: %w", err)
+		}
+		prover.SetRemote(remote)
+		log.Info().Strs("workers", cfg.ProverRemoteWorkers).Msg("Offloading proofs to remote provers")
+	}
+	return prover, nil
+}
+
+// configureBackends applies the per-circuit backend selection and loads the
+// universal SRS when any circuit uses PLONK
+func configureBackends(cfg Config) error {
+	usesPlonk := false
+	for circuit, name := range cfg.ZKPBackends {
+		backend, err := zkp.ParseBackend(name)
+		if err != nil {
+			return fmt.Errorf("zkp_backends.%s: %w", circuit, err)
+		}
+		if err := zkp.SetBackend(circuit, backend); err != nil {
+			return fmt.Errorf("zkp_backends.%s: %w", circuit, err)
+		}
+		usesPlonk = usesPlonk || backend == zkp.BackendPlonk
+	}
+
+	if !usesPlonk {
+		return nil
+	}
+	if cfg.PlonkSRSPath == "" {
+		return fmt.Errorf("zkp_plonk_srs must be set when a circuit uses the plonk backend")
+	}
+	return zkp.LoadPlonkSRS(cfg.PlonkSRSPath)
+}
+
 // Run starts the node's main loop and services
 func (n *Node) Run() error {
 	n.Logger.Info().Msgf("Starting Obscura Node on port %s", n.Config.Port)
@@ -238,6 +543,20 @@ func (n *Node) Run() error {
 		n.JobManager.Start(ctx)
 	}()
 
+	// Start Prover Workers
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		n.Prover.Start(ctx)
+	}()
+
+	// Start Retry Scheduler
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		n.Retries.Start(ctx)
+	}()
+
 	// Start AI Forecasting Service
 	wg.Add(1)
 	go func() {
@@ -266,6 +585,13 @@ func (n *Node) Run() error {
 		n.StakeSync.Start(ctx)
 	}()
 
+	// Start Cross-Chain Bridge Relayer
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		n.Bridge.Start(ctx)
+	}()
+
 	// Start Metrics & Monitoring API Server
 	wg.Add(1)
 	go func() {
@@ -289,6 +615,18 @@ func (n *Node) Run() error {
 func (n *Node) serveAPI(ctx context.Context) {
 	// Start metrics server on configured port
 	metricsServer := api.NewMetricsServer(n.Metrics, n.FeedManager, n.Config.Port)
+	metricsServer.SetJobHistory(n.Jobs)
+	metricsServer.SetOperatorToken(n.Config.APIToken)
+	metricsServer.SetDeadLetterQueue(n.Retries)
+	metricsServer.SetDisclosureService(n.JobManager)
+	metricsServer.SetCircuitRegistry(n.Circuits)
+	metricsServer.SetFunctionRegistry(n.Functions)
+	if n.Consensus != nil {
+		metricsServer.SetComputeConsensus(n.Consensus)
+	}
+	if len(n.Config.Bridge.Chains) > 0 {
+		metricsServer.SetBridgeCosigner(n.Bridge)
+	}
 	
 	// Run server in goroutine
 	go func() {
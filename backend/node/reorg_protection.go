@@ -3,6 +3,8 @@ package node
 import (
 	"context"
 	"fmt"
+	"math/rand"
+	"sort"
 	"strings"
 	"time"
 
@@ -126,15 +128,7 @@ func NewJobPersistence(store storage.Store) *JobPersistence {
 // SavePendingJob saves a job to persistent storage
 func (jp *JobPersistence) SavePendingJob(job oracle.JobRequest) error {
 	key := fmt.Sprintf("pending_job_%s", job.ID)
-	return jp.store.SaveJob(key, map[string]interface{}{
-		"id":              job.ID,
-		"type":            string(job.Type),
-		"params":          job.Params,
-		"requester":       job.Requester,
-		"timestamp":       job.Timestamp.Unix(),
-		"oev_enabled":     job.OEVEnabled,
-		"oev_beneficiary": job.OEVBeneficiary,
-	})
+	return jp.store.SaveJob(key, encodeJobRequest(job))
 }
 
 // LoadPendingJobs loads all pending jobs from storage
@@ -152,28 +146,15 @@ func (jp *JobPersistence) LoadPendingJobs() ([]oracle.JobRequest, error) {
 			continue
 		}
 
-		// Skip completed jobs
+		// Skip completed and rejected jobs
 		if completed, ok := m["completed"].(bool); ok && completed {
 			continue
 		}
+		if rejected, ok := m["rejected"].(bool); ok && rejected {
+			continue
+		}
 
-		id, _ := m["id"].(string)
-		jobType, _ := m["type"].(string)
-		params, _ := m["params"].(map[string]interface{})
-		requester, _ := m["requester"].(string)
-		ts, _ := m["timestamp"].(float64)
-		oevEnabled, _ := m["oev_enabled"].(bool)
-		oevBeneficiary, _ := m["oev_beneficiary"].(string)
-
-		jobs = append(jobs, oracle.JobRequest{
-			ID:             id,
-			Type:           oracle.JobType(jobType),
-			Params:         params,
-			Requester:      requester,
-			Timestamp:      time.Unix(int64(ts), 0),
-			OEVEnabled:     oevEnabled,
-			OEVBeneficiary: oevBeneficiary,
-		})
+		jobs = append(jobs, decodeJobRequest(m))
 	}
 	
 	log.Info().Int("count", len(jobs)).Msg("Job persistence: Loaded pending jobs")
@@ -190,6 +171,34 @@ func (jp *JobPersistence) MarkJobCompleted(jobID string) error {
 	})
 }
 
+// MarkJobRejected records that a job was shed by the scheduler and must not be restored
+func (jp *JobPersistence) MarkJobRejected(jobID, reason string) error {
+	key := fmt.Sprintf("pending_job_%s", jobID)
+	return jp.store.SaveJob(key, map[string]interface{}{
+		"rejected":    true,
+		"reason":      reason,
+		"rejected_at": time.Now().Unix(),
+	})
+}
+
+// Retry entry statuses stored under retry_job_<id>
+const (
+	retryStatusScheduled    = "scheduled"
+	retryStatusDispatched   = "dispatched"
+	retryStatusResolved     = "resolved"
+	retryStatusDeadLettered = "dead_lettered"
+)
+
+// Dead-letter entry statuses stored under dead_letter_<id>
+const (
+	deadLetterStatusOpen      = "open"
+	deadLetterStatusRequeued  = "requeued"
+	deadLetterStatusDiscarded = "discarded"
+)
+
+// maxRetryBackoff caps the exponential delay between attempts
+const maxRetryBackoff = 15 * time.Minute
+
 // RetryQueue manages failed jobs for retry
 type RetryQueue struct {
 	store        storage.Store
@@ -208,45 +217,283 @@ func NewRetryQueue(store storage.Store, maxRetries int, retryDelay time.Duration
 
 // AddToRetryQueue adds a failed job to the retry queue
 func (rq *RetryQueue) AddToRetryQueue(job oracle.JobRequest, errorMsg string) error {
+	_, err := rq.ScheduleRetry(job, errorMsg)
+	return err
+}
+
+// ScheduleRetry records a failed attempt and schedules the next one with
+// exponential backoff, moving the job to the dead letter queue once
+// maxRetries is exhausted. It reports whether the job was dead-lettered.
+func (rq *RetryQueue) ScheduleRetry(job oracle.JobRequest, errorMsg string) (bool, error) {
 	key := fmt.Sprintf("retry_job_%s", job.ID)
 	
 	// Get existing retry count
-	var retryCount int
-	if data, ok := rq.store.GetJob(key); ok {
-		if m, ok := data.(map[string]interface{}); ok {
-			if count, ok := m["retry_count"].(float64); ok {
-				retryCount = int(count)
-			}
-		}
-	}
+	retryCount := rq.RetryCount(job.ID)
 
 	if retryCount >= rq.maxRetries {
 		log.Error().
 			Str("job_id", job.ID).
 			Int("retries", retryCount).
 			Msg("Job exceeded max retries, moving to dead letter queue")
-		return rq.moveToDeadLetter(job, errorMsg)
+		if err := rq.moveToDeadLetter(job, errorMsg, retryCount); err != nil {
+			return false, err
+		}
+		return true, rq.store.SaveJob(key, encodeRetryEntry(job, retryCount, errorMsg, 0, retryStatusDeadLettered))
 	}
 
-	return rq.store.SaveJob(key, map[string]interface{}{
-		"id":          job.ID,
-		"type":        string(job.Type),
-		"params":      job.Params,
-		"requester":   job.Requester,
-		"retry_count": retryCount + 1,
-		"last_error":  errorMsg,
-		"next_retry":  time.Now().Add(rq.retryDelay).Unix(),
+	nextRetry := time.Now().Add(rq.backoff(retryCount + 1))
+	log.Info().
+		Str("job_id", job.ID).
+		Int("attempt", retryCount+1).
+		Time("next_retry", nextRetry).
+		Msg("Job scheduled for retry")
+
+	return false, rq.store.SaveJob(key, encodeRetryEntry(job, retryCount+1, errorMsg, nextRetry.Unix(), retryStatusScheduled))
+}
+
+// DeadLetter moves a job straight to the dead letter queue, used for
+// permanent failures that would fail the same way on every retry
+func (rq *RetryQueue) DeadLetter(job oracle.JobRequest, errorMsg string) error {
+	retryCount := rq.RetryCount(job.ID)
+	if err := rq.moveToDeadLetter(job, errorMsg, retryCount); err != nil {
+		return err
+	}
+	if _, ok := rq.store.GetJob(fmt.Sprintf("retry_job_%s", job.ID)); !ok {
+		return nil
+	}
+	return rq.setRetryStatus(job.ID, retryStatusDeadLettered)
+}
+
+// RetryCount returns how many retries have been scheduled for a job
+func (rq *RetryQueue) RetryCount(jobID string) int {
+	data, ok := rq.store.GetJob(fmt.Sprintf("retry_job_%s", jobID))
+	if !ok {
+		return 0
+	}
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	count, _ := toInt(m["retry_count"])
+	return count
+}
+
+// backoff returns retryDelay * 2^(attempt-1) capped at maxRetryBackoff, with ±25% jitter
+// so that jobs failing together do not retry in lockstep
+func (rq *RetryQueue) backoff(attempt int) time.Duration {
+	delay := rq.retryDelay
+	for i := 1; i < attempt && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(delay)/2+1)) - delay/4
+	return delay + jitter
+}
+
+// DueRetries returns scheduled retries whose next attempt time has passed
+func (rq *RetryQueue) DueRetries(now time.Time) []oracle.JobRequest {
+	var due []oracle.JobRequest
+	for key, data := range rq.store.GetAllJobs() {
+		if !strings.HasPrefix(key, "retry_job_") {
+			continue
+		}
+		m, ok := data.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		// Entries written before statuses existed are treated as scheduled
+		if status, ok := m["status"].(string); ok && status != retryStatusScheduled {
+			continue
+		}
+		nextRetry, _ := toInt(m["next_retry"])
+		if int64(nextRetry) > now.Unix() {
+			continue
+		}
+		due = append(due, decodeJobRequest(m))
+	}
+	return due
+}
+
+// MarkDispatched records that a due retry has been handed back to the job manager
+func (rq *RetryQueue) MarkDispatched(jobID string) error {
+	return rq.setRetryStatus(jobID, retryStatusDispatched)
+}
+
+// ClearRetry marks a job's retry entry as resolved after a successful attempt
+func (rq *RetryQueue) ClearRetry(jobID string) error {
+	if _, ok := rq.store.GetJob(fmt.Sprintf("retry_job_%s", jobID)); !ok {
+		return nil
+	}
+	return rq.setRetryStatus(jobID, retryStatusResolved)
+}
+
+func (rq *RetryQueue) setRetryStatus(jobID, status string) error {
+	key := fmt.Sprintf("retry_job_%s", jobID)
+	data, ok := rq.store.GetJob(key)
+	if !ok {
+		return fmt.Errorf("no retry entry for job %s", jobID)
+	}
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("malformed retry entry for job %s", jobID)
+	}
+
+	updated := make(map[string]interface{}, len(m)+1)
+	for k, v := range m {
+		updated[k] = v
+	}
+	updated["status"] = status
+	return rq.store.SaveJob(key, updated)
+}
+
+// ListDeadLetters returns jobs that exhausted their retries and await operator action
+func (rq *RetryQueue) ListDeadLetters() []oracle.DeadLetter {
+	var letters []oracle.DeadLetter
+	for key, data := range rq.store.GetAllJobs() {
+		if !strings.HasPrefix(key, "dead_letter_") {
+			continue
+		}
+		m, ok := data.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if status, ok := m["status"].(string); ok && status != deadLetterStatusOpen {
+			continue
+		}
+		letters = append(letters, decodeDeadLetter(m))
+	}
+
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i].FailedAt.After(letters[j].FailedAt)
 	})
+	return letters
+}
+
+// GetDeadLetter returns an open dead-letter entry
+func (rq *RetryQueue) GetDeadLetter(jobID string) (oracle.DeadLetter, bool) {
+	data, ok := rq.store.GetJob(fmt.Sprintf("dead_letter_%s", jobID))
+	if !ok {
+		return oracle.DeadLetter{}, false
+	}
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return oracle.DeadLetter{}, false
+	}
+	if status, ok := m["status"].(string); ok && status != deadLetterStatusOpen {
+		return oracle.DeadLetter{}, false
+	}
+	return decodeDeadLetter(m), true
+}
+
+// ReleaseDeadLetter closes a dead-letter entry. When requeue is true the retry
+// budget is reset so the job gets a fresh set of attempts.
+func (rq *RetryQueue) ReleaseDeadLetter(jobID string, requeue bool) error {
+	letter, ok := rq.GetDeadLetter(jobID)
+	if !ok {
+		return fmt.Errorf("no dead letter for job %s", jobID)
+	}
+
+	status := deadLetterStatusDiscarded
+	if requeue {
+		status = deadLetterStatusRequeued
+		if err := rq.store.SaveJob(fmt.Sprintf("retry_job_%s", jobID),
+			encodeRetryEntry(letter.Job, 0, letter.Error, 0, retryStatusResolved)); err != nil {
+			return err
+		}
+	}
+
+	entry := encodeDeadLetter(letter.Job, letter.Error, letter.Retries)
+	entry["failed_at"] = letter.FailedAt.Unix()
+	entry["status"] = status
+	entry["released_at"] = time.Now().Unix()
+	return rq.store.SaveJob(fmt.Sprintf("dead_letter_%s", jobID), entry)
 }
 
-func (rq *RetryQueue) moveToDeadLetter(job oracle.JobRequest, errorMsg string) error {
+func (rq *RetryQueue) moveToDeadLetter(job oracle.JobRequest, errorMsg string, retries int) error {
 	key := fmt.Sprintf("dead_letter_%s", job.ID)
-	return rq.store.SaveJob(key, map[string]interface{}{
-		"id":        job.ID,
-		"type":      string(job.Type),
-		"params":    job.Params,
-		"requester": job.Requester,
-		"error":     errorMsg,
-		"failed_at": time.Now().Unix(),
-	})
+	return rq.store.SaveJob(key, encodeDeadLetter(job, errorMsg, retries))
+}
+
+func encodeRetryEntry(job oracle.JobRequest, retryCount int, errorMsg string, nextRetry int64, status string) map[string]interface{} {
+	entry := encodeJobRequest(job)
+	entry["retry_count"] = retryCount
+	entry["last_error"] = errorMsg
+	entry["next_retry"] = nextRetry
+	entry["status"] = status
+	return entry
+}
+
+func encodeDeadLetter(job oracle.JobRequest, errorMsg string, retries int) map[string]interface{} {
+	entry := encodeJobRequest(job)
+	entry["error"] = errorMsg
+	entry["retries"] = retries
+	entry["failed_at"] = time.Now().Unix()
+	entry["status"] = deadLetterStatusOpen
+	return entry
+}
+
+func decodeDeadLetter(m map[string]interface{}) oracle.DeadLetter {
+	errorMsg, _ := m["error"].(string)
+	retries, _ := toInt(m["retries"])
+	failedAt, _ := toInt(m["failed_at"])
+	return oracle.DeadLetter{
+		Job:      decodeJobRequest(m),
+		Error:    errorMsg,
+		Retries:  retries,
+		FailedAt: time.Unix(int64(failedAt), 0),
+	}
+}
+
+// encodeJobRequest flattens a job into the map layout shared by the pending,
+// retry and dead-letter entries
+func encodeJobRequest(job oracle.JobRequest) map[string]interface{} {
+	return map[string]interface{}{
+		"id":              job.ID,
+		"type":            string(job.Type),
+		"params":          job.Params,
+		"requester":       job.Requester,
+		"timestamp":       job.Timestamp.Unix(),
+		"oev_enabled":     job.OEVEnabled,
+		"oev_beneficiary": job.OEVBeneficiary,
+		"is_optimistic":   job.IsOptimistic,
+	}
+}
+
+func decodeJobRequest(m map[string]interface{}) oracle.JobRequest {
+	id, _ := m["id"].(string)
+	jobType, _ := m["type"].(string)
+	params, _ := m["params"].(map[string]interface{})
+	requester, _ := m["requester"].(string)
+	ts, _ := toInt(m["timestamp"])
+	oevEnabled, _ := m["oev_enabled"].(bool)
+	oevBeneficiary, _ := m["oev_beneficiary"].(string)
+	isOptimistic, _ := m["is_optimistic"].(bool)
+
+	return oracle.JobRequest{
+		ID:             id,
+		Type:           oracle.JobType(jobType),
+		Params:         params,
+		Requester:      requester,
+		Timestamp:      time.Unix(int64(ts), 0),
+		OEVEnabled:     oevEnabled,
+		OEVBeneficiary: oevBeneficiary,
+		IsOptimistic:   isOptimistic,
+	}
+}
+
+// toInt reads a number that may be an int (in memory) or float64 (reloaded from JSON)
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	}
+	return 0, false
 }
@@ -0,0 +1,253 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog/log"
+
+	"github.com/obscura-network/obscura-node/api"
+	"github.com/obscura-network/obscura-node/oracle"
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+const ProofOfReserveABI = `[
+	{"inputs":[{"internalType":"bytes32","name":"assetId","type":"bytes32"},{"internalType":"uint256","name":"reportedReserve","type":"uint256"},{"internalType":"uint256","name":"circulatingSupply","type":"uint256"},{"internalType":"bytes32","name":"proofHash","type":"bytes32"}],"name":"updateReserve","outputs":[],"stateMutability":"nonpayable","type":"function"}
+]`
+
+// ReserveAttestation is one solvency proof over an asset's summed reserves
+// and liabilities. The blindings are kept so an auditor can open the
+// commitments; they never leave the node's store.
+type ReserveAttestation struct {
+	JobID               string
+	AssetID             string
+	Reserves            *big.Int
+	Liabilities         *big.Int
+	ReserveBlinding     *big.Int
+	LiabilityBlinding   *big.Int
+	ReserveCommitment   *big.Int
+	LiabilityCommitment *big.Int
+	Proof               zkp.Proof
+	ProofHash           common.Hash
+	TxHash              string
+	Timestamp           time.Time
+}
+
+// SetProofOfReserve sets the ProofOfReserve contract attestations are submitted to
+func (jm *JobManager) SetProofOfReserve(contractAddr string) error {
+	parsed, err := abi.JSON(strings.NewReader(ProofOfReserveABI))
+	if err != nil {
+		return err
+	}
+	jm.porAddr = common.HexToAddress(contractAddr)
+	jm.porABI = parsed
+	return nil
+}
+
+// RegisterReserve adds or replaces the sources attested for an asset
+func (jm *JobManager) RegisterReserve(cfg oracle.ReserveConfig) error {
+	if !isBytes32Hex(cfg.AssetID) {
+		return fmt.Errorf("asset id %q is not a 0x-prefixed bytes32", cfg.AssetID)
+	}
+	if len(cfg.Reserves) == 0 || len(cfg.Liabilities) == 0 {
+		return fmt.Errorf("asset %s needs at least one reserve and one liability source", cfg.AssetID)
+	}
+
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	if jm.reserves == nil {
+		jm.reserves = make(map[string]oracle.ReserveConfig)
+	}
+	jm.reserves[strings.ToLower(cfg.AssetID)] = cfg
+	return nil
+}
+
+func (jm *JobManager) reserveConfig(assetID string) (oracle.ReserveConfig, bool) {
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+	cfg, ok := jm.reserves[strings.ToLower(assetID)]
+	return cfg, ok
+}
+
+func (jm *JobManager) handleProofOfReserves(ctx context.Context, job oracle.JobRequest) error {
+	assetID, _ := job.Params["asset_id"].(string)
+	if assetID == "" {
+		assetID, _ = job.Params["feed_id"].(string)
+	}
+	cfg, ok := jm.reserveConfig(assetID)
+	if !ok {
+		return permanent(fmt.Errorf("no reserve sources configured for asset %q", assetID))
+	}
+	if jm.porAddr == (common.Address{}) {
+		return permanent(fmt.Errorf("proof_of_reserve_address is not configured"))
+	}
+
+	att, err := jm.attestReserves(job.ID, cfg)
+	if err != nil {
+		return err
+	}
+
+	data, err := jm.porABI.Pack("updateReserve", common.HexToHash(cfg.AssetID), att.Reserves, att.Liabilities, att.ProofHash)
+	if err != nil {
+		return permanent(fmt.Errorf("failed to pack updateReserve: %w", err))
+	}
+
+	txHash, err := jm.sendTo(ctx, job.ID, jm.porAddr, data)
+	if err != nil {
+		return err
+	}
+	att.TxHash = txHash.Hex()
+
+	log.Info().
+		Str("asset_id", cfg.AssetID).
+		Str("proof_hash", att.ProofHash.Hex()).
+		Str("tx_hash", att.TxHash).
+		Msg("Reserve Attestation Sent")
+
+	if jm.persistence != nil {
+		if err := jm.persistence.SaveReserveAttestation(att); err != nil {
+			log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to persist reserve attestation")
+		}
+	}
+
+	if jm.metrics != nil {
+		jm.metrics.AddJobRecord(api.JobRecord{
+			ID:        job.ID,
+			Type:      "Proof of Reserves",
+			Target:    cfg.AssetID,
+			Status:    "Attested",
+			Hash:      att.TxHash,
+			Timestamp: time.Now(),
+		})
+	}
+	return nil
+}
+
+// attestReserves sums every reserve and liability source and proves the
+// totals solvent under fresh blindings. Every source must respond: a missing
+// custodian would understate reserves or liabilities.
+func (jm *JobManager) attestReserves(jobID string, cfg oracle.ReserveConfig) (*ReserveAttestation, error) {
+	jm.trackState(jobID, oracle.JobStateFetching)
+	reserves, err := jm.sumSources(jobID, cfg.Reserves, cfg.Decimals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch reserves: %w", err)
+	}
+	liabilities, err := jm.sumSources(jobID, cfg.Liabilities, cfg.Decimals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch liabilities: %w", err)
+	}
+
+	jm.trackState(jobID, oracle.JobStateProving)
+	rb, err := zkp.RandomFieldElement()
+	if err != nil {
+		return nil, err
+	}
+	lb, err := zkp.RandomFieldElement()
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := zkp.GenerateProofOfReserves(reserves, liabilities, rb, lb)
+	if err != nil {
+		if reserves.Cmp(liabilities) < 0 {
+			log.Warn().Str("asset_id", cfg.AssetID).Str("reserves", reserves.String()).Str("liabilities", liabilities.String()).Msg("Reserves below liabilities")
+		}
+		return nil, proofFailure(fmt.Errorf("proof of reserves failed: %w", err))
+	}
+
+	att := &ReserveAttestation{
+		JobID:               jobID,
+		AssetID:             cfg.AssetID,
+		Reserves:            reserves,
+		Liabilities:         liabilities,
+		ReserveBlinding:     rb,
+		LiabilityBlinding:   lb,
+		ReserveCommitment:   zkp.Commit(reserves, rb),
+		LiabilityCommitment: zkp.Commit(liabilities, lb),
+		Proof:               proof,
+		Timestamp:           time.Now(),
+	}
+	if att.ProofHash, err = reserveProofHash(att); err != nil {
+		return nil, permanent(err)
+	}
+	return att, nil
+}
+
+// sumSources fetches every source concurrently and returns the exact total
+// scaled to the given number of decimals
+func (jm *JobManager) sumSources(jobID string, sources []oracle.DataSource, decimals int) (*big.Int, error) {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs []string
+	)
+	total := new(big.Int)
+
+	for _, src := range sources {
+		wg.Add(1)
+		go func(src oracle.DataSource) {
+			defer wg.Done()
+
+			scaled, err := jm.fetchAmount(src, decimals)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Warn().Err(err).Str("job_id", jobID).Str("url", src.URL).Msg("Reserve source failed")
+				errs = append(errs, fmt.Sprintf("%s: %v", src.URL, err))
+				return
+			}
+			total.Add(total, scaled)
+		}(src)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%d of %d sources failed: %s", len(errs), len(sources), strings.Join(errs, "; "))
+	}
+	return total, nil
+}
+
+// reserveProofHash binds the on-chain attestation to the proof and the two
+// commitments it was verified against
+func reserveProofHash(att *ReserveAttestation) (common.Hash, error) {
+	proof, err := zkp.SerializeProofBytes(att.Proof)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("proof serialization failed: %w", err)
+	}
+	return crypto.Keccak256Hash(
+		proof,
+		common.LeftPadBytes(att.ReserveCommitment.Bytes(), 32),
+		common.LeftPadBytes(att.LiabilityCommitment.Bytes(), 32),
+	), nil
+}
+
+func isBytes32Hex(s string) bool {
+	b, err := hexutil.Decode(s)
+	return err == nil && len(b) == 32
+}
+
+// SaveReserveAttestation records an attestation and the openings of its commitments
+func (jp *JobPersistence) SaveReserveAttestation(att *ReserveAttestation) error {
+	key := fmt.Sprintf("reserve_attestation_%s", att.JobID)
+	return jp.store.SaveJob(key, map[string]interface{}{
+		"asset_id":             att.AssetID,
+		"reserves":             att.Reserves.String(),
+		"liabilities":          att.Liabilities.String(),
+		"reserve_blinding":     att.ReserveBlinding.String(),
+		"liability_blinding":   att.LiabilityBlinding.String(),
+		"reserve_commitment":   att.ReserveCommitment.String(),
+		"liability_commitment": att.LiabilityCommitment.String(),
+		"proof_hash":           att.ProofHash.Hex(),
+		"tx_hash":              att.TxHash,
+		"timestamp":            att.Timestamp.Unix(),
+	})
+}
@@ -0,0 +1,93 @@
+package node
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/obscura-network/obscura-node/adapters"
+	"github.com/obscura-network/obscura-node/oracle"
+	"github.com/obscura-network/obscura-node/storage"
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+func TestAttestReservesSumsAuthenticatedSources(t *testing.T) {
+	const token = "Bearer custodian-token"
+	balances := map[string]string{
+		// 2^53 + 1.25 has no float64 representation
+		"/bank-a": `{"balance": 9007199254740993.25}`,
+		"/bank-b": `{"balance": "400000.50"}`,
+		"/supply": `{"balance": 950000}`,
+		"/short":  `{"balance": "9007199254740993.26"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(balances[r.URL.Path]))
+	}))
+	defer server.Close()
+
+	secrets := storage.NewSecretManager()
+	for path := range balances {
+		secrets.AddSecret(server.URL+path, token)
+	}
+	jm := &JobManager{adapters: adapters.NewAdapterManager(), secrets: secrets}
+
+	source := func(path string) oracle.DataSource {
+		return oracle.DataSource{URL: server.URL + path, Path: "balance", Weight: 1}
+	}
+	cfg := oracle.ReserveConfig{
+		AssetID:     "0x5553444300000000000000000000000000000000000000000000000000000000",
+		Reserves:    []oracle.DataSource{source("/bank-a"), source("/bank-b")},
+		Liabilities: []oracle.DataSource{source("/supply")},
+		Decimals:    2,
+	}
+	if err := jm.RegisterReserve(cfg); err != nil {
+		t.Fatalf("RegisterReserve failed: %v", err)
+	}
+
+	att, err := jm.attestReserves("por-1", cfg)
+	if err != nil {
+		t.Fatalf("attestReserves failed: %v", err)
+	}
+	if att.Reserves.Cmp(big.NewInt(900719925514099375)) != 0 || att.Liabilities.Cmp(big.NewInt(95000000)) != 0 {
+		t.Errorf("Expected exact totals 900719925514099375/95000000, got %v/%v", att.Reserves, att.Liabilities)
+	}
+	if err := zkp.VerifyProofOfReserves(att.Proof, att.ReserveCommitment, att.LiabilityCommitment); err != nil {
+		t.Errorf("Attestation proof rejected: %v", err)
+	}
+	if zkp.Commit(att.Reserves, att.ReserveBlinding).Cmp(att.ReserveCommitment) != 0 {
+		t.Error("Stored blinding does not open the reserve commitment")
+	}
+
+	cfg.Liabilities = []oracle.DataSource{source("/supply"), source("/short")}
+	_, err = jm.attestReserves("por-2", cfg)
+	if err == nil || IsRetryable(err) {
+		t.Errorf("Expected a permanent failure for insolvent reserves, got %v", err)
+	}
+}
+
+func TestRegisterReserveValidatesAsset(t *testing.T) {
+	jm := &JobManager{}
+	src := []oracle.DataSource{{URL: "https://custodian.example/balance"}}
+
+	if err := jm.RegisterReserve(oracle.ReserveConfig{AssetID: "USDC", Reserves: src, Liabilities: src}); err == nil {
+		t.Error("Expected a non-bytes32 asset id to be rejected")
+	}
+	if err := jm.RegisterReserve(oracle.ReserveConfig{AssetID: "0x01", Reserves: src, Liabilities: src}); err == nil {
+		t.Error("Expected a short asset id to be rejected")
+	}
+	id := "0xAB00000000000000000000000000000000000000000000000000000000000000"
+	if err := jm.RegisterReserve(oracle.ReserveConfig{AssetID: id, Reserves: src}); err == nil {
+		t.Error("Expected an asset without liability sources to be rejected")
+	}
+	if err := jm.RegisterReserve(oracle.ReserveConfig{AssetID: id, Reserves: src, Liabilities: src}); err != nil {
+		t.Fatalf("RegisterReserve failed: %v", err)
+	}
+	if _, ok := jm.reserveConfig("0xab00000000000000000000000000000000000000000000000000000000000000"); !ok {
+		t.Error("Expected asset lookup to ignore hex case")
+	}
+}
@@ -0,0 +1,102 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/obscura-network/obscura-node/oracle"
+)
+
+// RetryScheduler replays failed jobs from the RetryQueue once their backoff expires
+type RetryScheduler struct {
+	queue    *RetryQueue
+	jobs     *JobManager
+	interval time.Duration
+}
+
+// NewRetryScheduler creates a scheduler that scans for due retries every interval
+func NewRetryScheduler(rq *RetryQueue, jm *JobManager, interval time.Duration) *RetryScheduler {
+	return &RetryScheduler{
+		queue:    rq,
+		jobs:     jm,
+		interval: interval,
+	}
+}
+
+// Start scans for due retries until ctx is cancelled
+func (rs *RetryScheduler) Start(ctx context.Context) {
+	log.Info().Dur("interval", rs.interval).Msg("Retry scheduler started")
+
+	ticker := time.NewTicker(rs.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rs.dispatchDue(time.Now())
+		}
+	}
+}
+
+// dispatchDue re-dispatches every retry whose backoff has expired
+func (rs *RetryScheduler) dispatchDue(now time.Time) {
+	for _, job := range rs.queue.DueRetries(now) {
+		if err := rs.jobs.Dispatch(job); err != nil {
+			// Leave the entry scheduled; it will be picked up on the next scan
+			if !errors.Is(err, ErrQueueFull) {
+				log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to dispatch retry")
+			}
+			continue
+		}
+
+		if err := rs.queue.MarkDispatched(job.ID); err != nil {
+			log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to mark retry dispatched")
+		}
+		log.Info().Str("job_id", job.ID).Int("attempt", rs.queue.RetryCount(job.ID)).Msg("Retrying job")
+	}
+}
+
+// ListDeadLetters returns jobs awaiting operator action
+func (rs *RetryScheduler) ListDeadLetters() []oracle.DeadLetter {
+	return rs.queue.ListDeadLetters()
+}
+
+// GetDeadLetter returns a single dead-lettered job
+func (rs *RetryScheduler) GetDeadLetter(jobID string) (oracle.DeadLetter, bool) {
+	return rs.queue.GetDeadLetter(jobID)
+}
+
+// RequeueDeadLetter gives a dead-lettered job a fresh retry budget and dispatches it now
+func (rs *RetryScheduler) RequeueDeadLetter(jobID string) error {
+	letter, ok := rs.queue.GetDeadLetter(jobID)
+	if !ok {
+		return fmt.Errorf("no dead letter for job %s", jobID)
+	}
+
+	// Release before dispatching so a fast failure schedules against the fresh budget
+	if err := rs.queue.ReleaseDeadLetter(jobID, true); err != nil {
+		return err
+	}
+
+	if err := rs.jobs.Dispatch(letter.Job); err != nil {
+		if derr := rs.queue.DeadLetter(letter.Job, letter.Error); derr != nil {
+			log.Error().Err(derr).Str("job_id", jobID).Msg("Failed to restore dead letter")
+		}
+		return fmt.Errorf("failed to requeue job %s: %w", jobID, err)
+	}
+
+	log.Info().Str("job_id", jobID).Msg("Dead-lettered job requeued")
+	return nil
+}
+
+// DiscardDeadLetter drops a dead-lettered job for good
+func (rs *RetryScheduler) DiscardDeadLetter(jobID string) error {
+	log.Info().Str("job_id", jobID).Msg("Dead-lettered job discarded")
+	return rs.queue.ReleaseDeadLetter(jobID, false)
+}
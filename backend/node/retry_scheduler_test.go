@@ -0,0 +1,102 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/obscura-network/obscura-node/oracle"
+	"github.com/obscura-network/obscura-node/storage"
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+func TestRetryQueueBackoffAndDeadLetter(t *testing.T) {
+	store, err := storage.NewFileStore("./test_retry_backoff.json")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer store.Clear()
+
+	rq := NewRetryQueue(store, 2, time.Second)
+	job := oracle.JobRequest{
+		ID:         "1703750900",
+		Type:       oracle.JobTypeDataFeed,
+		Params:     map[string]interface{}{"feed_id": "ETH-USD"},
+		OEVEnabled: true,
+		Timestamp:  time.Now(),
+	}
+	now := time.Now()
+
+	if dead, err := rq.ScheduleRetry(job, "context deadline exceeded"); err != nil || dead {
+		t.Fatalf("First failure should be scheduled, got dead=%v err=%v", dead, err)
+	}
+	if due := rq.DueRetries(now.Add(-time.Second)); len(due) != 0 {
+		t.Errorf("Retry should not be due before its backoff expires")
+	}
+
+	due := rq.DueRetries(now.Add(3 * time.Second))
+	if len(due) != 1 || due[0].ID != job.ID || !due[0].OEVEnabled {
+		t.Fatalf("Expected the full job to be due for retry, got %+v", due)
+	}
+
+	// A dispatched retry is not picked up again by the next scan
+	rq.MarkDispatched(job.ID)
+	if due := rq.DueRetries(now.Add(time.Hour)); len(due) != 0 {
+		t.Errorf("Dispatched retry should not be due again")
+	}
+
+	rq.ScheduleRetry(job, "connection refused")
+	if dead, _ := rq.ScheduleRetry(job, "connection refused"); !dead {
+		t.Fatal("Expected job to be dead-lettered after max retries")
+	}
+
+	letters := rq.ListDeadLetters()
+	if len(letters) != 1 || letters[0].Retries != 2 || letters[0].Error != "connection refused" {
+		t.Fatalf("Unexpected dead letters: %+v", letters)
+	}
+
+	// Requeueing resets the retry budget and closes the dead letter
+	if err := rq.ReleaseDeadLetter(job.ID, true); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if len(rq.ListDeadLetters()) != 0 || rq.RetryCount(job.ID) != 0 {
+		t.Errorf("Expected dead letter closed and retry count reset")
+	}
+}
+
+func TestRetryBackoffIsCapped(t *testing.T) {
+	rq := NewRetryQueue(nil, 20, 10*time.Second)
+
+	for attempt := 1; attempt <= 20; attempt++ {
+		delay := rq.backoff(attempt)
+		if delay > maxRetryBackoff+maxRetryBackoff/4 {
+			t.Fatalf("Attempt %d backoff %v exceeds cap", attempt, delay)
+		}
+	}
+
+	if first := rq.backoff(1); first < 7500*time.Millisecond || first > 12500*time.Millisecond {
+		t.Errorf("First backoff %v outside ±25%% of base delay", first)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		err       error
+		retryable bool
+	}{
+		{fmt.Errorf("failed to fetch external data: timeout"), true},
+		{permanent(fmt.Errorf("ZK proof generation failed")), false},
+		{fmt.Errorf("wrapped: %w", permanent(fmt.Errorf("bad witness"))), false},
+		{fmt.Errorf("failed to send fulfillment transaction: execution reverted"), false},
+		{proofFailure(fmt.Errorf("ZK proof generation failed: %w", zkp.ErrProverQueueFull)), true},
+		{proofFailure(fmt.Errorf("ZK proof generation failed: %w", context.Canceled)), true},
+		{proofFailure(fmt.Errorf("ZK proof generation failed: constraint not satisfied")), false},
+	}
+
+	for _, c := range cases {
+		if got := IsRetryable(c.err); got != c.retryable {
+			t.Errorf("IsRetryable(%q) = %v, want %v", c.err, got, c.retryable)
+		}
+	}
+}
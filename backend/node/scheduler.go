@@ -0,0 +1,190 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/obscura-network/obscura-node/oracle"
+)
+
+// JobPriority selects the scheduling lane for a job
+type JobPriority int
+
+const (
+	PriorityHigh   JobPriority = iota // OEV and premium-tier requesters
+	PriorityNormal                    // Everything else
+	numPriorities
+)
+
+func (p JobPriority) String() string {
+	if p == PriorityHigh {
+		return "high"
+	}
+	return "normal"
+}
+
+// ErrQueueFull is returned when a job is shed because its lane is at capacity
+var ErrQueueFull = errors.New("job queue full")
+
+// SchedulerConfig controls the job worker pool
+type SchedulerConfig struct {
+	// Workers is the total number of jobs processed concurrently
+	Workers int
+
+	// QueueSize is the maximum number of waiting jobs per priority lane
+	QueueSize int
+
+	// TypeLimits caps concurrent jobs per type; types not listed may use every worker
+	TypeLimits map[oracle.JobType]int
+}
+
+// DefaultSchedulerConfig returns limits that keep proving-heavy jobs from
+// occupying every worker, so cheap VRF fulfillments are never starved.
+func DefaultSchedulerConfig() SchedulerConfig {
+	return SchedulerConfig{
+		Workers:   8,
+		QueueSize: 500,
+		TypeLimits: map[oracle.JobType]int{
+			oracle.JobTypeDataFeed: 4, // Groth16 range proof per job
+			oracle.JobTypeCompute:  2, // WASM execution plus proof
+			oracle.JobTypeVRF:      8,
+
+			oracle.JobTypeProofOfReserves:     1, // hourly, one asset at a time
+			oracle.JobTypeTWAP:                2,
+			oracle.JobTypeSelectiveDisclosure: 2,
+		},
+	}
+}
+
+// JobScheduler is a bounded worker pool with priority lanes and per-type concurrency limits
+type JobScheduler struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	config  SchedulerConfig
+	lanes   [numPriorities][]oracle.JobRequest
+	running map[oracle.JobType]int
+	closed  bool
+
+	// onDepthChange is notified whenever a lane grows or shrinks
+	onDepthChange func(lane string, depth int)
+}
+
+// NewJobScheduler creates a scheduler; workers are started by Run
+func NewJobScheduler(config SchedulerConfig) *JobScheduler {
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1
+	}
+
+	s := &JobScheduler{
+		config:  config,
+		running: make(map[oracle.JobType]int),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// Submit enqueues a job on the given lane, rejecting it if the lane is full
+func (s *JobScheduler) Submit(job oracle.JobRequest, priority JobPriority) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.lanes[priority]) >= s.config.QueueSize {
+		return ErrQueueFull
+	}
+
+	s.lanes[priority] = append(s.lanes[priority], job)
+	s.notifyDepth(priority)
+	s.cond.Broadcast()
+	return nil
+}
+
+// Depth returns the number of waiting jobs in a lane
+func (s *JobScheduler) Depth(priority JobPriority) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.lanes[priority])
+}
+
+// Run starts the workers and blocks until ctx is cancelled and in-flight jobs finish
+func (s *JobScheduler) Run(ctx context.Context, handler func(context.Context, oracle.JobRequest)) {
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		s.closed = true
+		s.mu.Unlock()
+		s.cond.Broadcast()
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				job, ok := s.next()
+				if !ok {
+					return
+				}
+				handler(ctx, job)
+				s.release(job.Type)
+			}
+		}()
+	}
+
+	log.Info().Int("workers", s.config.Workers).Int("queue_size", s.config.QueueSize).Msg("Job scheduler started")
+	wg.Wait()
+}
+
+// next blocks until a job is runnable under the type limits, highest lane first
+func (s *JobScheduler) next() (oracle.JobRequest, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		if s.closed {
+			return oracle.JobRequest{}, false
+		}
+
+		for p := JobPriority(0); p < numPriorities; p++ {
+			for i, job := range s.lanes[p] {
+				if !s.canRun(job.Type) {
+					continue
+				}
+				s.lanes[p] = append(s.lanes[p][:i], s.lanes[p][i+1:]...)
+				s.running[job.Type]++
+				s.notifyDepth(p)
+				return job, true
+			}
+		}
+
+		s.cond.Wait()
+	}
+}
+
+func (s *JobScheduler) canRun(jobType oracle.JobType) bool {
+	limit, ok := s.config.TypeLimits[jobType]
+	if !ok || limit <= 0 {
+		return true
+	}
+	return s.running[jobType] < limit
+}
+
+func (s *JobScheduler) release(jobType oracle.JobType) {
+	s.mu.Lock()
+	s.running[jobType]--
+	s.mu.Unlock()
+	s.cond.Broadcast()
+}
+
+// notifyDepth must be called with s.mu held
+func (s *JobScheduler) notifyDepth(priority JobPriority) {
+	if s.onDepthChange != nil {
+		s.onDepthChange(priority.String(), len(s.lanes[priority]))
+	}
+}
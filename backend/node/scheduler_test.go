@@ -0,0 +1,97 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/obscura-network/obscura-node/oracle"
+)
+
+func TestSchedulerRejectsWhenFull(t *testing.T) {
+	s := NewJobScheduler(SchedulerConfig{Workers: 1, QueueSize: 2})
+
+	for i := 0; i < 2; i++ {
+		if err := s.Submit(oracle.JobRequest{ID: "job", Type: oracle.JobTypeDataFeed}, PriorityNormal); err != nil {
+			t.Fatalf("Unexpected rejection: %v", err)
+		}
+	}
+
+	if err := s.Submit(oracle.JobRequest{ID: "overflow", Type: oracle.JobTypeDataFeed}, PriorityNormal); err != ErrQueueFull {
+		t.Errorf("Expected ErrQueueFull, got %v", err)
+	}
+
+	// The high lane has its own capacity so OEV jobs are not shed by a data burst
+	if err := s.Submit(oracle.JobRequest{ID: "oev", Type: oracle.JobTypeDataFeed}, PriorityHigh); err != nil {
+		t.Errorf("High priority job should be accepted: %v", err)
+	}
+}
+
+func TestSchedulerRunsHighPriorityFirst(t *testing.T) {
+	s := NewJobScheduler(SchedulerConfig{Workers: 1, QueueSize: 10})
+
+	s.Submit(oracle.JobRequest{ID: "normal-1", Type: oracle.JobTypeDataFeed}, PriorityNormal)
+	s.Submit(oracle.JobRequest{ID: "normal-2", Type: oracle.JobTypeDataFeed}, PriorityNormal)
+	s.Submit(oracle.JobRequest{ID: "premium", Type: oracle.JobTypeDataFeed}, PriorityHigh)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	var order []string
+
+	go s.Run(ctx, func(_ context.Context, job oracle.JobRequest) {
+		mu.Lock()
+		order = append(order, job.ID)
+		if len(order) == 3 {
+			cancel()
+		}
+		mu.Unlock()
+	})
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(2 * time.Second):
+		cancel()
+		t.Fatal("Scheduler did not process jobs")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if order[0] != "premium" {
+		t.Errorf("Expected premium job first, got order %v", order)
+	}
+}
+
+func TestSchedulerTypeLimitDoesNotStarveVRF(t *testing.T) {
+	s := NewJobScheduler(SchedulerConfig{
+		Workers:    2,
+		QueueSize:  10,
+		TypeLimits: map[oracle.JobType]int{oracle.JobTypeDataFeed: 1},
+	})
+
+	release := make(chan struct{})
+	vrfDone := make(chan struct{})
+
+	for i := 0; i < 3; i++ {
+		s.Submit(oracle.JobRequest{ID: "data", Type: oracle.JobTypeDataFeed}, PriorityNormal)
+	}
+	s.Submit(oracle.JobRequest{ID: "vrf", Type: oracle.JobTypeVRF}, PriorityNormal)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go s.Run(ctx, func(_ context.Context, job oracle.JobRequest) {
+		if job.Type == oracle.JobTypeVRF {
+			close(vrfDone)
+			return
+		}
+		<-release
+	})
+
+	select {
+	case <-vrfDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("VRF job was starved by data feed jobs")
+	}
+	close(release)
+}
@@ -0,0 +1,129 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/obscura-network/obscura-node/adapters"
+	"github.com/obscura-network/obscura-node/oracle"
+)
+
+// resolveDataSources returns the sources a data-feed job should query along with
+// the aggregation method and the minimum number of responses required.
+// Jobs referencing a registered feed use its configured sources; otherwise the
+// job's own url param is treated as a single source.
+func (jm *JobManager) resolveDataSources(job oracle.JobRequest) ([]oracle.DataSource, string, int) {
+	if feedID, ok := job.Params["feed_id"].(string); ok && jm.feedManager != nil {
+		if feed, exists := jm.feedManager.GetFeed(feedID); exists && len(feed.DataSources) > 0 {
+			return feed.DataSources, feed.AggregationMethod, int(feed.MinResponses)
+		}
+	}
+
+	url, _ := job.Params["url"].(string)
+	return []oracle.DataSource{{URL: url, Path: "price", Weight: 1}}, oracle.AggregationMedian, 1
+}
+
+// fetchAndAggregate queries every source concurrently and aggregates the responses.
+// Individual source failures are tolerated as long as minResponses are met.
+func (jm *JobManager) fetchAndAggregate(jobID string, sources []oracle.DataSource, method string, minResponses int) (oracle.AggregationResult, error) {
+	var (
+		mu           sync.Mutex
+		wg           sync.WaitGroup
+		observations []oracle.SourceObservation
+	)
+
+	for _, src := range sources {
+		wg.Add(1)
+		go func(src oracle.DataSource) {
+			defer wg.Done()
+
+			value, err := jm.fetchSource(src)
+			if err != nil {
+				log.Warn().Err(err).Str("job_id", jobID).Str("url", src.URL).Msg("Data source failed")
+				return
+			}
+
+			mu.Lock()
+			observations = append(observations, oracle.SourceObservation{
+				Source: src.URL,
+				Value:  value,
+				Weight: src.Weight,
+			})
+			mu.Unlock()
+		}(src)
+	}
+	wg.Wait()
+
+	if minResponses < 1 {
+		minResponses = 1
+	}
+	if len(observations) < minResponses {
+		return oracle.AggregationResult{}, fmt.Errorf("only %d of %d sources responded, need %d", len(observations), len(sources), minResponses)
+	}
+
+	return oracle.AggregateObservations(observations, method)
+}
+
+// fetchSource retrieves a single numeric value, injecting vault credentials when configured
+func (jm *JobManager) fetchSource(src oracle.DataSource) (float64, error) {
+	result, err := jm.fetch(src, false)
+	if err != nil {
+		return 0, err
+	}
+
+	value, ok := result.(float64)
+	if !ok {
+		return 0, fmt.Errorf("result is not a float number: %v", result)
+	}
+	return value, nil
+}
+
+// fetchAmount retrieves a balance without going through float64, scaled to
+// decimals. Sources may report it as a JSON number or a decimal string.
+func (jm *JobManager) fetchAmount(src oracle.DataSource, decimals int) (*big.Int, error) {
+	result, err := jm.fetch(src, true)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := result.(type) {
+	case json.Number:
+		return oracle.ParseAmount(v.String(), decimals)
+	case string:
+		return oracle.ParseAmount(v, decimals)
+	default:
+		return nil, fmt.Errorf("result is not a number: %v", result)
+	}
+}
+
+func (jm *JobManager) fetch(src oracle.DataSource, exact bool) (interface{}, error) {
+	// Feature #5: Inject Authentication for Private Sources
+	headers := make(map[string]string)
+	if jm.secrets != nil {
+		if cred, ok := jm.secrets.GetCredential(src.URL); ok {
+			log.Info().Str("url", src.URL).Msg("First-Party Authenticated Source Detected. Injecting Vault Credentials.")
+			// In real usage we'd parse the header vs key, simplified for demo
+			headers["Authorization"] = cred
+		}
+	}
+
+	path := src.Path
+	if path == "" {
+		path = "price"
+	}
+
+	return jm.adapters.Fetch(adapters.FetchDataRequest{
+		URL:      src.URL,
+		Method:   "GET",
+		Path:     path,
+		Obscured: false,
+		Headers:  headers,
+		Retries:  3,
+		Timeout:  src.Timeout,
+		Exact:    exact,
+	})
+}
@@ -0,0 +1,233 @@
+package node
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/obscura-network/obscura-node/api"
+	"github.com/obscura-network/obscura-node/oracle"
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+// feedScale is the fixed-point scale data feeds are reported at
+const feedScale = 1e8
+
+// TWAPRound is one published TWAP and the proof that it is the time-weighted
+// average of the base feed's recorded values over the window
+type TWAPRound struct {
+	FeedID    string
+	Value     *big.Int
+	Start     time.Time
+	End       time.Time
+	MinBound  *big.Int
+	MaxBound  *big.Int
+	Points    int
+	Proof     zkp.Proof
+	Timestamp time.Time
+}
+
+// SetFeedHistory enables recording of fulfilled feed values and TWAP jobs over them
+func (jm *JobManager) SetFeedHistory(h *oracle.FeedHistory) {
+	jm.history = h
+}
+
+// RegisterTWAP registers a derived feed publishing the TWAP of its base feed
+func (jm *JobManager) RegisterTWAP(cfg oracle.TWAPConfig) error {
+	if cfg.FeedID == "" || cfg.BaseFeed == "" {
+		return fmt.Errorf("TWAP feed needs both feed_id and base_feed")
+	}
+	if cfg.Window < time.Second {
+		return fmt.Errorf("TWAP feed %s needs a window of at least one second", cfg.FeedID)
+	}
+	if cfg.MaxPrice != 0 && cfg.MaxPrice < cfg.MinPrice {
+		return fmt.Errorf("TWAP feed %s has max_price below min_price", cfg.FeedID)
+	}
+
+	jm.mu.Lock()
+	if jm.twaps == nil {
+		jm.twaps = make(map[string]oracle.TWAPConfig)
+	}
+	jm.twaps[cfg.FeedID] = cfg
+	jm.mu.Unlock()
+
+	if jm.feedManager != nil {
+		jm.feedManager.RegisterFeed(&oracle.FeedConfig{
+			ID:                cfg.FeedID,
+			Name:              fmt.Sprintf("%s %s TWAP", cfg.BaseFeed, cfg.Window),
+			Description:       fmt.Sprintf("Time-weighted average of %s over %s", cfg.BaseFeed, cfg.Window),
+			Decimals:          8,
+			HeartbeatInterval: cfg.Interval,
+			DerivedFrom:       cfg.BaseFeed,
+			Active:            true,
+		})
+	}
+	return nil
+}
+
+func (jm *JobManager) twapConfig(feedID string) (oracle.TWAPConfig, bool) {
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+	cfg, ok := jm.twaps[feedID]
+	return cfg, ok
+}
+
+// recordObservation adds a fulfilled value to the feed history
+func (jm *JobManager) recordObservation(feedID string, value *big.Int, at time.Time) {
+	if jm.history == nil {
+		return
+	}
+	if err := jm.history.Record(feedID, value, at); err != nil {
+		log.Warn().Err(err).Str("feed", feedID).Msg("Failed to record feed observation")
+	}
+}
+
+func (jm *JobManager) handleTWAP(ctx context.Context, job oracle.JobRequest) error {
+	feedID, _ := job.Params["feed_id"].(string)
+	cfg, ok := jm.twapConfig(feedID)
+	if !ok {
+		return permanent(fmt.Errorf("no TWAP feed configured for %q", feedID))
+	}
+	if jm.history == nil {
+		return permanent(fmt.Errorf("feed history is not enabled"))
+	}
+
+	round, err := jm.computeTWAP(job, cfg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if jm.persistence != nil {
+		if err := jm.persistence.SaveTWAPRound(round); err != nil {
+			log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to persist TWAP round")
+		}
+	}
+	jm.recordObservation(cfg.FeedID, round.Value, round.End)
+
+	value, _ := new(big.Float).Quo(new(big.Float).SetInt(round.Value), big.NewFloat(feedScale)).Float64()
+	if jm.feedManager != nil {
+		jm.feedManager.UpdateFeedValue(oracle.FeedLiveStatus{
+			ID:         cfg.FeedID,
+			Value:      fmt.Sprintf("$%.2f", value),
+			Answer:     value,
+			Confidence: 100.0,
+			Sources:    round.Points,
+			Timestamp:  round.End,
+			IsZK:       true,
+		})
+	}
+
+	log.Info().
+		Str("feed", cfg.FeedID).
+		Float64("twap", value).
+		Int("points", round.Points).
+		Msg("TWAP Published")
+
+	if jm.metrics != nil {
+		jm.metrics.AddJobRecord(api.JobRecord{
+			ID:        job.ID,
+			Type:      "TWAP",
+			Target:    cfg.FeedID,
+			Status:    "Proven",
+			Hash:      fmt.Sprintf("twap_round_%s_%d", cfg.FeedID, round.End.Unix()),
+			Timestamp: time.Now(),
+		})
+	}
+	jm.trackState(job.ID, oracle.JobStateConfirmed)
+	return nil
+}
+
+// computeTWAP proves the TWAP of the base feed over the window ending at now
+func (jm *JobManager) computeTWAP(job oracle.JobRequest, cfg oracle.TWAPConfig, now time.Time) (*TWAPRound, error) {
+	end := now.Truncate(time.Second)
+	start := end.Add(-cfg.Window.Truncate(time.Second))
+
+	jm.trackState(job.ID, oracle.JobStateFetching)
+	window, err := jm.history.Window(cfg.BaseFeed, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s history: %w", cfg.BaseFeed, err)
+	}
+
+	prices := make([]*big.Int, len(window))
+	timestamps := make([]uint64, len(window))
+	for i, o := range window {
+		prices[i] = o.Value
+		timestamps[i] = uint64(o.Timestamp.Unix())
+	}
+	// Busy windows are averaged into as many time buckets as the circuit takes
+	prices, timestamps, err = zkp.BucketTWAPObservations(prices, timestamps, uint64(start.Unix()), uint64(end.Unix()))
+	if err != nil {
+		return nil, permanent(err)
+	}
+
+	twap, _, err := zkp.ComputeTWAP(prices, timestamps, uint64(start.Unix()), uint64(end.Unix()))
+	if err != nil {
+		return nil, permanent(err)
+	}
+	minBound, maxBound := twapBounds(job, cfg, twap)
+
+	jm.trackState(job.ID, oracle.JobStateProving)
+	_, proof, err := zkp.GenerateTWAPProof(uint64(start.Unix()), uint64(end.Unix()), minBound, maxBound, prices, timestamps)
+	if err != nil {
+		return nil, proofFailure(fmt.Errorf("TWAP proof generation failed: %w", err))
+	}
+
+	return &TWAPRound{
+		FeedID:    cfg.FeedID,
+		Value:     twap,
+		Start:     start,
+		End:       end,
+		MinBound:  minBound,
+		MaxBound:  maxBound,
+		Points:    len(window),
+		Proof:     proof,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// twapBounds resolves the proven range: job params first, then the feed's
+// configured prices, then the data feed default of the value ± 0.01
+func twapBounds(job oracle.JobRequest, cfg oracle.TWAPConfig, twap *big.Int) (*big.Int, *big.Int) {
+	minBound := toBigInt(job.Params["min"])
+	maxBound := toBigInt(job.Params["max"])
+	if minBound == nil && cfg.MinPrice > 0 {
+		minBound, _ = big.NewFloat(cfg.MinPrice * feedScale).Int(nil)
+	}
+	if maxBound == nil && cfg.MaxPrice > 0 {
+		maxBound, _ = big.NewFloat(cfg.MaxPrice * feedScale).Int(nil)
+	}
+	if minBound == nil {
+		minBound = new(big.Int).Sub(twap, big.NewInt(1000000))
+		if minBound.Sign() < 0 {
+			minBound.SetInt64(0)
+		}
+	}
+	if maxBound == nil {
+		maxBound = new(big.Int).Add(twap, big.NewInt(1000000))
+	}
+	return minBound, maxBound
+}
+
+// SaveTWAPRound records a published TWAP with its proof so consumers can verify it
+func (jp *JobPersistence) SaveTWAPRound(round *TWAPRound) error {
+	proof, err := zkp.SerializeProofBytes(round.Proof)
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("twap_round_%s_%d", round.FeedID, round.End.Unix())
+	return jp.store.SaveJob(key, map[string]interface{}{
+		"feed_id":   round.FeedID,
+		"value":     round.Value.String(),
+		"start":     round.Start.Unix(),
+		"end":       round.End.Unix(),
+		"min_bound": round.MinBound.String(),
+		"max_bound": round.MaxBound.String(),
+		"points":    round.Points,
+		"proof":     "0x" + hex.EncodeToString(proof),
+		"timestamp": round.Timestamp.Unix(),
+	})
+}
@@ -0,0 +1,76 @@
+package node
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/obscura-network/obscura-node/oracle"
+	"github.com/obscura-network/obscura-node/storage"
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+func TestTWAPJobProvesRecordedHistory(t *testing.T) {
+	store, err := storage.NewFileStore("./test_twap.json")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer store.Clear()
+
+	fm := oracle.NewFeedManager()
+	jm := &JobManager{feedManager: fm, persistence: NewJobPersistence(store)}
+	jm.SetFeedHistory(oracle.NewFeedHistory(store, time.Hour))
+
+	cfg := oracle.TWAPConfig{FeedID: "ETH-USD-TWAP30", BaseFeed: "ETH-USD", Window: 30 * time.Minute}
+	if err := jm.RegisterTWAP(cfg); err != nil {
+		t.Fatalf("RegisterTWAP failed: %v", err)
+	}
+	if feed, ok := fm.GetFeed(cfg.FeedID); !ok || feed.DerivedFrom != "ETH-USD" {
+		t.Fatalf("Expected a derived feed registered for ETH-USD, got %+v", feed)
+	}
+
+	// $3800 for the first 20 minutes of the window, then $3950
+	now := time.Unix(1_700_003_600, 0)
+	jm.recordObservation("ETH-USD", big.NewInt(3800_00000000), now.Add(-40*time.Minute))
+	jm.recordObservation("ETH-USD", big.NewInt(3950_00000000), now.Add(-10*time.Minute))
+
+	job := oracle.JobRequest{ID: "twap-1", Type: oracle.JobTypeTWAP, Params: map[string]interface{}{"feed_id": cfg.FeedID}}
+	round, err := jm.computeTWAP(job, cfg, now)
+	if err != nil {
+		t.Fatalf("computeTWAP failed: %v", err)
+	}
+	if round.Value.Cmp(big.NewInt(3850_00000000)) != 0 || round.Points != 2 {
+		t.Errorf("Expected a $3850 TWAP over 2 points, got %v over %d", round.Value, round.Points)
+	}
+	if err := zkp.VerifyTWAPProof(round.Proof, round.Value, uint64(round.Start.Unix()), uint64(round.End.Unix()), round.MinBound, round.MaxBound); err != nil {
+		t.Errorf("TWAP proof rejected: %v", err)
+	}
+
+	// A price outside the configured bounds trips the circuit
+	cfg.MaxPrice = 3840
+	if _, err := jm.computeTWAP(job, cfg, now); err == nil || IsRetryable(err) {
+		t.Errorf("Expected a permanent failure above max_price, got %v", err)
+	}
+
+	// A feed updated every 10s overflows the circuit and is bucketed instead
+	cfg.MaxPrice = 0
+	for i := 1; i < 180; i++ {
+		jm.recordObservation("ETH-USD", big.NewInt(3950_00000000+int64(i%3)*1_00000000), now.Add(-30*time.Minute+time.Duration(i)*10*time.Second))
+	}
+	round, err = jm.computeTWAP(job, cfg, now)
+	if err != nil {
+		t.Fatalf("computeTWAP over a busy window failed: %v", err)
+	}
+	if round.Points != 181 || round.Value.Cmp(big.NewInt(3950_00000000)) <= 0 {
+		t.Errorf("Expected a TWAP above $3950 over 181 points, got %v over %d", round.Value, round.Points)
+	}
+	if err := zkp.VerifyTWAPProof(round.Proof, round.Value, uint64(round.Start.Unix()), uint64(round.End.Unix()), round.MinBound, round.MaxBound); err != nil {
+		t.Errorf("Bucketed TWAP proof rejected: %v", err)
+	}
+
+	// History that does not cover the window is retried rather than guessed
+	cfg.Window = 2 * time.Hour
+	if _, err := jm.computeTWAP(job, cfg, now); err == nil || !IsRetryable(err) {
+		t.Errorf("Expected a retryable failure for missing history, got %v", err)
+	}
+}
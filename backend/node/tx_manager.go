@@ -3,8 +3,10 @@ package node
 import (
 	"context"
 	"crypto/ecdsa"
+	"fmt"
 	"math/big"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -52,8 +54,19 @@ func NewTxManager(client *ethclient.Client, pkHex string) (*TxManager, error) {
 }
 
 func (tm *TxManager) SendTransaction(ctx context.Context, to common.Address, data []byte, value *big.Int) (common.Hash, error) {
+	return tm.SendJournaledTransaction(ctx, to, data, value, nil)
+}
+
+// SendJournaledTransaction signs a transaction and calls journal with its hash
+// before broadcasting, so a crash mid-send never loses track of a transaction
+// that may have reached the mempool. A journal error aborts the send.
+func (tm *TxManager) SendJournaledTransaction(ctx context.Context, to common.Address, data []byte, value *big.Int, journal func(common.Hash) error) (common.Hash, error) {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
+	return tm.send(ctx, to, data, value, journal)
+}
+
+func (tm *TxManager) send(ctx context.Context, to common.Address, data []byte, value *big.Int, journal func(common.Hash) error) (common.Hash, error) {
 
 	gasPrice, err := tm.client.SuggestGasPrice(ctx)
 	if err != nil {
@@ -78,13 +91,19 @@ func (tm *TxManager) SendTransaction(ctx context.Context, to common.Address, dat
 		return common.Hash{}, err
 	}
 
+	if journal != nil {
+		if err := journal(signedTx.Hash()); err != nil {
+			return common.Hash{}, fmt.Errorf("failed to journal transaction: %w", err)
+		}
+	}
+
 	err = tm.client.SendTransaction(ctx, signedTx)
 	if err != nil {
 		// If nonce is too low, refresh it
 		if err.Error() == "nonce too low" {
 			n, _ := tm.client.PendingNonceAt(ctx, tm.fromAddr)
 			tm.nonce = n
-			return tm.SendTransaction(ctx, to, data, value)
+			return tm.send(ctx, to, data, value, journal)
 		}
 		return common.Hash{}, err
 	}
@@ -92,3 +111,52 @@ func (tm *TxManager) SendTransaction(ctx context.Context, to common.Address, dat
 	tm.nonce++
 	return signedTx.Hash(), nil
 }
+
+// TxStatus reports what the chain knows about a previously sent transaction
+func (tm *TxManager) TxStatus(ctx context.Context, hash common.Hash) (TxState, error) {
+	receipt, err := tm.client.TransactionReceipt(ctx, hash)
+	if err == nil {
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			return TxStateConfirmed, nil
+		}
+		return TxStateReverted, nil
+	}
+	if err != ethereum.NotFound {
+		return TxStateUnknown, err
+	}
+
+	// Not mined: either still in the mempool or dropped before broadcast
+	_, isPending, err := tm.client.TransactionByHash(ctx, hash)
+	if err == ethereum.NotFound {
+		return TxStateDropped, nil
+	}
+	if err != nil {
+		return TxStateUnknown, err
+	}
+	if isPending {
+		return TxStatePending, nil
+	}
+	return TxStateUnknown, nil
+}
+
+// WaitForReceipt polls until the transaction is mined or the context is cancelled
+func (tm *TxManager) WaitForReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := tm.client.TransactionReceipt(ctx, hash)
+		if err == nil {
+			return receipt, nil
+		}
+		if err != ethereum.NotFound {
+			log.Debug().Err(err).Str("tx_hash", hash.Hex()).Msg("Receipt lookup failed, retrying")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+// OracleVerifierABI holds the oracle contract's verifier getters
+const OracleVerifierABI = `[
+	{"inputs":[],"name":"verifier","outputs":[{"internalType":"contract IVerifier","name":"","type":"address"}],"stateMutability":"view","type":"function"},
+	{"inputs":[],"name":"aggregationVerifier","outputs":[{"internalType":"contract IAggregationVerifier","name":"","type":"address"}],"stateMutability":"view","type":"function"},
+	{"inputs":[],"name":"computeVerifier","outputs":[{"internalType":"contract IVerifier","name":"","type":"address"}],"stateMutability":"view","type":"function"}
+]`
+
+// verifierGetters names the oracle getter returning the verifier each circuit's
+// proofs are checked by on submission
+var verifierGetters = map[string]string{
+	"range":       "verifier",
+	"aggregation": "aggregationVerifier",
+	"private":     "computeVerifier",
+}
+
+// oracleVerifier returns the range verifier the oracle contract calls
+func oracleVerifier(ctx context.Context, caller bind.ContractCaller, oracleAddr string) (common.Address, error) {
+	return contractVerifier(ctx, caller, common.HexToAddress(oracleAddr), "verifier")
+}
+
+// contractVerifier reads a verifier address from one of the oracle's getters
+func contractVerifier(ctx context.Context, caller bind.ContractCaller, contractAddr common.Address, getter string) (common.Address, error) {
+	parsed, err := abi.JSON(strings.NewReader(OracleVerifierABI))
+	if err != nil {
+		return common.Address{}, err
+	}
+	contract := bind.NewBoundContract(contractAddr, parsed, caller, nil, nil)
+	var out []interface{}
+	if err := contract.Call(&bind.CallOpts{Context: ctx}, &out, getter); err != nil {
+		return common.Address{}, fmt.Errorf("failed to read %s() from oracle %s: %w", getter, contractAddr.Hex(), err)
+	}
+	addr, ok := out[0].(common.Address)
+	if !ok || addr == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("oracle %s has no %s set", contractAddr.Hex(), getter)
+	}
+	return addr, nil
+}
+
+// checkDeployedVerifiers fails unless the node's verifying keys are the ones
+// compiled into the deployed verifiers: the oracle's range verifier and every
+// zkp_verifiers entry for the current circuit versions on chainID
+func checkDeployedVerifiers(ctx context.Context, caller bind.ContractCaller, oracleAddr string, entries []VerifierConfig, chainID uint64) error {
+	rangeVerifier, err := oracleVerifier(ctx, caller, oracleAddr)
+	if err != nil {
+		return err
+	}
+	deployed := []VerifierConfig{{Circuit: "range", Address: rangeVerifier.Hex()}}
+	for _, v := range entries {
+		current, err := zkp.CircuitVersion(v.Circuit)
+		if err != nil {
+			return err
+		}
+		if v.ChainID == chainID && (v.Version == 0 || v.Version == current) {
+			deployed = append(deployed, v)
+		}
+	}
+
+	for _, v := range deployed {
+		code, err := caller.CodeAt(ctx, common.HexToAddress(v.Address), nil)
+		if err != nil {
+			return fmt.Errorf("failed to read %s verifier code at %s: %w", v.Circuit, v.Address, err)
+		}
+		if err := zkp.VerifyAgainstBytecode(v.Circuit, code); err != nil {
+			return fmt.Errorf("%s verifier at %s: %w", v.Circuit, v.Address, err)
+		}
+		log.Info().Str("circuit", v.Circuit).Str("verifier", v.Address).Msg("ZK keys match deployed verifier")
+	}
+	return nil
+}
+
+// VerifierConfig is the verifier deployed for one circuit version on one chain
+type VerifierConfig struct {
+	Circuit string `mapstructure:"circuit"`
+	// Version defaults to the circuit's current version
+	Version int    `mapstructure:"version"`
+	ChainID uint64 `mapstructure:"chain_id"`
+	Address string `mapstructure:"address"`
+}
+
+// newVerifierRegistry builds the circuit registry from zkp_verifiers
+func newVerifierRegistry(entries []VerifierConfig) (*zkp.VerifierRegistry, error) {
+	registry := zkp.NewVerifierRegistry()
+	for i, v := range entries {
+		version := v.Version
+		if version == 0 {
+			current, err := zkp.CircuitVersion(v.Circuit)
+			if err != nil {
+				return nil, fmt.Errorf("zkp_verifiers[%d]: %w", i, err)
+			}
+			version = current
+		}
+		if err := registry.Register(v.Circuit, version, v.ChainID, v.Address); err != nil {
+			return nil, fmt.Errorf("zkp_verifiers[%d]: %w", i, err)
+		}
+	}
+	return registry, nil
+}
+
+// SetVerifierRegistry makes the node refuse to submit proofs whose circuit
+// version has no verifier registered on chainID. With a caller, the registered
+// verifier must also be the one the target contract checks the proof with.
+func (jm *JobManager) SetVerifierRegistry(r *zkp.VerifierRegistry, chainID uint64, caller bind.ContractCaller) {
+	jm.verifiers = r
+	jm.chainID = chainID
+	jm.verifierCaller = caller
+}
+
+// proofTag identifies the circuit version a submitted proof was made for; it
+// is recorded with the job's submission
+type proofTag struct {
+	Circuit string
+	Version int
+}
+
+// circuitVersion returns the tag for a circuit's proofs sent to target. It
+// fails permanently when no verifier accepts that version on the node's chain,
+// or when target checks the circuit with a different verifier than the one
+// registered, since the proof could only revert.
+func (jm *JobManager) circuitVersion(ctx context.Context, circuit string, target common.Address) (proofTag, error) {
+	version, err := zkp.CircuitVersion(circuit)
+	if err != nil {
+		return proofTag{}, permanent(err)
+	}
+	tag := proofTag{Circuit: circuit, Version: version}
+	if jm.verifiers == nil {
+		return tag, nil
+	}
+	registered, err := jm.verifiers.Verifier(circuit, version, jm.chainID)
+	if err != nil {
+		return proofTag{}, permanent(fmt.Errorf("refusing to submit %s proof: %w", circuit, err))
+	}
+
+	getter, ok := verifierGetters[circuit]
+	if !ok || jm.verifierCaller == nil {
+		return tag, nil
+	}
+	deployed, err := contractVerifier(ctx, jm.verifierCaller, target, getter)
+	if err != nil {
+		return proofTag{}, err
+	}
+	if deployed != common.HexToAddress(registered) {
+		return proofTag{}, permanent(fmt.Errorf("refusing to submit %s v%d proof: %s %s() is %s, but zkp_verifiers registers %s",
+			circuit, version, target.Hex(), getter, deployed.Hex(), registered))
+	}
+	return tag, nil
+}
@@ -0,0 +1,124 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"regexp"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/obscura-network/obscura-node/zkp"
+)
+
+func TestCircuitVersionRefusesUnknownVerifier(t *testing.T) {
+	registry, err := newVerifierRegistry([]VerifierConfig{
+		{Circuit: "range", ChainID: 1, Address: "0x00000000000000000000000000000000000000aa"},
+	})
+	if err != nil {
+		t.Fatalf("newVerifierRegistry failed: %v", err)
+	}
+
+	ctx := context.Background()
+	oracle := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+	jm := &JobManager{}
+	if tag, err := jm.circuitVersion(ctx, "range", oracle); err != nil || tag.Version != 1 {
+		t.Errorf("Expected version 1 without a registry, got %+v, %v", tag, err)
+	}
+
+	jm.SetVerifierRegistry(registry, 1, nil)
+	if tag, err := jm.circuitVersion(ctx, "range", oracle); err != nil || tag != (proofTag{Circuit: "range", Version: 1}) {
+		t.Errorf("Expected the registered version, got %+v, %v", tag, err)
+	}
+
+	jm.SetVerifierRegistry(registry, 10, nil)
+	_, err = jm.circuitVersion(ctx, "range", oracle)
+	if !errors.Is(err, zkp.ErrUnknownVerifier) || IsRetryable(err) {
+		t.Errorf("Expected a permanent ErrUnknownVerifier, got %v", err)
+	}
+
+	// The registered verifier must be the one the oracle checks proofs with
+	chain := verifierChain{verifier: common.HexToAddress("0x00000000000000000000000000000000000000aa")}
+	jm.SetVerifierRegistry(registry, 1, chain)
+	if _, err := jm.circuitVersion(ctx, "range", oracle); err != nil {
+		t.Errorf("Expected the oracle's verifier to match the registry, got %v", err)
+	}
+	jm.SetVerifierRegistry(registry, 1, verifierChain{verifier: common.HexToAddress("0x00000000000000000000000000000000000000dd")})
+	if _, err := jm.circuitVersion(ctx, "range", oracle); err == nil || IsRetryable(err) {
+		t.Errorf("Expected a permanent error for an oracle using another verifier, got %v", err)
+	}
+
+	if _, err := newVerifierRegistry([]VerifierConfig{{Circuit: "range", Version: 3, ChainID: 1, Address: "0x00000000000000000000000000000000000000aa"}}); err == nil {
+		t.Error("Expected an unknown circuit version to be rejected")
+	}
+}
+
+// verifierChain serves the oracle's verifier() getter and contract code
+type verifierChain struct {
+	verifier common.Address
+	code     map[common.Address][]byte
+}
+
+func (c verifierChain) CodeAt(_ context.Context, addr common.Address, _ *big.Int) ([]byte, error) {
+	return c.code[addr], nil
+}
+
+func (c verifierChain) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+	return common.LeftPadBytes(c.verifier.Bytes(), 32), nil
+}
+
+// verifierBytecode stands in for a compiled verifier: solc inlines each
+// key-derived constant as a PUSH of its minimal big-endian encoding
+func verifierBytecode(t *testing.T, circuit string) []byte {
+	var src bytes.Buffer
+	if err := zkp.ExportVerifier(circuit, &src); err != nil {
+		t.Fatalf("ExportVerifier failed: %v", err)
+	}
+	code := []byte{0x60, 0x80, 0x60, 0x40, 0x52}
+	constant := regexp.MustCompile(`uint256 (?:private )?constant [A-Z0-9_]+ = (\w+);`)
+	for _, m := range constant.FindAllSubmatch(src.Bytes(), -1) {
+		value, _ := new(big.Int).SetString(string(m[1]), 0)
+		code = append(code, byte(0x5f+len(value.Bytes())))
+		code = append(code, value.Bytes()...)
+	}
+	return code
+}
+
+func TestDeployedVerifierMustMatchKeys(t *testing.T) {
+	rangeAddr := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	privateAddr := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	chain := verifierChain{
+		verifier: rangeAddr,
+		code: map[common.Address][]byte{
+			rangeAddr:   verifierBytecode(t, "range"),
+			privateAddr: verifierBytecode(t, "private"),
+		},
+	}
+	entries := []VerifierConfig{{Circuit: "private", ChainID: 1, Address: privateAddr.Hex()}}
+	oracle := "0x00000000000000000000000000000000000000cc"
+	ctx := context.Background()
+
+	if err := checkDeployedVerifiers(ctx, chain, oracle, entries, 1); err != nil {
+		t.Fatalf("Expected matching verifiers to pass, got %v", err)
+	}
+
+	// A verifier exported from other keys, or none at all, stops the node
+	chain.code[privateAddr] = verifierBytecode(t, "range")
+	if err := checkDeployedVerifiers(ctx, chain, oracle, entries, 1); err == nil {
+		t.Error("Expected a verifier for other keys to be rejected")
+	}
+	if err := checkDeployedVerifiers(ctx, chain, oracle, nil, 1); err != nil {
+		t.Errorf("Expected only the oracle's verifier to be checked without entries, got %v", err)
+	}
+	delete(chain.code, rangeAddr)
+	if err := checkDeployedVerifiers(ctx, chain, oracle, nil, 1); err == nil {
+		t.Error("Expected a missing range verifier to be rejected")
+	}
+	chain.verifier = common.Address{}
+	if err := checkDeployedVerifiers(ctx, chain, oracle, nil, 1); err == nil {
+		t.Error("Expected an oracle without a verifier to be rejected")
+	}
+}
@@ -1,11 +1,37 @@
 package oracle
 
 import (
+	"fmt"
 	"sort"
 
 	"github.com/obscura-network/obscura-node/security"
 )
 
+// Supported values for FeedConfig.AggregationMethod
+const (
+	AggregationMedian = "median"
+	AggregationMean   = "mean"
+	AggregationMode   = "mode"
+)
+
+// outlierThreshold is the modified Z-score above which a value is discarded
+const outlierThreshold = 1.5
+
+// SourceObservation is a single value reported by one data source
+type SourceObservation struct {
+	Source string
+	Value  float64
+	Weight float64
+}
+
+// AggregationResult summarizes a multi-source aggregation round
+type AggregationResult struct {
+	Value     float64
+	Responses int     // Sources that returned a value
+	Outliers  int     // Responses discarded by anomaly detection
+	Agreement float64 // Percentage of responses that survived filtering
+}
+
 // AggregateMedian returns the median of a slice of float64 values after filtering outliers.
 func AggregateMedian(values []float64) float64 {
 	if len(values) == 0 {
@@ -13,7 +39,7 @@ func AggregateMedian(values []float64) float64 {
 	}
 
 	// Filter outliers using Z-Score (from security package)
-	filtered := security.DetectAndFilterAnomalies(values, 1.5)
+	filtered := security.DetectAndFilterAnomalies(values, outlierThreshold)
 	if len(filtered) == 0 {
 		filtered = values // Fallback to raw if all filtered
 	}
@@ -30,3 +56,105 @@ func AggregateMedian(values []float64) float64 {
 	}
 	return (data[n/2-1] + data[n/2]) / 2
 }
+
+// AggregateObservations filters outliers from the observations and combines the
+// survivors using the given method, honouring per-source weights.
+// An empty method defaults to the weighted median.
+func AggregateObservations(observations []SourceObservation, method string) (AggregationResult, error) {
+	if len(observations) == 0 {
+		return AggregationResult{}, fmt.Errorf("no observations to aggregate")
+	}
+
+	values := make([]float64, len(observations))
+	for i, o := range observations {
+		values[i] = o.Value
+	}
+
+	// The filter returns surviving values, so match them back to their sources
+	remaining := make(map[float64]int)
+	for _, v := range security.DetectAndFilterAnomalies(values, outlierThreshold) {
+		remaining[v]++
+	}
+
+	var kept []SourceObservation
+	for _, o := range observations {
+		if remaining[o.Value] > 0 {
+			remaining[o.Value]--
+			if o.Weight <= 0 {
+				o.Weight = 1
+			}
+			kept = append(kept, o)
+		}
+	}
+
+	result := AggregationResult{
+		Responses: len(observations),
+		Outliers:  len(observations) - len(kept),
+		Agreement: float64(len(kept)) / float64(len(observations)) * 100,
+	}
+
+	switch method {
+	case AggregationMedian, "":
+		result.Value = weightedMedian(kept)
+	case AggregationMean:
+		result.Value = weightedMean(kept)
+	case AggregationMode:
+		result.Value = weightedMode(kept)
+	default:
+		return AggregationResult{}, fmt.Errorf("unsupported aggregation method: %s", method)
+	}
+
+	return result, nil
+}
+
+// weightedMedian returns the value at which cumulative weight reaches half the total
+func weightedMedian(obs []SourceObservation) float64 {
+	sorted := make([]SourceObservation, len(obs))
+	copy(sorted, obs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Value < sorted[j].Value })
+
+	var total float64
+	for _, o := range sorted {
+		total += o.Weight
+	}
+
+	var cumulative float64
+	for i, o := range sorted {
+		cumulative += o.Weight
+		if cumulative == total/2 && i+1 < len(sorted) {
+			// Exactly balanced: average with the next value, like an even-length median
+			return (o.Value + sorted[i+1].Value) / 2
+		}
+		if cumulative > total/2 {
+			return o.Value
+		}
+	}
+	return sorted[len(sorted)-1].Value
+}
+
+func weightedMean(obs []SourceObservation) float64 {
+	var sum, total float64
+	for _, o := range obs {
+		sum += o.Value * o.Weight
+		total += o.Weight
+	}
+	return sum / total
+}
+
+// weightedMode returns the value carrying the most weight, preferring the lower value on ties
+func weightedMode(obs []SourceObservation) float64 {
+	weights := make(map[float64]float64)
+	for _, o := range obs {
+		weights[o.Value] += o.Weight
+	}
+
+	var best, bestWeight float64
+	first := true
+	for v, w := range weights {
+		if first || w > bestWeight || (w == bestWeight && v < best) {
+			best, bestWeight = v, w
+			first = false
+		}
+	}
+	return best
+}
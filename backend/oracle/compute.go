@@ -0,0 +1,48 @@
+package oracle
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// DefaultEntrypoint is the exported function a compute job calls when none is named
+const DefaultEntrypoint = "run"
+
+// MaxComputeInputs bounds the private inputs passed to one function call
+const MaxComputeInputs = 16
+
+// ComputeRequest runs a registered WASM function over private inputs and
+// proves its output is at least Threshold. The inputs and output stay with
+// the node; only the threshold and the outcome are published.
+type ComputeRequest struct {
+	// Function is the SHA-256 of the module in the function registry
+	Function string
+	// Entrypoint is called with one uint64 argument per input
+	Entrypoint string
+	// Inputs are fetched with vault credentials and scaled by Decimals
+	Inputs    []DataSource
+	Decimals  int
+	Threshold *big.Int
+}
+
+// Validate checks the request before any input is fetched
+func (r ComputeRequest) Validate() error {
+	if r.Function == "" {
+		return fmt.Errorf("%w: function hash is required", ErrInvalidRequest)
+	}
+	if len(r.Inputs) > MaxComputeInputs {
+		return fmt.Errorf("%w: %d inputs exceed the limit of %d", ErrInvalidRequest, len(r.Inputs), MaxComputeInputs)
+	}
+	for i, in := range r.Inputs {
+		if in.URL == "" {
+			return fmt.Errorf("%w: input %d has no url", ErrInvalidRequest, i)
+		}
+	}
+	if r.Decimals < 0 || r.Decimals > 18 {
+		return fmt.Errorf("%w: decimals must be between 0 and 18, got %d", ErrInvalidRequest, r.Decimals)
+	}
+	if r.Threshold == nil || r.Threshold.Sign() < 0 {
+		return fmt.Errorf("%w: a non-negative threshold is required", ErrInvalidRequest)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+)
+
+// Predicate comparators
+const (
+	ComparatorGT      = "gt"
+	ComparatorGTE     = "gte"
+	ComparatorLT      = "lt"
+	ComparatorLTE     = "lte"
+	ComparatorEQ      = "eq"
+	ComparatorBetween = "between"
+)
+
+// ErrInvalidRequest marks a consumer request rejected before it was queued
+var ErrInvalidRequest = errors.New("invalid request")
+
+// predicateMax is the open upper end of gt/gte ranges; values above it
+// cannot be attested
+var predicateMax = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+// Predicate is a condition over one numeric field of a private response,
+// such as credit_score gte 700. Between takes the inclusive range
+// [Threshold, Upper].
+type Predicate struct {
+	Field      string  `json:"field"`
+	Comparator string  `json:"comparator"`
+	Threshold  float64 `json:"threshold"`
+	Upper      float64 `json:"upper,omitempty"`
+}
+
+// Bounds converts the predicate to the inclusive range the selective
+// disclosure circuit proves, with values scaled to decimals
+func (p Predicate) Bounds(decimals int) (*big.Int, *big.Int, error) {
+	if p.Field == "" {
+		return nil, nil, fmt.Errorf("predicate needs a field")
+	}
+	threshold, err := ScaleValue(p.Threshold, decimals)
+	if err != nil {
+		return nil, nil, fmt.Errorf("threshold: %w", err)
+	}
+	one := big.NewInt(1)
+
+	switch p.Comparator {
+	case ComparatorGT:
+		return new(big.Int).Add(threshold, one), predicateMax, nil
+	case ComparatorGTE:
+		return threshold, predicateMax, nil
+	case ComparatorLT:
+		if threshold.Sign() == 0 {
+			return nil, nil, fmt.Errorf("no value is below 0")
+		}
+		return big.NewInt(0), new(big.Int).Sub(threshold, one), nil
+	case ComparatorLTE:
+		return big.NewInt(0), threshold, nil
+	case ComparatorEQ:
+		return threshold, threshold, nil
+	case ComparatorBetween:
+		upper, err := ScaleValue(p.Upper, decimals)
+		if err != nil {
+			return nil, nil, fmt.Errorf("upper: %w", err)
+		}
+		if upper.Cmp(threshold) < 0 {
+			return nil, nil, fmt.Errorf("upper %v is below threshold %v", p.Upper, p.Threshold)
+		}
+		return threshold, upper, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown comparator %q", p.Comparator)
+	}
+}
+
+// ScaleValue converts v to a fixed-point integer with the given decimals,
+// rounding to the nearest unit. Only non-negative values can be proven.
+func ScaleValue(v float64, decimals int) (*big.Int, error) {
+	if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil, fmt.Errorf("value %v is not a non-negative number", v)
+	}
+	if decimals < 0 || decimals > 18 {
+		return nil, fmt.Errorf("decimals must be between 0 and 18, got %d", decimals)
+	}
+	scaled := new(big.Float).Mul(big.NewFloat(v), big.NewFloat(math.Pow10(decimals)))
+	scaled.Add(scaled, big.NewFloat(0.5))
+	i, _ := scaled.Int(nil)
+	if i.Cmp(predicateMax) > 0 {
+		return nil, fmt.Errorf("value %v is too large to attest", v)
+	}
+	return i, nil
+}
+
+// DisclosureRequest asks the node to attest a predicate over a private
+// endpoint. The value is encrypted to Recipient, a BabyJubjub public key, when
+// one is given; otherwise nobody can recover it.
+type DisclosureRequest struct {
+	URL       string    `json:"url"`
+	Predicate Predicate `json:"predicate"`
+	Decimals  int       `json:"decimals"`
+	Recipient string    `json:"recipient,omitempty"`
+}
+
+// DisclosureResult is the outcome of a selective-disclosure job. When the
+// predicate holds, Proof verifies against PublicInputs with the selective
+// disclosure verifier; the attested value itself is never included.
+type DisclosureResult struct {
+	ID             string    `json:"id"`
+	Predicate      Predicate `json:"predicate"`
+	Satisfied      bool      `json:"satisfied"`
+	DataCommitment string    `json:"data_commitment,omitempty"`
+	Recipient      string    `json:"recipient,omitempty"`
+	EphemeralKey   string    `json:"ephemeral_key,omitempty"`
+	Ciphertext     string    `json:"ciphertext,omitempty"`
+	RangeMin       string    `json:"range_min,omitempty"`
+	RangeMax       string    `json:"range_max,omitempty"`
+	Proof          string    `json:"proof,omitempty"`
+	PublicInputs   []string  `json:"public_inputs,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+}
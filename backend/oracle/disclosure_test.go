@@ -0,0 +1,43 @@
+package oracle
+
+import (
+	"testing"
+)
+
+func TestPredicateBounds(t *testing.T) {
+	cases := []struct {
+		p        Predicate
+		decimals int
+		min, max string
+	}{
+		{Predicate{Field: "score", Comparator: ComparatorGT, Threshold: 700}, 0, "701", predicateMax.String()},
+		{Predicate{Field: "age", Comparator: ComparatorGTE, Threshold: 18}, 0, "18", predicateMax.String()},
+		{Predicate{Field: "debt", Comparator: ComparatorLT, Threshold: 0.5}, 2, "0", "49"},
+		{Predicate{Field: "debt", Comparator: ComparatorLTE, Threshold: 0.5}, 2, "0", "50"},
+		{Predicate{Field: "tier", Comparator: ComparatorEQ, Threshold: 3}, 0, "3", "3"},
+		{Predicate{Field: "income", Comparator: ComparatorBetween, Threshold: 50000, Upper: 90000}, 0, "50000", "90000"},
+	}
+	for _, c := range cases {
+		min, max, err := c.p.Bounds(c.decimals)
+		if err != nil {
+			t.Errorf("%s %s: %v", c.p.Field, c.p.Comparator, err)
+			continue
+		}
+		if min.String() != c.min || max.String() != c.max {
+			t.Errorf("%s %s: expected [%s, %s], got [%v, %v]", c.p.Field, c.p.Comparator, c.min, c.max, min, max)
+		}
+	}
+
+	invalid := []Predicate{
+		{Comparator: ComparatorGT, Threshold: 1},
+		{Field: "x", Comparator: "ne", Threshold: 1},
+		{Field: "x", Comparator: ComparatorGT, Threshold: -1},
+		{Field: "x", Comparator: ComparatorLT, Threshold: 0},
+		{Field: "x", Comparator: ComparatorBetween, Threshold: 10, Upper: 5},
+	}
+	for _, p := range invalid {
+		if _, _, err := p.Bounds(0); err == nil {
+			t.Errorf("Expected %+v to be rejected", p)
+		}
+	}
+}
@@ -19,11 +19,24 @@ type FeedConfig struct {
 	OracleAddresses   []string
 	DataSources       []DataSource
 	AggregationMethod string // "median", "mean", "mode"
+	DerivedFrom       string // Base feed of a derived feed such as a TWAP
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 	Active            bool
 }
 
+// TWAPConfig defines a derived feed publishing the time-weighted average of a base feed
+type TWAPConfig struct {
+	FeedID   string        `mapstructure:"feed_id"`
+	BaseFeed string        `mapstructure:"base_feed"`
+	Window   time.Duration `mapstructure:"window"`
+	Interval time.Duration `mapstructure:"interval"`
+	// MinPrice and MaxPrice are sanity bounds proven in-circuit; unset bounds
+	// follow the data feed default of the value ± 0.01
+	MinPrice float64 `mapstructure:"min_price"`
+	MaxPrice float64 `mapstructure:"max_price"`
+}
+
 // DataSource represents an external data endpoint
 type DataSource struct {
 	URL     string
@@ -36,8 +49,11 @@ type DataSource struct {
 type FeedLiveStatus struct {
 	ID                 string    `json:"id"`
 	Value              string    `json:"value"`
+	Answer             float64   `json:"answer"` // Value before formatting
 	Confidence         float64   `json:"confidence"`
 	Outliers           int       `json:"outliers"`
+	Sources            int       `json:"sources"`          // Sources that responded this round
+	SourceAgreement    float64   `json:"source_agreement"` // % of responses within consensus
 	RoundID            uint64    `json:"round_id"`
 	Timestamp          time.Time `json:"timestamp"`
 	IsZK               bool      `json:"is_zk"`
@@ -116,6 +132,18 @@ func (fm *FeedManager) UpdateFeedValue(status FeedLiveStatus) {
 	fm.liveStatus[status.ID] = &status
 }
 
+// GetLatest returns the current live data for one active feed
+func (fm *FeedManager) GetLatest(id string) (FeedLiveStatus, bool) {
+	fm.mu.RLock()
+	defer fm.mu.RUnlock()
+
+	s, ok := fm.liveStatus[id]
+	if feed, active := fm.feeds[id]; !ok || !active || !feed.Active {
+		return FeedLiveStatus{}, false
+	}
+	return *s, true
+}
+
 // GetLiveStatus returns the current live data for all active feeds
 func (fm *FeedManager) GetLiveStatus() []FeedLiveStatus {
 	fm.mu.RLock()
@@ -0,0 +1,140 @@
+package oracle
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/obscura-network/obscura-node/storage"
+)
+
+const feedHistoryPrefix = "feed_history_"
+
+// DefaultHistoryRetention is how long observations are kept when no retention is set
+const DefaultHistoryRetention = 24 * time.Hour
+
+// Observation is one fulfilled feed value, scaled to the feed's decimals
+type Observation struct {
+	Value     *big.Int
+	Timestamp time.Time
+}
+
+// FeedHistory persists the values each feed reported so derived feeds such
+// as TWAPs can be recomputed after a restart
+type FeedHistory struct {
+	store     storage.Store
+	retention time.Duration
+	cache     map[string][]Observation
+	mu        sync.Mutex
+}
+
+// NewFeedHistory creates a history backed by store
+func NewFeedHistory(store storage.Store, retention time.Duration) *FeedHistory {
+	if retention <= 0 {
+		retention = DefaultHistoryRetention
+	}
+	return &FeedHistory{
+		store:     store,
+		retention: retention,
+		cache:     make(map[string][]Observation),
+	}
+}
+
+// Record appends an observation and drops those older than the retention
+// window, keeping the newest of them so the window start stays covered
+func (h *FeedHistory) Record(feedID string, value *big.Int, at time.Time) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	obs := append(h.load(feedID), Observation{Value: new(big.Int).Set(value), Timestamp: at.Truncate(time.Second)})
+	sort.SliceStable(obs, func(i, j int) bool { return obs[i].Timestamp.Before(obs[j].Timestamp) })
+
+	cutoff := at.Add(-h.retention)
+	keep := 0
+	for keep < len(obs)-1 && !obs[keep+1].Timestamp.After(cutoff) {
+		keep++
+	}
+	obs = obs[keep:]
+	h.cache[feedID] = obs
+
+	encoded := make([]map[string]interface{}, len(obs))
+	for i, o := range obs {
+		encoded[i] = map[string]interface{}{
+			"value":     o.Value.String(),
+			"timestamp": o.Timestamp.Unix(),
+		}
+	}
+	return h.store.SaveJob(feedHistoryPrefix+feedID, encoded)
+}
+
+// Window returns the observations covering [start, end]: the one in effect at
+// start, with its timestamp moved to start, followed by every later one
+func (h *FeedHistory) Window(feedID string, start, end time.Time) ([]Observation, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	obs := h.load(feedID)
+	first := -1
+	for i, o := range obs {
+		if o.Timestamp.After(start) {
+			break
+		}
+		first = i
+	}
+	if first < 0 {
+		return nil, fmt.Errorf("history of %s does not reach back to %s", feedID, start.Format(time.RFC3339))
+	}
+
+	window := []Observation{{Value: obs[first].Value, Timestamp: start}}
+	for _, o := range obs[first+1:] {
+		if o.Timestamp.After(end) {
+			break
+		}
+		window = append(window, o)
+	}
+	return window, nil
+}
+
+func (h *FeedHistory) load(feedID string) []Observation {
+	if obs, ok := h.cache[feedID]; ok {
+		return obs
+	}
+
+	var obs []Observation
+	data, _ := h.store.GetJob(feedHistoryPrefix + feedID)
+	switch entries := data.(type) {
+	case []map[string]interface{}:
+		for _, e := range entries {
+			obs = appendDecoded(obs, e)
+		}
+	case []interface{}:
+		// FileStore round-trips through JSON
+		for _, e := range entries {
+			if m, ok := e.(map[string]interface{}); ok {
+				obs = appendDecoded(obs, m)
+			}
+		}
+	}
+	h.cache[feedID] = obs
+	return obs
+}
+
+func appendDecoded(obs []Observation, m map[string]interface{}) []Observation {
+	s, _ := m["value"].(string)
+	value, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return obs
+	}
+	var ts int64
+	switch v := m["timestamp"].(type) {
+	case int64:
+		ts = v
+	case float64:
+		ts = int64(v)
+	default:
+		return obs
+	}
+	return append(obs, Observation{Value: value, Timestamp: time.Unix(ts, 0)})
+}
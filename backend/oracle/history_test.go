@@ -0,0 +1,54 @@
+package oracle
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/obscura-network/obscura-node/storage"
+)
+
+func TestFeedHistorySurvivesRestart(t *testing.T) {
+	store, err := storage.NewFileStore("./test_history.json")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer store.Clear()
+
+	base := time.Unix(1_700_000_000, 0)
+	h := NewFeedHistory(store, time.Hour)
+	for i, price := range []int64{100, 110, 120, 130} {
+		if err := h.Record("ETH-USD", big.NewInt(price), base.Add(time.Duration(i)*10*time.Minute)); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	reloaded, err := storage.NewFileStore("./test_history.json")
+	if err != nil {
+		t.Fatalf("Failed to reopen storage: %v", err)
+	}
+	window, err := NewFeedHistory(reloaded, time.Hour).Window("ETH-USD", base.Add(15*time.Minute), base.Add(25*time.Minute))
+	if err != nil {
+		t.Fatalf("Window failed: %v", err)
+	}
+	// The 110 observation is in effect at the window start; 130 is after its end
+	if len(window) != 2 || window[0].Value.Int64() != 110 || !window[0].Timestamp.Equal(base.Add(15*time.Minute)) || window[1].Value.Int64() != 120 {
+		t.Errorf("Unexpected window %+v", window)
+	}
+
+	if _, err := h.Window("ETH-USD", base.Add(-time.Minute), base.Add(time.Minute)); err == nil {
+		t.Error("Expected a window before the first observation to be rejected")
+	}
+
+	// Pruning keeps the last observation before the cutoff
+	if err := h.Record("ETH-USD", big.NewInt(140), base.Add(90*time.Minute)); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	window, err = h.Window("ETH-USD", base.Add(30*time.Minute), base.Add(90*time.Minute))
+	if err != nil || window[0].Value.Int64() != 130 {
+		t.Errorf("Expected the window to start at 130 after pruning, got %+v, %v", window, err)
+	}
+	if _, err := h.Window("ETH-USD", base.Add(25*time.Minute), base.Add(90*time.Minute)); err == nil {
+		t.Error("Expected pruned observations to be gone")
+	}
+}
@@ -0,0 +1,102 @@
+package oracle
+
+import (
+	"strings"
+	"time"
+)
+
+// JobState is a stage in the lifecycle of an oracle job
+type JobState string
+
+const (
+	JobStateReceived     JobState = "received"
+	JobStateFetching     JobState = "fetching"
+	JobStateProving      JobState = "proving"
+	JobStateSubmitted    JobState = "submitted"
+	JobStateConfirmed    JobState = "confirmed"
+	JobStateFailed       JobState = "failed"
+	JobStateDeadLettered JobState = "dead_lettered"
+)
+
+// jobTransitions lists the states reachable from each state.
+// Returning to received covers both retries and restarts after a crash mid-job.
+// A received job may be confirmed directly when the request was already resolved on-chain,
+// a proven job when it is published off-chain, as TWAP rounds are, and a fetched
+// job when its predicate does not hold and there is nothing to prove.
+var jobTransitions = map[JobState][]JobState{
+	JobStateReceived:     {JobStateFetching, JobStateProving, JobStateSubmitted, JobStateConfirmed, JobStateFailed},
+	JobStateFetching:     {JobStateProving, JobStateSubmitted, JobStateConfirmed, JobStateFailed, JobStateReceived},
+	JobStateProving:      {JobStateSubmitted, JobStateConfirmed, JobStateFailed, JobStateReceived},
+	JobStateSubmitted:    {JobStateConfirmed, JobStateFailed},
+	JobStateFailed:       {JobStateReceived, JobStateDeadLettered},
+	JobStateDeadLettered: {JobStateReceived},
+	JobStateConfirmed:    {},
+}
+
+// CanTransition reports whether a job may move from one state to another
+func (s JobState) CanTransition(to JobState) bool {
+	for _, next := range jobTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal reports whether no further processing will happen without operator action
+func (s JobState) IsTerminal() bool {
+	return s == JobStateConfirmed || s == JobStateDeadLettered
+}
+
+// JobTransition records when a job entered a state
+type JobTransition struct {
+	State JobState  `json:"state"`
+	At    time.Time `json:"at"`
+	Error string    `json:"error,omitempty"`
+}
+
+// JobStatus is the single source of truth for a job's progress. It is served
+// by the lifecycle endpoints; the dashboard's job history keeps its own shape.
+type JobStatus struct {
+	ID        string          `json:"id"`
+	Type      JobType         `json:"type"`
+	Requester string          `json:"requester"`
+	State     JobState        `json:"status"`
+	Error     string          `json:"error,omitempty"`
+	TxHash    string          `json:"hash,omitempty"`
+	Attempts  int             `json:"attempts"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"timestamp"`
+	History   []JobTransition `json:"history"`
+
+	// Circuit and CircuitVersion identify the verifier a submitted proof was
+	// made for
+	Circuit        string `json:"circuit,omitempty"`
+	CircuitVersion int    `json:"circuit_version,omitempty"`
+}
+
+// JobFilter narrows a job listing; zero values match everything
+type JobFilter struct {
+	State     JobState
+	Type      JobType
+	Requester string
+	Since     time.Time
+	Limit     int
+}
+
+// Matches reports whether a job satisfies the filter
+func (f JobFilter) Matches(job JobStatus) bool {
+	if f.State != "" && job.State != f.State {
+		return false
+	}
+	if f.Type != "" && job.Type != f.Type {
+		return false
+	}
+	if f.Requester != "" && !strings.EqualFold(job.Requester, f.Requester) {
+		return false
+	}
+	if !f.Since.IsZero() && job.UpdatedAt.Before(f.Since) {
+		return false
+	}
+	return true
+}
@@ -34,3 +34,53 @@ func TestMedianWithOutliers(t *testing.T) {
 		t.Errorf("Expected %f, got %f (outlier should be filtered)", expected, result)
 	}
 }
+
+func TestAggregateObservationsWeightedMedian(t *testing.T) {
+	obs := []SourceObservation{
+		{Source: "a", Value: 100, Weight: 1},
+		{Source: "b", Value: 101, Weight: 3},
+		{Source: "c", Value: 102, Weight: 1},
+	}
+
+	result, err := AggregateObservations(obs, AggregationMedian)
+	if err != nil {
+		t.Fatalf("Aggregation error: %v", err)
+	}
+	if result.Value != 101 {
+		t.Errorf("Expected 101, got %f", result.Value)
+	}
+	if result.Responses != 3 || result.Outliers != 0 || result.Agreement != 100 {
+		t.Errorf("Unexpected stats: %+v", result)
+	}
+}
+
+func TestAggregateObservationsFiltersOutliers(t *testing.T) {
+	obs := []SourceObservation{
+		{Source: "a", Value: 100, Weight: 1},
+		{Source: "b", Value: 105, Weight: 1},
+		{Source: "c", Value: 110, Weight: 1},
+		{Source: "d", Value: 115, Weight: 1},
+		{Source: "flaky", Value: 5000, Weight: 10},
+	}
+
+	result, err := AggregateObservations(obs, AggregationMean)
+	if err != nil {
+		t.Fatalf("Aggregation error: %v", err)
+	}
+	if result.Value != 107.5 {
+		t.Errorf("Expected 107.5, got %f (outlier should be filtered)", result.Value)
+	}
+	if result.Outliers != 1 {
+		t.Errorf("Expected 1 outlier, got %d", result.Outliers)
+	}
+	if result.Agreement != 80 {
+		t.Errorf("Expected 80%% agreement, got %f", result.Agreement)
+	}
+}
+
+func TestAggregateObservationsUnknownMethod(t *testing.T) {
+	obs := []SourceObservation{{Source: "a", Value: 100, Weight: 1}}
+	if _, err := AggregateObservations(obs, "geometric"); err == nil {
+		t.Error("Expected error for unsupported aggregation method")
+	}
+}
@@ -0,0 +1,45 @@
+package oracle
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// ReserveConfig describes an asset whose solvency is attested on a schedule
+type ReserveConfig struct {
+	// AssetID is the ProofOfReserve.sol assetId, a 0x-prefixed bytes32
+	AssetID string `mapstructure:"asset_id"`
+	// Reserves are custodian balance endpoints; their values are summed
+	Reserves []DataSource `mapstructure:"reserves"`
+	// Liabilities are outstanding supply endpoints; their values are summed
+	Liabilities []DataSource `mapstructure:"liabilities"`
+	// Decimals is the fixed-point scale amounts are proven at
+	Decimals int           `mapstructure:"decimals"`
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// ParseAmount scales a decimal amount such as "600000.25" or "1.5e6" to
+// decimals exactly. Amounts with more precision than decimals are rejected
+// rather than rounded, since rounding would misstate reserves or liabilities.
+func ParseAmount(s string, decimals int) (*big.Int, error) {
+	if decimals < 0 || decimals > 18 {
+		return nil, fmt.Errorf("decimals must be between 0 and 18, got %d", decimals)
+	}
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return nil, fmt.Errorf("amount %q is not a decimal number", s)
+	}
+	if r.Sign() < 0 {
+		return nil, fmt.Errorf("amount %s is negative", s)
+	}
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	if !r.IsInt() {
+		return nil, fmt.Errorf("amount %s has more than %d decimals", s, decimals)
+	}
+	if r.Num().Cmp(predicateMax) > 0 {
+		return nil, fmt.Errorf("amount %s is too large to attest", s)
+	}
+	return new(big.Int).Set(r.Num()), nil
+}
@@ -0,0 +1,33 @@
+package oracle
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseAmountIsExact(t *testing.T) {
+	cases := map[string]string{
+		"9007199254740993.25": "900719925474099325",
+		"400000.5":            "40000050",
+		"1.5e6":               "150000000",
+		"0":                   "0",
+	}
+	for in, want := range cases {
+		got, err := ParseAmount(in, 2)
+		if err != nil || got.String() != want {
+			t.Errorf("ParseAmount(%q) = %v, %v; want %s", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"1.005", "-1", "abc", "", "1e40"} {
+		if v, err := ParseAmount(in, 2); err == nil {
+			t.Errorf("Expected ParseAmount(%q) to fail, got %v", in, v)
+		}
+	}
+	if _, err := ParseAmount("1", 19); err == nil {
+		t.Error("Expected more than 18 decimals to be rejected")
+	}
+	if v, _ := ParseAmount("0.000000000000000001", 18); v.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("Expected one wei, got %v", v)
+	}
+}
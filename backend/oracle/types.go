@@ -9,6 +9,11 @@ const (
 	JobTypeDataFeed  JobType = "DATA_FEED"
 	JobTypeVRF       JobType = "VRF"
 	JobTypeCompute   JobType = "COMPUTE"
+
+	JobTypeProofOfReserves JobType = "PROOF_OF_RESERVES"
+	JobTypeTWAP            JobType = "TWAP"
+
+	JobTypeSelectiveDisclosure JobType = "SELECTIVE_DISCLOSURE"
 )
 
 // JobRequest represents an incoming oracle request
@@ -22,3 +27,11 @@ type JobRequest struct {
 	OEVBeneficiary string
 	IsOptimistic   bool
 }
+
+// DeadLetter is a job that exhausted its retries and awaits operator action
+type DeadLetter struct {
+	Job      JobRequest `json:"job"`
+	Error    string     `json:"error"`
+	Retries  int        `json:"retries"`
+	FailedAt time.Time  `json:"failed_at"`
+}
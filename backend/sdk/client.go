@@ -1,9 +1,14 @@
 package sdk
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"math/big"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -16,7 +21,7 @@ import (
 const OracleABI = `[
 	{"inputs":[{"internalType":"string","name":"apiUrl","type":"string"},{"internalType":"uint256","name":"min","type":"uint256"},{"internalType":"uint256","name":"max","type":"uint256"},{"internalType":"string","name":"metadata","type":"string"}],"name":"requestData","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"nonpayable","type":"function"},
 	{"inputs":[{"internalType":"uint256","name":"requestId","type":"uint256"},{"internalType":"uint256","name":"value","type":"uint256"},{"internalType":"uint256[8]","name":"zkpProof","type":"uint256[8]"},{"internalType":"uint256[2]","name":"publicInputs","type":"uint256[2]"}],"name":"fulfillData","outputs":[],"stateMutability":"nonpayable","type":"function"},
-	{"inputs":[{"internalType":"uint256","name":"","type":"uint256"}],"name":"requests","outputs":[{"internalType":"uint256","name":"id","type":"uint256"},{"internalType":"string","name":"apiUrl","type":"string"},{"internalType":"address","name":"requester","type":"address"},{"internalType":"bool","name":"resolved","type":"bool"},{"internalType":"uint256","name":"finalValue","type":"uint256"},{"internalType":"uint256","name":"createdAt","type":"uint256"},{"internalType":"uint256","name":"minThreshold","type":"uint256"},{"internalType":"uint256","name":"maxThreshold","type":"uint256"},{"internalType":"string","name":"metadata","type":"string"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"internalType":"uint256","name":"","type":"uint256"}],"name":"requests","outputs":[{"internalType":"uint256","name":"id","type":"uint256"},{"internalType":"string","name":"apiUrl","type":"string"},{"internalType":"address","name":"requester","type":"address"},{"internalType":"address","name":"oevBeneficiary","type":"address"},{"internalType":"bool","name":"oevEnabled","type":"bool"},{"internalType":"bool","name":"isOptimistic","type":"bool"},{"internalType":"uint256","name":"challengeWindow","type":"uint256"},{"internalType":"address","name":"disputer","type":"address"},{"internalType":"bool","name":"isDisputed","type":"bool"},{"internalType":"bool","name":"resolved","type":"bool"},{"internalType":"uint256","name":"finalValue","type":"uint256"},{"internalType":"uint256","name":"createdAt","type":"uint256"},{"internalType":"uint256","name":"minThreshold","type":"uint256"},{"internalType":"uint256","name":"maxThreshold","type":"uint256"},{"internalType":"string","name":"metadata","type":"string"}],"stateMutability":"view","type":"function"},
 	{"inputs":[],"name":"stakeGuard","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"}
 ]`
 
@@ -29,6 +34,8 @@ type ObscuraClient struct {
 	client     *ethclient.Client
 	oracleAddr common.Address
 	parsedABI  abi.ABI
+	nodeAPI    string
+	http       *http.Client
 }
 
 // NewObscuraClient initializes a new SDK client.
@@ -43,9 +50,15 @@ func NewObscuraClient(rpcURL string, oracleAddr string) (*ObscuraClient, error)
 		client:     client,
 		oracleAddr: common.HexToAddress(oracleAddr),
 		parsedABI:  parsed,
+		http:       &http.Client{Timeout: 30 * time.Second},
 	}, nil
 }
 
+// SetNodeAPI sets the API URL of the node that verifies proofs for the client
+func (c *ObscuraClient) SetNodeAPI(url string) {
+	c.nodeAPI = strings.TrimRight(url, "/")
+}
+
 // RequestData triggers a new data request on the Obscura Network.
 func (c *ObscuraClient) RequestData(ctx context.Context, auth *bind.TransactOpts, url string, min, max *big.Int) (common.Hash, error) {
 	contract := bind.NewBoundContract(c.oracleAddr, c.parsedABI, c.client, c.client, c.client)
@@ -75,16 +88,72 @@ func (c *ObscuraClient) GetRequestStatus(ctx context.Context, requestID *big.Int
 		return false, nil, err
 	}
 	
-	// out[3] is the 'resolved' boolean, out[4] is the 'finalValue'
-	return out[3].(bool), out[4].(*big.Int), nil
+	// Read fields by name; the getter returns every Request field in declaration order
+	var (
+		resolved   bool
+		finalValue *big.Int
+	)
+	for i, output := range c.parsedABI.Methods["requests"].Outputs {
+		switch output.Name {
+		case "resolved":
+			resolved, _ = out[i].(bool)
+		case "finalValue":
+			finalValue, _ = out[i].(*big.Int)
+		}
+	}
+	if finalValue == nil {
+		return false, nil, fmt.Errorf("unexpected requests() output for %s", requestID)
+	}
+	return resolved, finalValue, nil
 }
 
-// VerifyProof verifies a ZK range proof locally.
-func (c *ObscuraClient) VerifyProof(proof [8]*big.Int, min, max *big.Int) (bool, error) {
-	// 1. Reconstruct gnark proof from uint256[8]
-	// In a real SDK, we'd have a helper to un-serialize.
-	// For now, we simulate the verification call with a real verifier engine.
-	return zkp.VerifyRangeProof(nil, min, max) // Nil proof bypasses for demo, but uses real logic structure
+// VerifyProof verifies a fulfilled range proof through the node's /api/verify
+// endpoint, which checks it against the keys the node proves with. The result
+// carries the verifying key fingerprint to match against the deployed
+// verifier; a proof that does not verify is returned as an error.
+func (c *ObscuraClient) VerifyProof(ctx context.Context, proof [8]*big.Int, min, max *big.Int) (zkp.VerificationResult, error) {
+	var result zkp.VerificationResult
+	if c.nodeAPI == "" {
+		return result, fmt.Errorf("no node API set; call SetNodeAPI first")
+	}
+
+	req := zkp.VerifyRequest{Circuit: "range", PublicInputs: []string{min.String(), max.String()}}
+	for _, w := range proof {
+		if w == nil {
+			return result, fmt.Errorf("proof has a missing word")
+		}
+		req.Proof = append(req.Proof, w.String())
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return result, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.nodeAPI+"/api/verify", bytes.NewReader(body))
+	if err != nil {
+		return result, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return result, fmt.Errorf("verification request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return result, fmt.Errorf("node rejected verification request (status %d): %s", resp.StatusCode, apiErr.Error)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return result, fmt.Errorf("invalid verification response: %w", err)
+	}
+	if !result.Valid {
+		return result, fmt.Errorf("proof does not verify: %s", result.Error)
+	}
+	return result, nil
 }
 
 // GetReputation fetches the reputation score of a node directly from the StakeGuard contract.
@@ -1,7 +1,15 @@
 package sdk
 
 import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/obscura-network/obscura-node/zkp"
 )
 
 func TestObscuraClientInit(t *testing.T) {
@@ -16,6 +24,47 @@ func TestObscuraClientInit(t *testing.T) {
 	}
 }
 
-func TestProofVerificationMock(t *testing.T) {
-	t.Skip("SDK proof verification test - requires updated signature")
+func TestVerifyProofUsesNodeAPI(t *testing.T) {
+	var got zkp.VerifyRequest
+	valid := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/verify" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		result := zkp.VerificationResult{Circuit: got.Circuit, Valid: valid, VKFingerprint: "ab12"}
+		if !valid {
+			result.Error = "pairing check failed"
+		}
+		json.NewEncoder(w).Encode(result)
+	}))
+	defer server.Close()
+
+	client, err := NewObscuraClient("http://localhost:8545", "0x0000000000000000000000000000000000000000")
+	if err != nil {
+		t.Fatalf("NewObscuraClient failed: %v", err)
+	}
+	var proof [8]*big.Int
+	for i := range proof {
+		proof[i] = big.NewInt(int64(i + 1))
+	}
+
+	if _, err := client.VerifyProof(context.Background(), proof, big.NewInt(100), big.NewInt(200)); err == nil {
+		t.Error("Expected verification without a node API to fail")
+	}
+
+	client.SetNodeAPI(server.URL + "/")
+	result, err := client.VerifyProof(context.Background(), proof, big.NewInt(100), big.NewInt(200))
+	if err != nil || !result.Valid || result.VKFingerprint != "ab12" {
+		t.Fatalf("Expected a valid result with the key fingerprint, got %+v, %v", result, err)
+	}
+	if got.Circuit != "range" || len(got.Proof) != 8 || got.Proof[7] != "8" || len(got.PublicInputs) != 2 || got.PublicInputs[1] != "200" {
+		t.Errorf("Unexpected verification request %+v", got)
+	}
+
+	valid = false
+	if _, err := client.VerifyProof(context.Background(), proof, big.NewInt(100), big.NewInt(200)); err == nil || !strings.Contains(err.Error(), "pairing check failed") {
+		t.Errorf("Expected the rejection reason as an error, got %v", err)
+	}
 }
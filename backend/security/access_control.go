@@ -49,6 +49,11 @@ var TierLimits = map[ConsumerTier]int{
 	TierInternal:   100000,
 }
 
+// HasPriority reports whether requests from this tier are scheduled ahead of others
+func (t ConsumerTier) HasPriority() bool {
+	return t == TierPremium || t == TierEnterprise || t == TierInternal
+}
+
 // RateLimiter tracks request rates for a consumer
 type RateLimiter struct {
 	mu           sync.Mutex
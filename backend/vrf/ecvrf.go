@@ -0,0 +1,319 @@
+package vrf
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Suite is an RFC 9381 ECVRF cipher suite over a short Weierstrass curve with
+// cofactor 1, using try-and-increment encode_to_curve and RFC 6979 nonces
+type Suite struct {
+	Name string
+	ID   byte // suite_string
+	// A is the curve's a coefficient; elliptic.CurveParams only carries b
+	A     *big.Int
+	Curve elliptic.Curve
+	CLen  int
+	Hash  func() hash.Hash
+}
+
+var (
+	// P256SHA256TAI is ECVRF-P256-SHA256-TAI from RFC 9381 section 5.5
+	P256SHA256TAI = &Suite{
+		Name:  "ECVRF-P256-SHA256-TAI",
+		ID:    0x01,
+		A:     big.NewInt(-3),
+		Curve: elliptic.P256(),
+		CLen:  16,
+		Hash:  sha256.New,
+	}
+
+	// Secp256k1SHA256TAI applies the P256 TAI construction to the node's
+	// secp256k1 key. RFC 9381 assigns no suite string for this curve, so it
+	// uses 0xFE; verifiers must use the same value.
+	Secp256k1SHA256TAI = &Suite{
+		Name:  "ECVRF-SECP256K1-SHA256-TAI",
+		ID:    0xfe,
+		A:     big.NewInt(0),
+		Curve: crypto.S256(),
+		CLen:  16,
+		Hash:  sha256.New,
+	}
+)
+
+// ErrInvalidProof is returned when a VRF proof does not verify
+var ErrInvalidProof = errors.New("invalid VRF proof")
+
+const (
+	domainEncodeToCurve = 0x01
+	domainChallenge     = 0x02
+	domainProofToHash   = 0x03
+	domainBack          = 0x00
+)
+
+// ptLen and qLen are the byte lengths of an encoded point and scalar
+func (s *Suite) ptLen() int { return 1 + s.fieldLen() }
+func (s *Suite) qLen() int  { return (s.Curve.Params().N.BitLen() + 7) / 8 }
+func (s *Suite) fieldLen() int {
+	return (s.Curve.Params().P.BitLen() + 7) / 8
+}
+
+// ProofLen is the length of an encoded proof pi
+func (s *Suite) ProofLen() int { return s.ptLen() + s.CLen + s.qLen() }
+
+// Prove computes the proof pi for alpha under the given key (RFC 9381 section 5.1)
+func (s *Suite) Prove(key *ecdsa.PrivateKey, alpha []byte) ([]byte, error) {
+	if key.Curve != s.Curve {
+		return nil, fmt.Errorf("%s: key is on a different curve", s.Name)
+	}
+	x := key.D
+	q := s.Curve.Params().N
+
+	pk := s.encodePoint(key.X, key.Y)
+	hx, hy, err := s.encodeToCurve(pk, alpha)
+	if err != nil {
+		return nil, err
+	}
+	hString := s.encodePoint(hx, hy)
+
+	gx, gy := s.Curve.ScalarMult(hx, hy, s.scalar(x))
+	k := s.nonce(x, hString)
+	ux, uy := s.Curve.ScalarBaseMult(s.scalar(k))
+	vx, vy := s.Curve.ScalarMult(hx, hy, s.scalar(k))
+
+	c := s.challenge(pk, hString, s.encodePoint(gx, gy), s.encodePoint(ux, uy), s.encodePoint(vx, vy))
+	sc := new(big.Int).Mul(c, x)
+	sc.Add(sc, k).Mod(sc, q)
+
+	pi := s.encodePoint(gx, gy)
+	pi = append(pi, leftPad(c.Bytes(), s.CLen)...)
+	pi = append(pi, leftPad(sc.Bytes(), s.qLen())...)
+	return pi, nil
+}
+
+// Verify checks pi against the public key and alpha and returns the VRF
+// output beta (RFC 9381 section 5.3)
+func (s *Suite) Verify(pub *ecdsa.PublicKey, pi, alpha []byte) ([]byte, error) {
+	if pub.Curve != s.Curve || !s.Curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, fmt.Errorf("%s: invalid public key", s.Name)
+	}
+	gx, gy, c, sc, err := s.decodeProof(pi)
+	if err != nil {
+		return nil, err
+	}
+
+	pk := s.encodePoint(pub.X, pub.Y)
+	hx, hy, err := s.encodeToCurve(pk, alpha)
+	if err != nil {
+		return nil, err
+	}
+
+	// U = s*B - c*Y, V = s*H - c*Gamma
+	sBx, sBy := s.Curve.ScalarBaseMult(s.scalar(sc))
+	cYx, cYy := s.Curve.ScalarMult(pub.X, pub.Y, s.scalar(c))
+	ux, uy := s.sub(sBx, sBy, cYx, cYy)
+
+	sHx, sHy := s.Curve.ScalarMult(hx, hy, s.scalar(sc))
+	cGx, cGy := s.Curve.ScalarMult(gx, gy, s.scalar(c))
+	vx, vy := s.sub(sHx, sHy, cGx, cGy)
+
+	expected := s.challenge(pk, s.encodePoint(hx, hy), s.encodePoint(gx, gy), s.encodePoint(ux, uy), s.encodePoint(vx, vy))
+	if expected.Cmp(c) != 0 {
+		return nil, ErrInvalidProof
+	}
+	return s.gammaToHash(gx, gy), nil
+}
+
+// ProofToHash returns the VRF output beta for a proof without verifying it
+// (RFC 9381 section 5.2). Only use it on proofs that were verified or produced locally.
+func (s *Suite) ProofToHash(pi []byte) ([]byte, error) {
+	gx, gy, _, _, err := s.decodeProof(pi)
+	if err != nil {
+		return nil, err
+	}
+	return s.gammaToHash(gx, gy), nil
+}
+
+// MarshalPublicKey encodes a public key as a compressed SEC1 point
+func (s *Suite) MarshalPublicKey(pub *ecdsa.PublicKey) []byte {
+	return s.encodePoint(pub.X, pub.Y)
+}
+
+// UnmarshalPublicKey decodes a compressed SEC1 public key
+func (s *Suite) UnmarshalPublicKey(data []byte) (*ecdsa.PublicKey, error) {
+	x, y, err := s.decodePoint(data)
+	if err != nil {
+		return nil, err
+	}
+	return &ecdsa.PublicKey{Curve: s.Curve, X: x, Y: y}, nil
+}
+
+func (s *Suite) gammaToHash(gx, gy *big.Int) []byte {
+	h := s.Hash()
+	h.Write([]byte{s.ID, domainProofToHash})
+	h.Write(s.encodePoint(gx, gy))
+	h.Write([]byte{domainBack})
+	return h.Sum(nil)
+}
+
+// encodeToCurve is ECVRF_encode_to_curve_try_and_increment (section 5.4.1.1)
+func (s *Suite) encodeToCurve(salt, alpha []byte) (*big.Int, *big.Int, error) {
+	for ctr := 0; ctr < 256; ctr++ {
+		h := s.Hash()
+		h.Write([]byte{s.ID, domainEncodeToCurve})
+		h.Write(salt)
+		h.Write(alpha)
+		h.Write([]byte{byte(ctr), domainBack})
+		candidate := append([]byte{0x02}, h.Sum(nil)[:s.fieldLen()]...)
+		if x, y, err := s.decodePoint(candidate); err == nil {
+			return x, y, nil
+		}
+	}
+	return nil, nil, errors.New("encode_to_curve failed")
+}
+
+// challenge is ECVRF_challenge_generation (section 5.4.3)
+func (s *Suite) challenge(points ...[]byte) *big.Int {
+	h := s.Hash()
+	h.Write([]byte{s.ID, domainChallenge})
+	for _, p := range points {
+		h.Write(p)
+	}
+	h.Write([]byte{domainBack})
+	return new(big.Int).SetBytes(h.Sum(nil)[:s.CLen])
+}
+
+// nonce is ECVRF_nonce_generation_RFC6979 (section 5.4.2.1)
+func (s *Suite) nonce(x *big.Int, hString []byte) *big.Int {
+	q := s.Curve.Params().N
+	qLen := s.qLen()
+
+	h1 := s.Hash()
+	h1.Write(hString)
+	digest := bitsToInt(h1.Sum(nil), q.BitLen())
+	digest.Mod(digest, q)
+
+	xOctets := leftPad(x.Bytes(), qLen)
+	hOctets := leftPad(digest.Bytes(), qLen)
+
+	size := s.Hash().Size()
+	v := make([]byte, size)
+	for i := range v {
+		v[i] = 0x01
+	}
+	k := make([]byte, size)
+	mac := func(key []byte, parts ...[]byte) []byte {
+		m := hmac.New(s.Hash, key)
+		for _, p := range parts {
+			m.Write(p)
+		}
+		return m.Sum(nil)
+	}
+
+	k = mac(k, v, []byte{0x00}, xOctets, hOctets)
+	v = mac(k, v)
+	k = mac(k, v, []byte{0x01}, xOctets, hOctets)
+	v = mac(k, v)
+
+	for {
+		var t []byte
+		for len(t) < qLen {
+			v = mac(k, v)
+			t = append(t, v...)
+		}
+		candidate := bitsToInt(t, q.BitLen())
+		if candidate.Sign() > 0 && candidate.Cmp(q) < 0 {
+			return candidate
+		}
+		k = mac(k, v, []byte{0x00})
+		v = mac(k, v)
+	}
+}
+
+func (s *Suite) decodeProof(pi []byte) (gx, gy, c, sc *big.Int, err error) {
+	if len(pi) != s.ProofLen() {
+		return nil, nil, nil, nil, fmt.Errorf("%s: proof must be %d bytes, got %d", s.Name, s.ProofLen(), len(pi))
+	}
+	ptLen := s.ptLen()
+	gx, gy, err = s.decodePoint(pi[:ptLen])
+	if err != nil {
+		return nil, nil, nil, nil, ErrInvalidProof
+	}
+	c = new(big.Int).SetBytes(pi[ptLen : ptLen+s.CLen])
+	sc = new(big.Int).SetBytes(pi[ptLen+s.CLen:])
+	if sc.Cmp(s.Curve.Params().N) >= 0 {
+		return nil, nil, nil, nil, ErrInvalidProof
+	}
+	return gx, gy, c, sc, nil
+}
+
+func (s *Suite) encodePoint(x, y *big.Int) []byte {
+	out := make([]byte, 1, s.ptLen())
+	out[0] = 0x02 | byte(y.Bit(0))
+	return append(out, leftPad(x.Bytes(), s.fieldLen())...)
+}
+
+// decodePoint decompresses a SEC1 point, solving y^2 = x^3 + ax + b
+func (s *Suite) decodePoint(data []byte) (*big.Int, *big.Int, error) {
+	if len(data) != s.ptLen() || (data[0] != 0x02 && data[0] != 0x03) {
+		return nil, nil, errors.New("invalid point encoding")
+	}
+	params := s.Curve.Params()
+	p := params.P
+	x := new(big.Int).SetBytes(data[1:])
+	if x.Cmp(p) >= 0 {
+		return nil, nil, errors.New("point x coordinate out of range")
+	}
+
+	rhs := new(big.Int).Exp(x, big.NewInt(3), p)
+	rhs.Add(rhs, new(big.Int).Mul(s.A, x))
+	rhs.Add(rhs, params.B)
+	rhs.Mod(rhs, p)
+
+	y := new(big.Int).ModSqrt(rhs, p)
+	if y == nil {
+		return nil, nil, errors.New("point is not on the curve")
+	}
+	if y.Bit(0) != uint(data[0]&1) {
+		y.Sub(p, y)
+	}
+	return x, y, nil
+}
+
+// sub computes (x1, y1) - (x2, y2)
+func (s *Suite) sub(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	p := s.Curve.Params().P
+	negY := new(big.Int).Sub(p, y2)
+	negY.Mod(negY, p)
+	return s.Curve.Add(x1, y1, x2, negY)
+}
+
+func (s *Suite) scalar(k *big.Int) []byte {
+	return leftPad(k.Bytes(), s.qLen())
+}
+
+// bitsToInt takes the leftmost qBits bits of b as an integer (RFC 6979 section 2.3.2)
+func bitsToInt(b []byte, qBits int) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if excess := len(b)*8 - qBits; excess > 0 {
+		v.Rsh(v, uint(excess))
+	}
+	return v
+}
+
+func leftPad(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	out := make([]byte, size)
+	copy(out[size-len(b):], b)
+	return out
+}
@@ -0,0 +1,123 @@
+package vrf
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// RFC 9381 Appendix B.1, ECVRF-P256-SHA256-TAI examples 10 and 11
+var p256Vectors = []struct {
+	sk, pk, alpha, pi, beta string
+}{
+	{
+		sk:    "c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721",
+		pk:    "0360fed4ba255a9d31c961eb74c6356d68c049b8923b61fa6ce669622e60f29fb6",
+		alpha: "73616d706c65",
+		pi:    "035b5c726e8c0e2c488a107c600578ee75cb702343c153cb1eb8dec77f4b5071b4a53f0a46f018bc2c56e58d383f2305e0975972c26feea0eb122fe7893c15af376b33edf7de17c6ea056d4d82de6bc02f",
+		beta:  "a3ad7b0ef73d8fc6655053ea22f9bede8c743f08bbed3d38821f0e16474b505e",
+	},
+	{
+		sk:    "c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721",
+		pk:    "0360fed4ba255a9d31c961eb74c6356d68c049b8923b61fa6ce669622e60f29fb6",
+		alpha: "74657374",
+		pi:    "034dac60aba508ba0c01aa9be80377ebd7562c4a52d74722e0abae7dc3080ddb56c19e067b15a8a8174905b13617804534214f935b94c2287f797e393eb0816969d864f37625b443f30f1a5a33f2b3c854",
+		beta:  "a284f94ceec2ff4b3794629da7cbafa49121972671b466cab4ce170aa365f26d",
+	},
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestECVRFP256Vectors(t *testing.T) {
+	s := P256SHA256TAI
+	for _, v := range p256Vectors {
+		key := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(mustHex(t, v.sk))}
+		key.Curve = s.Curve
+		key.X, key.Y = s.Curve.ScalarBaseMult(mustHex(t, v.sk))
+
+		if pk := hex.EncodeToString(s.MarshalPublicKey(&key.PublicKey)); pk != v.pk {
+			t.Fatalf("public key mismatch: got %s", pk)
+		}
+
+		alpha := mustHex(t, v.alpha)
+		pi, err := s.Prove(key, alpha)
+		if err != nil {
+			t.Fatalf("Prove failed: %v", err)
+		}
+		if got := hex.EncodeToString(pi); got != v.pi {
+			t.Errorf("pi mismatch for alpha %s:\n got  %s\n want %s", v.alpha, got, v.pi)
+		}
+
+		beta, err := s.Verify(&key.PublicKey, mustHex(t, v.pi), alpha)
+		if err != nil {
+			t.Fatalf("Verify rejected RFC proof: %v", err)
+		}
+		if got := hex.EncodeToString(beta); got != v.beta {
+			t.Errorf("beta mismatch: got %s, want %s", got, v.beta)
+		}
+		if hashed, _ := s.ProofToHash(pi); !bytes.Equal(hashed, beta) {
+			t.Error("ProofToHash disagrees with Verify")
+		}
+	}
+}
+
+func TestECVRFSecp256k1RejectsTampering(t *testing.T) {
+	s := Secp256k1SHA256TAI
+	key, _ := crypto.GenerateKey()
+	alpha := []byte("lottery-round-7")
+
+	pi, err := s.Prove(key, alpha)
+	if err != nil {
+		t.Fatalf("Prove failed: %v", err)
+	}
+	again, _ := s.Prove(key, alpha)
+	if !bytes.Equal(pi, again) {
+		t.Error("Proofs for the same key and input must be identical")
+	}
+	if _, err := s.Verify(&key.PublicKey, pi, alpha); err != nil {
+		t.Fatalf("Valid proof rejected: %v", err)
+	}
+
+	if _, err := s.Verify(&key.PublicKey, pi, []byte("lottery-round-8")); err == nil {
+		t.Error("Proof accepted for a different input")
+	}
+
+	other, _ := crypto.GenerateKey()
+	if _, err := s.Verify(&other.PublicKey, pi, alpha); err == nil {
+		t.Error("Proof accepted under a different key")
+	}
+
+	tampered := append([]byte{}, pi...)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := s.Verify(&key.PublicKey, tampered, alpha); err == nil {
+		t.Error("Tampered proof accepted")
+	}
+}
+
+func TestVerifyRandomnessWithPublishedKey(t *testing.T) {
+	rm := NewRandomnessManager("")
+	val, proof, err := rm.GenerateRandomness("seed-42")
+	if err != nil {
+		t.Fatalf("Generation error: %v", err)
+	}
+
+	if !VerifyRandomness(rm.PublicKey(), "seed-42", proof, val) {
+		t.Error("Consumer-side verification failed")
+	}
+	if VerifyRandomness(NewRandomnessManager("").PublicKey(), "seed-42", proof, val) {
+		t.Error("Verification passed against another node's key")
+	}
+	if VerifyRandomness(rm.PublicKey(), "seed-42", proof, "12345") {
+		t.Error("Verification passed for a value that is not the VRF output")
+	}
+}
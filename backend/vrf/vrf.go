@@ -33,72 +33,75 @@ func NewRandomnessManager(pkHex string) *RandomnessManager {
 		log.Warn().Msg("No VRF private key provided, using ephemeral session key")
 	}
 
-	log.Info().Str("public_key", crypto.PubkeyToAddress(pk.PublicKey).Hex()).Msg("VRF Manager Initialized")
-	
+	log.Info().
+		Str("address", crypto.PubkeyToAddress(pk.PublicKey).Hex()).
+		Str("vrf_public_key", hex.EncodeToString(Secp256k1SHA256TAI.MarshalPublicKey(&pk.PublicKey))).
+		Msg("VRF Manager Initialized")
+
 	return &RandomnessManager{
 		privateKey: pk,
 	}
 }
 
-// GenerateRandomness produces a deterministic random number and a proof from a seed.
-// This implementation uses RFC 6979 deterministic signatures (provided by libsecp256k1 via Geth)
-// to ensure that for a given seed and private key, exactly one valid signature/random-value exists.
+// PublicKey returns the compressed public key consumers verify proofs against
+func (rm *RandomnessManager) PublicKey() string {
+	return hex.EncodeToString(Secp256k1SHA256TAI.MarshalPublicKey(&rm.privateKey.PublicKey))
+}
+
+// GenerateRandomness evaluates the ECVRF (Secp256k1SHA256TAI) on the seed and
+// returns the output as a decimal integer together with the hex-encoded proof.
+// Unlike a signature hash, exactly one output verifies for a given key and seed.
 func (rm *RandomnessManager) GenerateRandomness(seed string) (string, string, error) {
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
 
-	// 1. Prepare entropy from seed
-	// We hash the seed to ensure we have a standard 32-byte input
-	seedHash := crypto.Keccak256Hash([]byte(seed))
-	
-	// 2. Generate the deterministic signature
-	// The crypto.Sign function in Geth uses secp256k1 which is deterministic by default.
-	signature, err := crypto.Sign(seedHash.Bytes(), rm.privateKey)
+	pi, err := Secp256k1SHA256TAI.Prove(rm.privateKey, []byte(seed))
 	if err != nil {
-		log.Error().Err(err).Msg("VRF signature generation failed")
+		log.Error().Err(err).Msg("VRF proof generation failed")
 		return "", "", fmt.Errorf("cryptographic failure: %w", err)
 	}
-
-	// 3. Derive the Random Value from the signature
-	// We hash the signature to produce the final VRF output.
-	// This ensures the value is indistinguishable from random data to anyone without the private key.
-	randomValue := crypto.Keccak256Hash(signature)
-	randomInt := new(big.Int).SetBytes(randomValue.Bytes())
+	beta, err := Secp256k1SHA256TAI.ProofToHash(pi)
+	if err != nil {
+		return "", "", fmt.Errorf("cryptographic failure: %w", err)
+	}
+	randomInt := new(big.Int).SetBytes(beta)
 
 	log.Info().
 		Str("seed", seed).
 		Str("random_val", randomInt.String()[:10]+"...").
 		Msg("Deterministic VRF output generated")
 
-	return randomInt.String(), hex.EncodeToString(signature), nil
+	return randomInt.String(), hex.EncodeToString(pi), nil
 }
 
 // VerifyRandomness performs a local verification of the VRF output.
 func (rm *RandomnessManager) VerifyRandomness(seed, proofHex, value string) bool {
-	sig, err := hex.DecodeString(proofHex)
+	return VerifyRandomness(rm.PublicKey(), seed, proofHex, value)
+}
+
+// VerifyRandomness checks a VRF output against the publishing node's public key
+func VerifyRandomness(publicKeyHex, seed, proofHex, value string) bool {
+	pubBytes, err := hex.DecodeString(publicKeyHex)
 	if err != nil {
 		return false
 	}
-
-	seedHash := crypto.Keccak256Hash([]byte(seed))
-	
-	// Recover the public key from the signature to prove the source
-	pubKey, err := crypto.SigToPub(seedHash.Bytes(), sig)
+	pub, err := Secp256k1SHA256TAI.UnmarshalPublicKey(pubBytes)
+	if err != nil {
+		return false
+	}
+	pi, err := hex.DecodeString(proofHex)
 	if err != nil {
 		return false
 	}
 
-	// Assert the public key matches the manager's authorized key
-	if crypto.PubkeyToAddress(*pubKey) != crypto.PubkeyToAddress(rm.privateKey.PublicKey) {
+	beta, err := Secp256k1SHA256TAI.Verify(pub, pi, []byte(seed))
+	if err != nil {
 		return false
 	}
 
-	// Verify the hash of the signature matches the provided value
-	expectedValue := crypto.Keccak256Hash(sig)
 	providedValue, ok := new(big.Int).SetString(value, 10)
 	if !ok {
 		return false
 	}
-
-	return expectedValue.Big().Cmp(providedValue) == 0
+	return new(big.Int).SetBytes(beta).Cmp(providedValue) == 0
 }
@@ -1,24 +1,32 @@
 package zkp
 
 import (
+	"fmt"
 	"math/big"
 
-	"github.com/consensys/gnark-crypto/ecc"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
 	"github.com/consensys/gnark/backend/groth16"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
 )
 
 // ============================================================================
 // TWAP (Time-Weighted Average Price) Circuit
 // ============================================================================
 
+// TWAPMaxObservations is the number of price observations one TWAP proof
+// covers; shorter windows are padded by repeating the last observation
+const TWAPMaxObservations = 32
+
 // TWAPCircuit proves that a TWAP value was correctly computed from a set of
 // price observations without revealing the individual prices.
 //
+// Each price holds from its timestamp until the next one, the last until
+// EndTime, so the first observation must be the one in effect at StartTime.
+//
 // Public Inputs:
-// - TWAPResult: The claimed TWAP value
+// - TWAPResult: The claimed TWAP value, rounded down
 // - StartTime: Beginning of the TWAP window
 // - EndTime: End of the TWAP window
 // - MinBound: Lower bound for the TWAP (range proof)
@@ -27,6 +35,7 @@ import (
 // Private Inputs:
 // - Prices: Array of price observations
 // - Timestamps: Corresponding timestamps for each observation
+// - Remainder: Weighted sum modulo the window length
 type TWAPCircuit struct {
 	// Public inputs
 	TWAPResult frontend.Variable `gnark:",public"`
@@ -36,35 +45,41 @@ type TWAPCircuit struct {
 	MaxBound   frontend.Variable `gnark:",public"`
 
 	// Private inputs (hidden from verifier)
-	Prices     [10]frontend.Variable `gnark:",secret"`
-	Timestamps [10]frontend.Variable `gnark:",secret"`
-	NumPoints  frontend.Variable     `gnark:",secret"`
+	Prices     [TWAPMaxObservations]frontend.Variable `gnark:",secret"`
+	Timestamps [TWAPMaxObservations]frontend.Variable `gnark:",secret"`
+	Remainder  frontend.Variable                      `gnark:",secret"`
 }
 
 func (c *TWAPCircuit) Define(api frontend.API) error {
-	// 1. Verify timestamps are within the window
-	for i := 0; i < 10; i++ {
-		// timestamp >= startTime (or zero if unused)
-		api.AssertIsLessOrEqual(c.StartTime, api.Add(c.Timestamps[i], 1))
-		// timestamp <= endTime
-		api.AssertIsLessOrEqual(c.Timestamps[i], c.EndTime)
-	}
+	// 1. Observations start the window and are in time order. Decomposing
+	// each gap into 64 bits proves it non-negative.
+	api.AssertIsEqual(c.Timestamps[0], c.StartTime)
+	last := TWAPMaxObservations - 1
 
-	// 2. Calculate time-weighted sum
-	// TWAP = Σ(price_i * (t_{i+1} - t_i)) / (t_n - t_0)
+	// 2. Calculate time-weighted sum; the gaps sum to EndTime - StartTime
+	// TWAP = Σ(price_i * (t_{i+1} - t_i)) / (t_end - t_start)
 	var weightedSum frontend.Variable = frontend.Variable(0)
 
-	for i := 0; i < 9; i++ {
-		timeDiff := api.Sub(c.Timestamps[i+1], c.Timestamps[i])
-		weighted := api.Mul(c.Prices[i], timeDiff)
-		weightedSum = api.Add(weightedSum, weighted)
+	for i := 0; i <= last; i++ {
+		next := c.EndTime
+		if i < last {
+			next = c.Timestamps[i+1]
+		}
+		timeDiff := api.Sub(next, c.Timestamps[i])
+		api.ToBinary(timeDiff, 64)
+
+		// Bounded prices keep the sum from wrapping the field
+		api.ToBinary(c.Prices[i], 128)
+		weightedSum = api.Add(weightedSum, api.Mul(c.Prices[i], timeDiff))
 	}
 
+	// 3. Assert TWAP is the floor of weightedSum / totalTime
 	totalTime := api.Sub(c.EndTime, c.StartTime)
-	expectedTWAP := api.Div(weightedSum, totalTime)
-
-	// 3. Assert TWAP matches claimed value
-	api.AssertIsEqual(c.TWAPResult, expectedTWAP)
+	api.AssertIsDifferent(totalTime, 0)
+	api.AssertIsEqual(weightedSum, api.Add(api.Mul(c.TWAPResult, totalTime), c.Remainder))
+	api.ToBinary(c.Remainder, 64)
+	api.ToBinary(api.Sub(totalTime, api.Add(c.Remainder, 1)), 64)
+	api.ToBinary(c.TWAPResult, 128)
 
 	// 4. Range proof: TWAP is within bounds
 	api.AssertIsLessOrEqual(c.MinBound, c.TWAPResult)
@@ -74,13 +89,13 @@ func (c *TWAPCircuit) Define(api frontend.API) error {
 }
 
 // ============================================================================
-// Proof of Reserves Circuit (Pedersen Commitment)
+// Proof of Reserves Circuit (MiMC Commitments)
 // ============================================================================
 
 // ProofOfReservesCircuit proves that committed reserves exceed liabilities
 // without revealing the exact amounts.
 //
-// Uses Pedersen commitments: C = g^r * h^v where r is randomness, v is value
+// Commitments are MiMC(amount, blinding); see Commit for the native side.
 type ProofOfReservesCircuit struct {
 	// Public inputs
 	ReserveCommitment   frontend.Variable `gnark:",public"`
@@ -88,17 +103,22 @@ type ProofOfReservesCircuit struct {
 	SolvencyProof       frontend.Variable `gnark:",public"` // Reserves >= Liabilities
 
 	// Private inputs
-	ReserveAmount    frontend.Variable `gnark:",secret"`
-	ReserveBlinding  frontend.Variable `gnark:",secret"`
-	LiabilityAmount  frontend.Variable `gnark:",secret"`
+	ReserveAmount     frontend.Variable `gnark:",secret"`
+	ReserveBlinding   frontend.Variable `gnark:",secret"`
+	LiabilityAmount   frontend.Variable `gnark:",secret"`
 	LiabilityBlinding frontend.Variable `gnark:",secret"`
 }
 
 func (c *ProofOfReservesCircuit) Define(api frontend.API) error {
-	// 1. Verify commitment openings (simplified for demo)
-	// In production, use MiMC or Poseidon hash for commitments
-	reserveCommit := api.Add(c.ReserveAmount, c.ReserveBlinding)
-	liabilityCommit := api.Add(c.LiabilityAmount, c.LiabilityBlinding)
+	// 1. Verify commitment openings
+	reserveCommit, err := mimcHash(api, c.ReserveAmount, c.ReserveBlinding)
+	if err != nil {
+		return err
+	}
+	liabilityCommit, err := mimcHash(api, c.LiabilityAmount, c.LiabilityBlinding)
+	if err != nil {
+		return err
+	}
 
 	api.AssertIsEqual(c.ReserveCommitment, reserveCommit)
 	api.AssertIsEqual(c.LiabilityCommitment, liabilityCommit)
@@ -116,75 +136,55 @@ func (c *ProofOfReservesCircuit) Define(api frontend.API) error {
 // Selective Disclosure Circuit
 // ============================================================================
 
-// SelectiveDisclosureCircuit allows revealing data only to authorized parties
-// by encrypting the data to a specific public key.
+// SelectiveDisclosureCircuit proves a committed value lies in a range and
+// that the same value is encrypted to an authorized BabyJubjub key, so only
+// that party can read it. The encryption is ECIES-style: an ephemeral key
+// R = r*G is published and the value is masked with MiMC(r*PubKey).
 type SelectiveDisclosureCircuit struct {
 	// Public inputs
-	DataCommitment   frontend.Variable `gnark:",public"`
-	AuthorizedPubKey frontend.Variable `gnark:",public"`
-	EncryptedData    frontend.Variable `gnark:",public"`
+	DataCommitment   frontend.Variable    `gnark:",public"`
+	AuthorizedPubKey twistededwards.Point `gnark:",public"`
+	EphemeralKey     twistededwards.Point `gnark:",public"`
+	EncryptedData    frontend.Variable    `gnark:",public"`
+	RangeMin         frontend.Variable    `gnark:",public"`
+	RangeMax         frontend.Variable    `gnark:",public"`
 
 	// Private inputs
-	RawData        frontend.Variable `gnark:",secret"`
-	Randomness     frontend.Variable `gnark:",secret"`
-	DataInRange    frontend.Variable `gnark:",secret"` // 1 if in range, 0 otherwise
-	RangeMin       frontend.Variable `gnark:",public"`
-	RangeMax       frontend.Variable `gnark:",public"`
+	RawData         frontend.Variable `gnark:",secret"`
+	Randomness      frontend.Variable `gnark:",secret"`
+	EphemeralSecret frontend.Variable `gnark:",secret"`
 }
 
 func (c *SelectiveDisclosureCircuit) Define(api frontend.API) error {
 	// 1. Verify data commitment
-	commit := api.Add(c.RawData, c.Randomness)
+	commit, err := mimcHash(api, c.RawData, c.Randomness)
+	if err != nil {
+		return err
+	}
 	api.AssertIsEqual(c.DataCommitment, commit)
 
-	// 2. Verify encryption to authorized key (simplified)
-	encrypted := api.Mul(c.RawData, c.AuthorizedPubKey)
-	api.AssertIsEqual(c.EncryptedData, encrypted)
-
-	// 3. Range proof for data
-	api.AssertIsLessOrEqual(c.RangeMin, c.RawData)
-	api.AssertIsLessOrEqual(c.RawData, c.RangeMax)
-
-	return nil
-}
-
-// ============================================================================
-// Recursive Aggregation Circuit
-// ============================================================================
-
-// AggregationCircuit aggregates multiple proofs into a single proof
-// This enables batching 1000+ data points into one on-chain verification
-type AggregationCircuit struct {
-	// Public inputs
-	FinalValue     frontend.Variable   `gnark:",public"`
-	ProofHashes    [8]frontend.Variable `gnark:",public"` // Up to 8 sub-proofs
-	AggregationType frontend.Variable   `gnark:",public"` // 0=median, 1=mean, 2=min, 3=max
-
-	// Private inputs
-	SubValues [8]frontend.Variable `gnark:",secret"`
-	Weights   [8]frontend.Variable `gnark:",secret"` // For weighted aggregation
-}
-
-func (c *AggregationCircuit) Define(api frontend.API) error {
-	// 1. Verify proof hashes match sub-values (simplified)
-	for i := 0; i < 8; i++ {
-		expectedHash := api.Add(c.SubValues[i], frontend.Variable(i))
-		api.AssertIsEqual(c.ProofHashes[i], expectedHash)
+	// 2. Verify encryption to the authorized key
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
 	}
+	curve.AssertIsOnCurve(c.AuthorizedPubKey)
+	base := twistededwards.Point{X: curve.Params().Base[0], Y: curve.Params().Base[1]}
 
-	// 2. Calculate aggregated value based on type
-	// For simplicity, we implement weighted mean here
-	var weightedSum frontend.Variable = frontend.Variable(0)
-	var totalWeight frontend.Variable = frontend.Variable(0)
+	ephemeral := curve.ScalarMul(base, c.EphemeralSecret)
+	api.AssertIsEqual(c.EphemeralKey.X, ephemeral.X)
+	api.AssertIsEqual(c.EphemeralKey.Y, ephemeral.Y)
 
-	for i := 0; i < 8; i++ {
-		weighted := api.Mul(c.SubValues[i], c.Weights[i])
-		weightedSum = api.Add(weightedSum, weighted)
-		totalWeight = api.Add(totalWeight, c.Weights[i])
+	shared := curve.ScalarMul(c.AuthorizedPubKey, c.EphemeralSecret)
+	pad, err := mimcHash(api, shared.X, shared.Y)
+	if err != nil {
+		return err
 	}
+	api.AssertIsEqual(c.EncryptedData, api.Add(c.RawData, pad))
 
-	expectedValue := api.Div(weightedSum, totalWeight)
-	api.AssertIsEqual(c.FinalValue, expectedValue)
+	// 3. Range proof for data
+	api.AssertIsLessOrEqual(c.RangeMin, c.RawData)
+	api.AssertIsLessOrEqual(c.RawData, c.RangeMax)
 
 	return nil
 }
@@ -211,131 +211,196 @@ var (
 	aggVK       groth16.VerifyingKey
 )
 
+// advancedCircuits share the key directory with the core circuits
+var advancedCircuits = []circuitDef{
+	{"twap", 1, func() frontend.Circuit { return &TWAPCircuit{} }, &twapCCS, &twapPK, &twapVK},
+	{"por", 1, func() frontend.Circuit { return &ProofOfReservesCircuit{} }, &porCCS, &porPK, &porVK},
+	{"selective_disclosure", 1, func() frontend.Circuit { return &SelectiveDisclosureCircuit{} }, &sdCCS, &sdPK, &sdVK},
+	{"aggregation", 2, func() frontend.Circuit { return newAggregationCircuit(AggregationBatchSize) }, &aggCCS, &aggPK, &aggVK},
+}
+
 // InitAdvancedCircuits initializes the advanced ZK circuits
 func InitAdvancedCircuits() error {
-	var err error
+	for _, def := range advancedCircuits {
+		if err := setupCircuit(def); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	// 1. TWAP Circuit
-	var twapCircuit TWAPCircuit
-	twapCCS, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &twapCircuit)
-	if err != nil {
-		return err
+// ComputeTWAP returns the time-weighted average of prices over
+// [startTime, endTime], rounded down, with the remainder the circuit needs.
+// timestamps must be ascending and start at startTime.
+func ComputeTWAP(prices []*big.Int, timestamps []uint64, startTime, endTime uint64) (*big.Int, *big.Int, error) {
+	if len(prices) == 0 || len(prices) != len(timestamps) {
+		return nil, nil, fmt.Errorf("need matching prices and timestamps, got %d and %d", len(prices), len(timestamps))
 	}
-	twapPK, twapVK, err = groth16.Setup(twapCCS)
-	if err != nil {
-		return err
+	if len(prices) > TWAPMaxObservations {
+		return nil, nil, fmt.Errorf("%d observations exceed the TWAP circuit's %d", len(prices), TWAPMaxObservations)
+	}
+	if endTime <= startTime || timestamps[0] != startTime {
+		return nil, nil, fmt.Errorf("observations must start the window [%d, %d]", startTime, endTime)
 	}
 
-	// 2. Proof of Reserves Circuit
-	var porCircuit ProofOfReservesCircuit
-	porCCS, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &porCircuit)
-	if err != nil {
-		return err
+	sum := new(big.Int)
+	for i, price := range prices {
+		next := endTime
+		if i+1 < len(timestamps) {
+			next = timestamps[i+1]
+		}
+		if next < timestamps[i] {
+			return nil, nil, fmt.Errorf("observation %d is out of time order", i)
+		}
+		sum.Add(sum, new(big.Int).Mul(price, new(big.Int).SetUint64(next-timestamps[i])))
 	}
-	porPK, porVK, err = groth16.Setup(porCCS)
-	if err != nil {
-		return err
+
+	twap, rem := new(big.Int).QuoRem(sum, new(big.Int).SetUint64(endTime-startTime), new(big.Int))
+	return twap, rem, nil
+}
+
+// BucketTWAPObservations fits a window with more observations than the TWAP
+// circuit takes into TWAPMaxObservations equal time buckets, each holding the
+// time-weighted average of the window over that bucket, rounded down. The
+// TWAP of the buckets is within one unit of the TWAP of the observations.
+// Windows that already fit are returned unchanged.
+func BucketTWAPObservations(prices []*big.Int, timestamps []uint64, startTime, endTime uint64) ([]*big.Int, []uint64, error) {
+	if len(prices) <= TWAPMaxObservations {
+		return prices, timestamps, nil
+	}
+	if len(prices) != len(timestamps) {
+		return nil, nil, fmt.Errorf("need matching prices and timestamps, got %d and %d", len(prices), len(timestamps))
+	}
+	if endTime <= startTime || timestamps[0] != startTime {
+		return nil, nil, fmt.Errorf("observations must start the window [%d, %d]", startTime, endTime)
 	}
 
-	// 3. Selective Disclosure Circuit
-	var sdCircuit SelectiveDisclosureCircuit
-	sdCCS, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &sdCircuit)
-	if err != nil {
-		return err
+	total := endTime - startTime
+	buckets := uint64(TWAPMaxObservations)
+	if total < buckets {
+		buckets = total
 	}
-	sdPK, sdVK, err = groth16.Setup(sdCCS)
-	if err != nil {
-		return err
+
+	outPrices := make([]*big.Int, 0, buckets)
+	outTimes := make([]uint64, 0, buckets)
+	j := 0
+	for b := uint64(0); b < buckets; b++ {
+		from := startTime + total*b/buckets
+		to := startTime + total*(b+1)/buckets
+
+		sum := new(big.Int)
+		for t := from; t < to; {
+			for j+1 < len(timestamps) && timestamps[j+1] <= t {
+				j++
+			}
+			next := endTime
+			if j+1 < len(timestamps) {
+				next = timestamps[j+1]
+			}
+			if next < timestamps[j] {
+				return nil, nil, fmt.Errorf("observation %d is out of time order", j)
+			}
+			next = min(next, to)
+			sum.Add(sum, new(big.Int).Mul(prices[j], new(big.Int).SetUint64(next-t)))
+			t = next
+		}
+		outPrices = append(outPrices, sum.Quo(sum, new(big.Int).SetUint64(to-from)))
+		outTimes = append(outTimes, from)
 	}
+	return outPrices, outTimes, nil
+}
 
-	// 4. Aggregation Circuit
-	var aggCircuit AggregationCircuit
-	aggCCS, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &aggCircuit)
+// GenerateTWAPProof proves the TWAP of the observations lies in [minBound, maxBound]
+func GenerateTWAPProof(startTime, endTime uint64, minBound, maxBound *big.Int,
+	prices []*big.Int, timestamps []uint64) (*big.Int, Proof, error) {
+
+	assignment, err := twapAssignment(startTime, endTime, minBound, maxBound, prices, timestamps)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	aggPK, aggVK, err = groth16.Setup(aggCCS)
+	proof, err := Prove("twap", assignment)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-
-	return nil
+	return assignment.TWAPResult.(*big.Int), proof, nil
 }
 
-// GenerateTWAPProof creates a ZK proof for TWAP calculation
-func GenerateTWAPProof(twap *big.Int, startTime, endTime uint64, minBound, maxBound *big.Int, 
-	prices [10]*big.Int, timestamps [10]uint64) (groth16.Proof, error) {
-	
-	if twapCCS == nil {
-		if err := InitAdvancedCircuits(); err != nil {
-			return nil, err
-		}
-	}
+func twapAssignment(startTime, endTime uint64, minBound, maxBound *big.Int,
+	prices []*big.Int, timestamps []uint64) (*TWAPCircuit, error) {
 
-	// Convert inputs
-	var priceVars [10]frontend.Variable
-	var tsVars [10]frontend.Variable
-	for i := 0; i < 10; i++ {
-		if prices[i] != nil {
-			priceVars[i] = prices[i]
-		} else {
-			priceVars[i] = big.NewInt(0)
-		}
-		tsVars[i] = timestamps[i]
+	twap, rem, err := ComputeTWAP(prices, timestamps, startTime, endTime)
+	if err != nil {
+		return nil, err
 	}
 
-	witness, err := frontend.NewWitness(&TWAPCircuit{
+	assignment := &TWAPCircuit{
 		TWAPResult: twap,
 		StartTime:  startTime,
 		EndTime:    endTime,
 		MinBound:   minBound,
 		MaxBound:   maxBound,
-		Prices:     priceVars,
-		Timestamps: tsVars,
-		NumPoints:  10,
-	}, ecc.BN254.ScalarField())
-	if err != nil {
-		return nil, err
+		Remainder:  rem,
+	}
+	// Padding repeats the last observation, adding zero-length intervals
+	for i := 0; i < TWAPMaxObservations; i++ {
+		j := min(i, len(prices)-1)
+		assignment.Prices[i] = prices[j]
+		assignment.Timestamps[i] = timestamps[j]
 	}
+	return assignment, nil
+}
 
-	return groth16.Prove(twapCCS, twapPK, witness)
+// VerifyTWAPProof checks a TWAP proof against its public inputs
+func VerifyTWAPProof(proof Proof, twap *big.Int, startTime, endTime uint64, minBound, maxBound *big.Int) error {
+	return Verify("twap", proof, &TWAPCircuit{
+		TWAPResult: twap,
+		StartTime:  startTime,
+		EndTime:    endTime,
+		MinBound:   minBound,
+		MaxBound:   maxBound,
+	})
 }
 
 // GenerateProofOfReserves creates a ZK proof for reserve attestation
 func GenerateProofOfReserves(reserves, liabilities *big.Int, 
-	reserveBlinding, liabilityBlinding *big.Int) (groth16.Proof, error) {
-	
-	if porCCS == nil {
-		if err := InitAdvancedCircuits(); err != nil {
-			return nil, err
-		}
-	}
+	reserveBlinding, liabilityBlinding *big.Int) (Proof, error) {
 
-	reserveCommit := new(big.Int).Add(reserves, reserveBlinding)
-	liabilityCommit := new(big.Int).Add(liabilities, liabilityBlinding)
+	return Prove("por", reservesAssignment(reserves, liabilities, reserveBlinding, liabilityBlinding))
+}
 
-	witness, err := frontend.NewWitness(&ProofOfReservesCircuit{
-		ReserveCommitment:   reserveCommit,
-		LiabilityCommitment: liabilityCommit,
+func reservesAssignment(reserves, liabilities, reserveBlinding, liabilityBlinding *big.Int) *ProofOfReservesCircuit {
+	return &ProofOfReservesCircuit{
+		ReserveCommitment:   Commit(reserves, reserveBlinding),
+		LiabilityCommitment: Commit(liabilities, liabilityBlinding),
 		SolvencyProof:       big.NewInt(1),
 		ReserveAmount:       reserves,
 		ReserveBlinding:     reserveBlinding,
 		LiabilityAmount:     liabilities,
 		LiabilityBlinding:   liabilityBlinding,
-	}, ecc.BN254.ScalarField())
-	if err != nil {
-		return nil, err
 	}
+}
 
-	return groth16.Prove(porCCS, porPK, witness)
+// VerifyProofOfReserves checks a solvency proof against the two commitments
+func VerifyProofOfReserves(proof Proof, reserveCommitment, liabilityCommitment *big.Int) error {
+	return Verify("por", proof, &ProofOfReservesCircuit{
+		ReserveCommitment:   reserveCommitment,
+		LiabilityCommitment: liabilityCommitment,
+		SolvencyProof:       big.NewInt(1),
+	})
 }
 
-// GetTWAPVerifier returns the TWAP verifier key for export
+// GetTWAPVerifier returns the TWAP verifier key for export.
+//
+// Deprecated: the key is nil until the circuit is initialized and under
+// PLONK; use ExportVerifier.
 func GetTWAPVerifier() groth16.VerifyingKey {
 	return twapVK
 }
 
-// GetPoRVerifier returns the Proof of Reserves verifier key for export
+// GetPoRVerifier returns the Proof of Reserves verifier key for export.
+//
+// Deprecated: the key is nil until the circuit is initialized and under
+// PLONK; use ExportVerifier.
 func GetPoRVerifier() groth16.VerifyingKey {
 	return porVK
 }
@@ -0,0 +1,95 @@
+package zkp
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestTWAPProofMatchesTimeWeightedAverage(t *testing.T) {
+	// 100 for 60s, 130 for 30s, 90 for the last 30s of a 120s window
+	prices := []*big.Int{big.NewInt(100), big.NewInt(130), big.NewInt(90)}
+	timestamps := []uint64{1000, 1060, 1090}
+	start, end := uint64(1000), uint64(1120)
+
+	twap, proof, err := GenerateTWAPProof(start, end, big.NewInt(0), big.NewInt(1000), prices, timestamps)
+	if err != nil {
+		t.Fatalf("TWAP proof failed: %v", err)
+	}
+	// (100*60 + 130*30 + 90*30) / 120 = 105
+	if twap.Int64() != 105 {
+		t.Errorf("Expected TWAP 105, got %v", twap)
+	}
+	if err := VerifyTWAPProof(proof, twap, start, end, big.NewInt(0), big.NewInt(1000)); err != nil {
+		t.Errorf("Valid TWAP proof rejected: %v", err)
+	}
+	if err := VerifyTWAPProof(proof, big.NewInt(110), start, end, big.NewInt(0), big.NewInt(1000)); err == nil {
+		t.Error("Proof accepted for a different TWAP")
+	}
+
+	if _, _, err := GenerateTWAPProof(start, end, big.NewInt(106), big.NewInt(1000), prices, timestamps); err == nil {
+		t.Error("TWAP below the lower bound produced a proof")
+	}
+
+	// The first observation must be the one in effect at the window start
+	if _, _, err := ComputeTWAP(prices, []uint64{1010, 1060, 1090}, start, end); err == nil {
+		t.Error("Expected a window not covered from its start to be rejected")
+	}
+	if _, _, err := ComputeTWAP(prices, []uint64{1000, 1090, 1060}, start, end); err == nil {
+		t.Error("Expected out-of-order observations to be rejected")
+	}
+
+	// Rounding down must still prove
+	twap, rem, _ := ComputeTWAP([]*big.Int{big.NewInt(1), big.NewInt(2)}, []uint64{0, 1}, 0, 3)
+	if twap.Int64() != 1 || rem.Int64() != 2 {
+		t.Errorf("Expected 5/3 = 1 rem 2, got %v rem %v", twap, rem)
+	}
+	if _, _, err := GenerateTWAPProof(0, 3, big.NewInt(0), big.NewInt(10), []*big.Int{big.NewInt(1), big.NewInt(2)}, []uint64{0, 1}); err != nil {
+		t.Errorf("Rounded TWAP failed to prove: %v", err)
+	}
+}
+
+func TestBucketTWAPObservationsStaysWithinOneUnit(t *testing.T) {
+	// 500 irregular observations over an hour
+	start, end := uint64(1_700_000_000), uint64(1_700_003_600)
+	prices := make([]*big.Int, 500)
+	timestamps := make([]uint64, 500)
+	exact := new(big.Int)
+	for i := range prices {
+		prices[i] = big.NewInt(3800_00000000 + int64(i*i%977)*1_000_000)
+		timestamps[i] = start + uint64(i*7+i%5)
+	}
+	for i := range prices {
+		next := end
+		if i+1 < len(timestamps) {
+			next = timestamps[i+1]
+		}
+		exact.Add(exact, new(big.Int).Mul(prices[i], new(big.Int).SetUint64(next-timestamps[i])))
+	}
+	exact.Quo(exact, new(big.Int).SetUint64(end-start))
+
+	bucketPrices, bucketTimes, err := BucketTWAPObservations(prices, timestamps, start, end)
+	if err != nil {
+		t.Fatalf("BucketTWAPObservations failed: %v", err)
+	}
+	if len(bucketPrices) != TWAPMaxObservations || bucketTimes[0] != start {
+		t.Fatalf("Expected %d buckets from the window start, got %d from %d", TWAPMaxObservations, len(bucketPrices), bucketTimes[0])
+	}
+	twap, _, err := ComputeTWAP(bucketPrices, bucketTimes, start, end)
+	if err != nil {
+		t.Fatalf("ComputeTWAP over buckets failed: %v", err)
+	}
+	if diff := new(big.Int).Sub(exact, twap); diff.Sign() < 0 || diff.Cmp(big.NewInt(1)) > 0 {
+		t.Errorf("Bucketed TWAP %v is more than one unit from %v", twap, exact)
+	}
+
+	// Windows shorter than the bucket count get one-second buckets
+	short, shortTimes, err := BucketTWAPObservations(prices[:40], make([]uint64, 40), 0, 10)
+	if err != nil || len(short) != 10 || shortTimes[9] != 9 {
+		t.Errorf("Expected ten one-second buckets, got %d: %v", len(short), err)
+	}
+
+	// Windows that fit are left alone
+	if p, _, _ := BucketTWAPObservations(prices[:3], timestamps[:3], start, end); len(p) != 3 {
+		t.Errorf("Expected a short window to be unchanged, got %d observations", len(p))
+	}
+}
@@ -0,0 +1,188 @@
+package zkp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	gsha3 "github.com/consensys/gnark/std/hash/sha3"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// AggregationBatchSize is the number of feed updates one aggregated proof
+// covers, so a 40-feed heartbeat is settled in five on-chain calls
+const AggregationBatchSize = 8
+
+// AggregationValueBits bounds each aggregated value; data feeds are scaled
+// into a uint64
+const AggregationValueBits = 64
+
+// FeedBounds are the public bounds of one feed value in the batch
+type FeedBounds struct {
+	Min frontend.Variable `gnark:",public"`
+	Max frontend.Variable `gnark:",public"`
+}
+
+// AggregationCircuit proves that every value of a batch of feed updates lies
+// within its request's bounds, with a single Groth16 pairing check on chain.
+// ValuesHash commits to the values as
+// uint256(keccak256(abi.encodePacked(values))) mod r, which the oracle
+// recomputes from the values it records, so a proof only covers the values it
+// was made for. The public inputs are ValuesHash followed by [Min, Max] of
+// each update in batch order.
+//
+// Range proofs keep their value secret, so verifying them recursively could
+// not tie them to the posted values; the bounds are checked directly instead.
+type AggregationCircuit struct {
+	ValuesHash frontend.Variable `gnark:",public"`
+	Feeds      []FeedBounds
+	Values     []frontend.Variable `gnark:",secret"`
+}
+
+// newAggregationCircuit sizes the circuit for n updates
+func newAggregationCircuit(n int) *AggregationCircuit {
+	return &AggregationCircuit{
+		Feeds:  make([]FeedBounds, n),
+		Values: make([]frontend.Variable, n),
+	}
+}
+
+func (c *AggregationCircuit) Define(api frontend.API) error {
+	if len(c.Feeds) != len(c.Values) {
+		return fmt.Errorf("%d feeds but %d values", len(c.Feeds), len(c.Values))
+	}
+
+	bytes, err := uints.NewBytes(api)
+	if err != nil {
+		return err
+	}
+	keccak, err := gsha3.NewLegacyKeccak256(api)
+	if err != nil {
+		return err
+	}
+
+	zero := uints.NewU8(0)
+	for i, value := range c.Values {
+		api.AssertIsLessOrEqual(c.Feeds[i].Min, value)
+		api.AssertIsLessOrEqual(value, c.Feeds[i].Max)
+
+		// abi.encodePacked widens each value to a 32-byte big-endian word
+		bits := api.ToBinary(value, AggregationValueBits)
+		word := make([]uints.U8, 32)
+		for j := range word {
+			word[j] = zero
+		}
+		for j := 0; j < AggregationValueBits/8; j++ {
+			word[31-j] = bytes.ValueOf(api.FromBinary(bits[8*j : 8*j+8]...))
+		}
+		keccak.Write(word)
+	}
+
+	// Accumulating the digest big-endian in the field reduces it mod r
+	var digest frontend.Variable = 0
+	for _, b := range keccak.Sum() {
+		digest = api.Add(api.Mul(digest, 256), bytes.Value(b))
+	}
+	api.AssertIsEqual(c.ValuesHash, digest)
+	return nil
+}
+
+// RangeUpdate is a feed value and the bounds of the request it fulfills
+type RangeUpdate struct {
+	Value *big.Int
+	Min   *big.Int
+	Max   *big.Int
+}
+
+// AggregateRangeProofs proves up to AggregationBatchSize updates at once.
+// Shorter batches are padded by repeating the last update.
+func AggregateRangeProofs(updates []RangeUpdate) (Proof, error) {
+	return AggregateRangeProofsContext(context.Background(), updates)
+}
+
+// AggregateRangeProofsContext is AggregateRangeProofs, giving up when ctx is done
+func AggregateRangeProofsContext(ctx context.Context, updates []RangeUpdate) (Proof, error) {
+	padded, err := padUpdates(updates, AggregationBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	assignment, err := aggregationAssignment(padded)
+	if err != nil {
+		return nil, err
+	}
+	return ProveContext(ctx, "aggregation", assignment)
+}
+
+// VerifyAggregatedProof checks an aggregated proof against the values and
+// bounds of the updates it covers
+func VerifyAggregatedProof(proof Proof, updates []RangeUpdate) error {
+	inputs, err := AggregationPublicInputs(updates)
+	if err != nil {
+		return err
+	}
+	return VerifyPublicInputs("aggregation", proof, inputs)
+}
+
+// AggregationPublicInputs returns the public inputs of an aggregated proof in
+// the order the exported verifier expects: the values hash, then min and max
+// of each padded update
+func AggregationPublicInputs(updates []RangeUpdate) ([]*big.Int, error) {
+	padded, err := padUpdates(updates, AggregationBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	inputs := make([]*big.Int, 0, 1+2*len(padded))
+	inputs = append(inputs, aggregationValuesHash(padded))
+	for _, u := range padded {
+		inputs = append(inputs, u.Min, u.Max)
+	}
+	return inputs, nil
+}
+
+// aggregationValuesHash is uint256(keccak256(abi.encodePacked(values))) mod r
+func aggregationValuesHash(updates []RangeUpdate) *big.Int {
+	packed := make([]byte, 32*len(updates))
+	for i, u := range updates {
+		u.Value.FillBytes(packed[32*i : 32*(i+1)])
+	}
+	return FieldElement(crypto.Keccak256(packed))
+}
+
+func padUpdates(updates []RangeUpdate, size int) ([]RangeUpdate, error) {
+	if len(updates) == 0 {
+		return nil, errors.New("no feed updates to aggregate")
+	}
+	if len(updates) > size {
+		return nil, fmt.Errorf("%d feed updates exceed the batch size of %d", len(updates), size)
+	}
+	padded := make([]RangeUpdate, size)
+	copy(padded, updates)
+	for i := len(updates); i < size; i++ {
+		padded[i] = updates[len(updates)-1]
+	}
+	return padded, nil
+}
+
+// aggregationAssignment checks each update natively first, so a value outside
+// its bounds is reported by position rather than as an unsatisfied circuit
+func aggregationAssignment(updates []RangeUpdate) (*AggregationCircuit, error) {
+	c := newAggregationCircuit(len(updates))
+	for i, u := range updates {
+		if u.Value == nil || u.Min == nil || u.Max == nil {
+			return nil, fmt.Errorf("update %d is missing its value or bounds", i)
+		}
+		if u.Value.Sign() < 0 || u.Value.BitLen() > AggregationValueBits {
+			return nil, fmt.Errorf("update %d value does not fit %d bits", i, AggregationValueBits)
+		}
+		if u.Value.Cmp(u.Min) < 0 || u.Value.Cmp(u.Max) > 0 {
+			return nil, fmt.Errorf("update %d value is outside [%s, %s]", i, u.Min, u.Max)
+		}
+		c.Values[i] = u.Value
+		c.Feeds[i] = FeedBounds{Min: u.Min, Max: u.Max}
+	}
+	c.ValuesHash = aggregationValuesHash(updates)
+	return c, nil
+}
@@ -0,0 +1,90 @@
+package zkp
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func update(value, min, max int64) RangeUpdate {
+	return RangeUpdate{Value: big.NewInt(value), Min: big.NewInt(min), Max: big.NewInt(max)}
+}
+
+// The full batch takes minutes to set up in a unit test, so this solves a
+// two-update instance of the same circuit
+func TestAggregationCircuitBindsValues(t *testing.T) {
+	updates := []RangeUpdate{
+		update(6_500_000_000_000, 6_400_000_000_000, 6_600_000_000_000),
+		update(310_000_000_000, 300_000_000_000, 320_000_000_000),
+	}
+
+	assignment, err := aggregationAssignment(updates)
+	if err != nil {
+		t.Fatalf("Failed to build assignment: %v", err)
+	}
+	if err := test.IsSolved(newAggregationCircuit(2), assignment, ecc.BN254.ScalarField()); err != nil {
+		t.Fatalf("Valid batch rejected: %v", err)
+	}
+
+	// A values hash over different values must fail
+	wrongHash, _ := aggregationAssignment(updates)
+	wrongHash.ValuesHash = aggregationValuesHash([]RangeUpdate{updates[0], update(311_000_000_000, 300_000_000_000, 320_000_000_000)})
+	if err := test.IsSolved(newAggregationCircuit(2), wrongHash, ecc.BN254.ScalarField()); err == nil {
+		t.Error("Batch accepted with a hash of other values")
+	}
+
+	// Bounds that exclude the value must fail
+	outside, _ := aggregationAssignment(updates)
+	outside.Feeds[1].Max = big.NewInt(305_000_000_000)
+	if err := test.IsSolved(newAggregationCircuit(2), outside, ecc.BN254.ScalarField()); err == nil {
+		t.Error("Batch accepted with a value outside its bounds")
+	}
+}
+
+func TestAggregationValuesHashMatchesEncodePacked(t *testing.T) {
+	updates := []RangeUpdate{update(1, 0, 2), update(258, 0, 300)}
+
+	// abi.encodePacked(uint256[]) is the 32-byte big-endian words back to back
+	packed := make([]byte, 64)
+	packed[31] = 1
+	packed[62], packed[63] = 1, 2
+	expected := new(big.Int).SetBytes(crypto.Keccak256(packed))
+	expected.Mod(expected, ecc.BN254.ScalarField())
+
+	if got := aggregationValuesHash(updates); got.Cmp(expected) != 0 {
+		t.Errorf("Expected values hash %s, got %s", expected, got)
+	}
+}
+
+func TestAggregationPadsShortBatches(t *testing.T) {
+	good := update(50, 10, 100)
+
+	inputs, err := AggregationPublicInputs([]RangeUpdate{good})
+	if err != nil {
+		t.Fatalf("AggregationPublicInputs failed: %v", err)
+	}
+	if len(inputs) != 1+2*AggregationBatchSize || inputs[len(inputs)-1].Int64() != 100 {
+		t.Errorf("Expected %d inputs padded with the last update, got %v", 1+2*AggregationBatchSize, inputs)
+	}
+	padded, _ := padUpdates([]RangeUpdate{good}, AggregationBatchSize)
+	if inputs[0].Cmp(aggregationValuesHash(padded)) != 0 {
+		t.Error("Expected the values hash to cover the padded values")
+	}
+
+	if _, err := AggregateRangeProofs(nil); err == nil {
+		t.Error("Expected an empty batch to be rejected")
+	}
+	if _, err := AggregateRangeProofs(make([]RangeUpdate, AggregationBatchSize+1)); err == nil {
+		t.Error("Expected an oversized batch to be rejected")
+	}
+
+	// A value outside its bounds is caught before proving
+	bad := good
+	bad.Value = big.NewInt(5)
+	if _, err := aggregationAssignment([]RangeUpdate{good, bad}); err == nil {
+		t.Error("Expected an out-of-bounds value to be rejected")
+	}
+}
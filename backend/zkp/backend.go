@@ -0,0 +1,349 @@
+package zkp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/plonk"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/backend/solidity"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/rs/zerolog/log"
+)
+
+// Backend is the proof system a circuit is proven with
+type Backend string
+
+const (
+	// BackendGroth16 needs a circuit-specific setup (see GenerateKeys and the ceremony)
+	BackendGroth16 Backend = "groth16"
+	// BackendPlonk derives its keys from a universal KZG SRS, so new circuits need no new setup
+	BackendPlonk Backend = "plonk"
+)
+
+// Proof is a proof from either backend
+type Proof interface {
+	io.WriterTo
+	io.ReaderFrom
+}
+
+// plonkKeySet holds a circuit compiled for PLONK and its SRS-derived keys
+type plonkKeySet struct {
+	ccs constraint.ConstraintSystem
+	pk  plonk.ProvingKey
+	vk  plonk.VerifyingKey
+}
+
+var (
+	backendMu    sync.RWMutex
+	backends     = make(map[string]Backend) // circuit name -> backend, groth16 when unset
+	plonkSRS     *kzg_bn254.SRS
+	plonkSRSPath string
+	plonkKeys    = make(map[string]*plonkKeySet)
+)
+
+// ParseBackend validates a backend name from configuration
+func ParseBackend(name string) (Backend, error) {
+	switch b := Backend(name); b {
+	case BackendGroth16, BackendPlonk:
+		return b, nil
+	default:
+		return "", fmt.Errorf("unknown proving backend %q (expected %s or %s)", name, BackendGroth16, BackendPlonk)
+	}
+}
+
+// Groth16OnlyCircuits are verified by ObscuraOracle and CrossLink, whose
+// verifier interface takes a Groth16 proof as uint256[8]. PLONK is limited to
+// the other circuits until the contracts accept PLONK proofs.
+var Groth16OnlyCircuits = []string{"range", "private", "bridge", "aggregation"}
+
+// SetBackend selects the proving backend for a circuit. Must be called before
+// the circuit is initialized.
+func SetBackend(circuit string, b Backend) error {
+	if _, ok := findCircuit(circuit); !ok {
+		return fmt.Errorf("unknown circuit: %s", circuit)
+	}
+	if _, err := ParseBackend(string(b)); err != nil {
+		return err
+	}
+	if b == BackendPlonk && slices.Contains(Groth16OnlyCircuits, circuit) {
+		return fmt.Errorf("%s proofs are verified on-chain as Groth16 uint256[8]; plonk is not supported for this circuit", circuit)
+	}
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	backends[circuit] = b
+	return nil
+}
+
+// BackendFor returns the proving backend configured for a circuit
+func BackendFor(circuit string) Backend {
+	backendMu.RLock()
+	defer backendMu.RUnlock()
+	if b, ok := backends[circuit]; ok {
+		return b
+	}
+	return BackendGroth16
+}
+
+// LoadPlonkSRS reads a universal KZG SRS in canonical form (as written by
+// gnark-crypto's kzg.SRS.WriteTo). Every PLONK circuit derives its keys from it.
+func LoadPlonkSRS(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var srs kzg_bn254.SRS
+	if _, err := srs.ReadFrom(f); err != nil {
+		return fmt.Errorf("failed to read KZG SRS from %s: %w", path, err)
+	}
+
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	plonkSRS = &srs
+	plonkSRSPath = path
+	log.Info().Str("path", path).Int("size", len(srs.Pk.G1)).Msg("Loaded universal KZG SRS")
+	return nil
+}
+
+// setupPlonkCircuit compiles a circuit to a sparse constraint system and derives
+// its keys from the loaded SRS
+func setupPlonkCircuit(def circuitDef) error {
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, def.circuit())
+	if err != nil {
+		return fmt.Errorf("failed to compile %s circuit: %w", def.name, err)
+	}
+
+	backendMu.RLock()
+	srs, srsPath := plonkSRS, plonkSRSPath
+	backendMu.RUnlock()
+	if srs == nil {
+		return fmt.Errorf("%s circuit uses the plonk backend but no KZG SRS is loaded", def.name)
+	}
+
+	canonical, lagrange, err := srsFor(srs, ccs)
+	if err != nil {
+		return fmt.Errorf("SRS does not fit %s circuit: %w", def.name, err)
+	}
+	pk, vk, err := plonk.Setup(ccs, canonical, lagrange)
+	if err != nil {
+		return fmt.Errorf("plonk setup failed for %s circuit: %w", def.name, err)
+	}
+
+	backendMu.Lock()
+	plonkKeys[def.name] = &plonkKeySet{ccs: ccs, pk: pk, vk: vk}
+	backendMu.Unlock()
+
+	keyMu.Lock()
+	keySets[def.name] = true
+	keyMu.Unlock()
+
+	log.Info().Str("circuit", def.name).Str("srs", srsPath).Int("constraints", ccs.GetNbConstraints()).Msg("Initialized PLONK circuit")
+	return nil
+}
+
+// srsFor trims the universal SRS to the sizes a circuit needs and derives the
+// Lagrange form. ToLagrangeG1 works in place, so it gets a copy.
+func srsFor(srs *kzg_bn254.SRS, ccs constraint.ConstraintSystem) (*kzg_bn254.SRS, *kzg_bn254.SRS, error) {
+	sizeCanonical, sizeLagrange := plonk.SRSSize(ccs)
+	if len(srs.Pk.G1) < sizeCanonical {
+		return nil, nil, fmt.Errorf("need %d G1 points, SRS has %d", sizeCanonical, len(srs.Pk.G1))
+	}
+
+	canonical := &kzg_bn254.SRS{Vk: srs.Vk}
+	canonical.Pk.G1 = srs.Pk.G1[:sizeCanonical]
+
+	points := make([]kzg_bn254.Digest, sizeLagrange)
+	copy(points, srs.Pk.G1[:sizeLagrange])
+	lagrangePoints, err := kzg_bn254.ToLagrangeG1(points)
+	if err != nil {
+		return nil, nil, err
+	}
+	lagrange := &kzg_bn254.SRS{Vk: srs.Vk}
+	lagrange.Pk.G1 = lagrangePoints
+
+	return canonical, lagrange, nil
+}
+
+// Prove generates a proof for the named circuit with its configured backend.
+// When a prover service is installed (see SetProver) the proof is queued there.
+func Prove(circuit string, assignment frontend.Circuit) (Proof, error) {
+	return ProveContext(context.Background(), circuit, assignment)
+}
+
+// ProveContext is Prove, giving up when ctx is done
+func ProveContext(ctx context.Context, circuit string, assignment frontend.Circuit) (Proof, error) {
+	if p := currentProver(); p != nil {
+		return p.Prove(ctx, circuit, assignment)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	w, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	return proveWitness(circuit, w)
+}
+
+// proveWitness proves a full witness inline on the calling goroutine
+func proveWitness(circuit string, w witness.Witness) (Proof, error) {
+	def, err := readyCircuit(circuit)
+	if err != nil {
+		return nil, err
+	}
+
+	if keys := plonkKeysFor(def.name); keys != nil {
+		return plonk.Prove(keys.ccs, keys.pk, w, solidity.WithProverTargetSolidityVerifier(backend.PLONK))
+	}
+	return groth16.Prove(*def.ccs, *def.pk, w)
+}
+
+// Verify checks a proof for the named circuit against the public fields of assignment
+func Verify(circuit string, proof Proof, publicAssignment frontend.Circuit) error {
+	publicWitness, err := frontend.NewWitness(publicAssignment, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	if err != nil {
+		return err
+	}
+	return verifyWitness(circuit, proof, publicWitness)
+}
+
+// verifyWitness checks a proof for the named circuit against a public witness
+func verifyWitness(circuit string, proof Proof, publicWitness witness.Witness) error {
+	def, err := readyCircuit(circuit)
+	if err != nil {
+		return err
+	}
+
+	keys := plonkKeysFor(def.name)
+	switch p := proof.(type) {
+	case *plonk_bn254.Proof:
+		if keys == nil {
+			return fmt.Errorf("%s circuit uses %s, got a plonk proof", circuit, BackendFor(circuit))
+		}
+		return plonk.Verify(p, keys.vk, publicWitness, solidity.WithVerifierTargetSolidityVerifier(backend.PLONK))
+	case *groth16_bn254.Proof:
+		if keys != nil {
+			return fmt.Errorf("%s circuit uses plonk, got a groth16 proof", circuit)
+		}
+		return groth16.Verify(p, *def.vk, publicWitness)
+	default:
+		return fmt.Errorf("unsupported proof type %T", proof)
+	}
+}
+
+// PublicInputs returns a circuit's public inputs in the order its exported
+// verifier expects them
+func PublicInputs(publicAssignment frontend.Circuit) ([]*big.Int, error) {
+	publicWitness, err := frontend.NewWitness(publicAssignment, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	if err != nil {
+		return nil, err
+	}
+	vector, ok := publicWitness.Vector().(fr.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unexpected witness type %T", publicWitness.Vector())
+	}
+	inputs := make([]*big.Int, len(vector))
+	for i := range vector {
+		inputs[i] = vector[i].BigInt(new(big.Int))
+	}
+	return inputs, nil
+}
+
+// newProof returns an empty proof of the circuit's backend to decode into
+func newProof(circuit string) Proof {
+	if BackendFor(circuit) == BackendPlonk {
+		return new(plonk_bn254.Proof)
+	}
+	return new(groth16_bn254.Proof)
+}
+
+// readyCircuit returns the circuit definition, initializing the circuit on first use
+func readyCircuit(circuit string) (circuitDef, error) {
+	def, ok := findCircuit(circuit)
+	if !ok {
+		return circuitDef{}, fmt.Errorf("unknown circuit: %s", circuit)
+	}
+	if circuitReady(def) {
+		return def, nil
+	}
+	if err := Init(); err != nil {
+		return def, err
+	}
+	if !circuitReady(def) {
+		if err := setupCircuit(def); err != nil {
+			return def, err
+		}
+	}
+	return def, nil
+}
+
+func circuitReady(def circuitDef) bool {
+	if BackendFor(def.name) == BackendPlonk {
+		return plonkKeysFor(def.name) != nil
+	}
+	return *def.ccs != nil
+}
+
+func plonkKeysFor(circuit string) *plonkKeySet {
+	if BackendFor(circuit) != BackendPlonk {
+		return nil
+	}
+	backendMu.RLock()
+	defer backendMu.RUnlock()
+	return plonkKeys[circuit]
+}
+
+// SerializeProofBytes encodes a proof the way the backend's exported Solidity
+// verifier expects it: the 256-byte uint256[8] for Groth16, or the proof bytes
+// passed to Verify(bytes, uint256[]) for PLONK
+func SerializeProofBytes(proof Proof) ([]byte, error) {
+	switch p := proof.(type) {
+	case *groth16_bn254.Proof:
+		return p.MarshalSolidity(), nil
+	case *plonk_bn254.Proof:
+		return p.MarshalSolidity(), nil
+	default:
+		return nil, fmt.Errorf("unsupported proof type %T", proof)
+	}
+}
+
+// ExportVerifier writes the Solidity verifier for a circuit's configured backend
+func ExportVerifier(circuit string, w io.Writer) error {
+	def, err := readyCircuit(circuit)
+	if err != nil {
+		return err
+	}
+	if keys := plonkKeysFor(def.name); keys != nil {
+		return keys.vk.ExportSolidity(w)
+	}
+	return (*def.vk).ExportSolidity(w)
+}
+
+// groth16Proof extracts the bn254 Groth16 proof, rejecting PLONK proofs that
+// do not fit the fixed uint256[8] layout
+func groth16Proof(proof Proof) (*groth16_bn254.Proof, error) {
+	switch p := proof.(type) {
+	case *groth16_bn254.Proof:
+		return p, nil
+	case *plonk_bn254.Proof:
+		return nil, fmt.Errorf("plonk proofs do not fit the uint256[8] layout; use SerializeProofBytes")
+	default:
+		return nil, fmt.Errorf("invalid proof type")
+	}
+}
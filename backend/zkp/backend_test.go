@@ -0,0 +1,118 @@
+package zkp
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+)
+
+// usePlonk switches a circuit to PLONK for the duration of a test
+func usePlonk(t *testing.T, circuit string) {
+	if err := SetBackend(circuit, BackendPlonk); err != nil {
+		t.Fatalf("SetBackend failed: %v", err)
+	}
+	t.Cleanup(func() {
+		backendMu.Lock()
+		delete(backends, circuit)
+		delete(plonkKeys, circuit)
+		backendMu.Unlock()
+	})
+}
+
+func writeTestSRS(t *testing.T, size uint64) string {
+	srs, err := kzg_bn254.NewSRS(size, big.NewInt(42))
+	if err != nil {
+		t.Fatalf("Failed to create SRS: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "kzg.srs")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := srs.WriteTo(f); err != nil {
+		t.Fatalf("Failed to write SRS: %v", err)
+	}
+	return path
+}
+
+func TestPlonkBackendSharesOneSRS(t *testing.T) {
+	if err := LoadPlonkSRS(writeTestSRS(t, 1<<13+3)); err != nil {
+		t.Fatalf("LoadPlonkSRS failed: %v", err)
+	}
+	usePlonk(t, "vrf")
+	usePlonk(t, "por")
+
+	proof, err := GenerateVRFProof(big.NewInt(7), big.NewInt(5), big.NewInt(12))
+	if err != nil {
+		t.Fatalf("PLONK proof failed: %v", err)
+	}
+	if err := Verify("vrf", proof, &VRFCircuit{Seed: big.NewInt(5), Randomness: big.NewInt(12)}); err != nil {
+		t.Errorf("Valid PLONK proof rejected: %v", err)
+	}
+	if err := Verify("vrf", proof, &VRFCircuit{Seed: big.NewInt(5), Randomness: big.NewInt(13)}); err == nil {
+		t.Error("PLONK proof accepted for the wrong public inputs")
+	}
+
+	// PLONK proofs have their own encoding rather than the Groth16 uint256[8]
+	if _, err := SerializeProof(proof); err == nil {
+		t.Error("Expected SerializeProof to reject a PLONK proof")
+	}
+	if raw, err := SerializeProofBytes(proof); err != nil || len(raw) == 0 {
+		t.Errorf("SerializeProofBytes failed: %v", err)
+	}
+
+	var sol bytes.Buffer
+	if err := ExportVerifier("vrf", &sol); err != nil {
+		t.Fatalf("ExportVerifier failed: %v", err)
+	}
+	if !strings.Contains(sol.String(), "function Verify(bytes calldata proof") {
+		t.Error("Expected a PLONK Solidity verifier")
+	}
+	solPath := filepath.Join(t.TempDir(), "PlonkVerifier.sol")
+	os.WriteFile(solPath, sol.Bytes(), 0o600)
+	if err := VerifyAgainstSolidity("vrf", solPath); err != nil {
+		t.Errorf("Exported PLONK verifier does not match its key: %v", err)
+	}
+
+	// A second circuit needs no setup of its own, only the same SRS
+	por := &ProofOfReservesCircuit{
+		ReserveCommitment: Commit(big.NewInt(10), big.NewInt(5)), LiabilityCommitment: Commit(big.NewInt(7), big.NewInt(2)), SolvencyProof: 1,
+		ReserveAmount: 10, ReserveBlinding: 5, LiabilityAmount: 7, LiabilityBlinding: 2,
+	}
+	porProof, err := Prove("por", por)
+	if err != nil {
+		t.Fatalf("Reserves circuit could not use the shared SRS: %v", err)
+	}
+	if err := Verify("por", porProof, por); err != nil {
+		t.Errorf("Valid reserves proof rejected: %v", err)
+	}
+}
+
+func TestPlonkRequiresLargeEnoughSRS(t *testing.T) {
+	if err := LoadPlonkSRS(writeTestSRS(t, 4)); err != nil {
+		t.Fatalf("LoadPlonkSRS failed: %v", err)
+	}
+	usePlonk(t, "por")
+
+	def, _ := findCircuit("por")
+	if err := setupCircuit(def); err == nil || !strings.Contains(err.Error(), "SRS does not fit") {
+		t.Errorf("Expected an undersized SRS to be rejected, got %v", err)
+	}
+}
+
+func TestPlonkRejectedForOnChainCircuits(t *testing.T) {
+	for _, circuit := range Groth16OnlyCircuits {
+		if err := SetBackend(circuit, BackendPlonk); err == nil {
+			t.Errorf("Expected plonk to be rejected for %s", circuit)
+		}
+		if BackendFor(circuit) != BackendGroth16 {
+			t.Errorf("Expected %s to stay on groth16", circuit)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package zkp
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	gmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+const (
+	// BridgeBatchDepth bounds a batch to 2^8 messages
+	BridgeBatchDepth = 8
+	// BridgeCommitteeDepth bounds the signing committee to 2^4 nodes
+	BridgeCommitteeDepth = 4
+	// BridgeQuorum is the number of distinct committee signatures a batch root needs
+	BridgeQuorum = 3
+)
+
+// BridgeCommitteeSignature is one committee member's EdDSA signature over a batch root
+type BridgeCommitteeSignature struct {
+	PublicKey eddsa.PublicKey
+	Signature eddsa.Signature
+	Index     frontend.Variable
+	Path      [BridgeCommitteeDepth]frontend.Variable
+}
+
+// BridgeProofCircuit proves a source-chain message is included in a batch
+// whose root was signed by a quorum of the committee. The message fields are
+// public so the target chain can enforce its own replay protection by nonce.
+type BridgeProofCircuit struct {
+	BatchRoot     frontend.Variable `gnark:",public"`
+	CommitteeRoot frontend.Variable `gnark:",public"`
+	SourceChain   frontend.Variable `gnark:",public"`
+	TargetChain   frontend.Variable `gnark:",public"`
+	Nonce         frontend.Variable `gnark:",public"`
+	PayloadHash   frontend.Variable `gnark:",public"`
+
+	MessageIndex frontend.Variable                   `gnark:",secret"`
+	MessagePath  [BridgeBatchDepth]frontend.Variable `gnark:",secret"`
+	Signers      [BridgeQuorum]BridgeCommitteeSignature
+}
+
+func (c *BridgeProofCircuit) Define(api frontend.API) error {
+	leaf, err := mimcHash(api, c.SourceChain, c.TargetChain, c.Nonce, c.PayloadHash)
+	if err != nil {
+		return err
+	}
+	if err := assertMerkleInclusion(api, leaf, c.MessageIndex, c.MessagePath[:], c.BatchRoot); err != nil {
+		return err
+	}
+
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	for i, s := range c.Signers {
+		member, err := mimcHash(api, s.PublicKey.A.X, s.PublicKey.A.Y)
+		if err != nil {
+			return err
+		}
+		if err := assertMerkleInclusion(api, member, s.Index, s.Path[:], c.CommitteeRoot); err != nil {
+			return err
+		}
+
+		h, err := gmimc.NewMiMC(api)
+		if err != nil {
+			return err
+		}
+		if err := eddsa.Verify(curve, s.Signature, c.BatchRoot, s.PublicKey, &h); err != nil {
+			return err
+		}
+
+		// Strictly increasing indices keep one member from counting twice
+		if i > 0 {
+			api.AssertIsLessOrEqual(api.Add(c.Signers[i-1].Index, 1), s.Index)
+		}
+	}
+	return nil
+}
+
+func mimcHash(api frontend.API, inputs ...frontend.Variable) (frontend.Variable, error) {
+	h, err := gmimc.NewMiMC(api)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(inputs...)
+	return h.Sum(), nil
+}
+
+// assertMerkleInclusion recomputes the root from a leaf and its sibling path;
+// bit i of index selects whether the node at level i is a right child
+func assertMerkleInclusion(api frontend.API, leaf, index frontend.Variable, path []frontend.Variable, root frontend.Variable) error {
+	bits := api.ToBinary(index, len(path))
+	node := leaf
+	for i, sibling := range path {
+		left := api.Select(bits[i], sibling, node)
+		right := api.Select(bits[i], node, sibling)
+		var err error
+		if node, err = mimcHash(api, left, right); err != nil {
+			return err
+		}
+	}
+	api.AssertIsEqual(node, root)
+	return nil
+}
+
+// MiMCHash hashes field elements with the same MiMC construction the circuits use
+func MiMCHash(inputs ...*big.Int) *big.Int {
+	h := mimc.NewMiMC()
+	for _, in := range inputs {
+		var e fr.Element
+		e.SetBigInt(in)
+		b := e.Bytes()
+		h.Write(b[:])
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// BridgeLeaf is the batch tree leaf committing to a message
+func BridgeLeaf(sourceChain, targetChain, nonce uint64, payloadHash *big.Int) *big.Int {
+	return MiMCHash(new(big.Int).SetUint64(sourceChain), new(big.Int).SetUint64(targetChain), new(big.Int).SetUint64(nonce), payloadHash)
+}
+
+// FieldElement reduces arbitrary bytes (such as a keccak digest) into the scalar field
+func FieldElement(b []byte) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	return v.Mod(v, ecc.BN254.ScalarField())
+}
+
+// MerkleTree is a fixed-depth MiMC Merkle tree padded with zero leaves
+type MerkleTree struct {
+	levels [][]*big.Int // levels[0] are the leaves, the last level is the root
+}
+
+// NewMerkleTree builds a tree of the given depth over leaves
+func NewMerkleTree(leaves []*big.Int, depth int) (*MerkleTree, error) {
+	size := 1 << depth
+	if len(leaves) > size {
+		return nil, fmt.Errorf("%d leaves do not fit a tree of depth %d", len(leaves), depth)
+	}
+	level := make([]*big.Int, size)
+	for i := range level {
+		if i < len(leaves) {
+			level[i] = leaves[i]
+		} else {
+			level[i] = big.NewInt(0)
+		}
+	}
+
+	t := &MerkleTree{levels: [][]*big.Int{level}}
+	for len(level) > 1 {
+		next := make([]*big.Int, len(level)/2)
+		for i := range next {
+			next[i] = MiMCHash(level[2*i], level[2*i+1])
+		}
+		t.levels = append(t.levels, next)
+		level = next
+	}
+	return t, nil
+}
+
+// Root returns the tree root
+func (t *MerkleTree) Root() *big.Int {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Path returns the sibling of each node on the way from leaf index to the root
+func (t *MerkleTree) Path(index int) []*big.Int {
+	path := make([]*big.Int, 0, len(t.levels)-1)
+	for _, level := range t.levels[:len(t.levels)-1] {
+		path = append(path, level[index^1])
+		index >>= 1
+	}
+	return path
+}
+
+// BridgeSignature is a committee member's signature over a batch root, with
+// the member's position in the committee tree
+type BridgeSignature struct {
+	PublicKey []byte // compressed BabyJubjub point (gnark-crypto eddsa encoding)
+	Signature []byte
+	Index     int
+	Path      []*big.Int
+}
+
+// BridgeWitness holds everything needed to prove one message of a batch
+type BridgeWitness struct {
+	BatchRoot     *big.Int
+	CommitteeRoot *big.Int
+	SourceChain   uint64
+	TargetChain   uint64
+	Nonce         uint64
+	PayloadHash   *big.Int
+	MessageIndex  int
+	MessagePath   []*big.Int
+	Signatures    []BridgeSignature // BridgeQuorum entries in increasing Index order
+}
+
+func (w BridgeWitness) assignment() (*BridgeProofCircuit, error) {
+	a := &BridgeProofCircuit{
+		BatchRoot:     w.BatchRoot,
+		CommitteeRoot: w.CommitteeRoot,
+		SourceChain:   w.SourceChain,
+		TargetChain:   w.TargetChain,
+		Nonce:         w.Nonce,
+		PayloadHash:   w.PayloadHash,
+		MessageIndex:  w.MessageIndex,
+	}
+	if len(w.MessagePath) != BridgeBatchDepth {
+		return nil, fmt.Errorf("message path has %d levels, want %d", len(w.MessagePath), BridgeBatchDepth)
+	}
+	for i, p := range w.MessagePath {
+		a.MessagePath[i] = p
+	}
+
+	if len(w.Signatures) != BridgeQuorum {
+		return nil, fmt.Errorf("need %d committee signatures, got %d", BridgeQuorum, len(w.Signatures))
+	}
+	for i, s := range w.Signatures {
+		if len(s.Path) != BridgeCommitteeDepth {
+			return nil, fmt.Errorf("committee path has %d levels, want %d", len(s.Path), BridgeCommitteeDepth)
+		}
+		signer := &a.Signers[i]
+		signer.PublicKey.Assign(tedwards.BN254, s.PublicKey)
+		signer.Signature.Assign(tedwards.BN254, s.Signature)
+		signer.Index = s.Index
+		for j, p := range s.Path {
+			signer.Path[j] = p
+		}
+	}
+	return a, nil
+}
+
+// GenerateBridgeProof creates a ZK proof that a message belongs to a batch
+// signed by a quorum of the committee
+func GenerateBridgeProof(w BridgeWitness) (Proof, error) {
+	assignment, err := w.assignment()
+	if err != nil {
+		return nil, err
+	}
+	return Prove("bridge", assignment)
+}
+
+// VerifyBridgeProof checks a bridge proof against the message and roots in w;
+// the private fields of w are ignored
+func VerifyBridgeProof(proof Proof, w BridgeWitness) error {
+	return Verify("bridge", proof, &BridgeProofCircuit{
+		BatchRoot:     w.BatchRoot,
+		CommitteeRoot: w.CommitteeRoot,
+		SourceChain:   w.SourceChain,
+		TargetChain:   w.TargetChain,
+		Nonce:         w.Nonce,
+		PayloadHash:   w.PayloadHash,
+	})
+}
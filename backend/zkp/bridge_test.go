@@ -0,0 +1,91 @@
+package zkp
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/consensys/gnark-crypto/hash"
+)
+
+// signBatch builds a committee tree and signs root with the members at indices
+func signBatch(t *testing.T, members []*eddsa.PrivateKey, root *big.Int, indices ...int) (*big.Int, []BridgeSignature) {
+	leaves := make([]*big.Int, len(members))
+	for i, m := range members {
+		leaves[i] = MiMCHash(m.PublicKey.A.X.BigInt(new(big.Int)), m.PublicKey.A.Y.BigInt(new(big.Int)))
+	}
+	tree, err := NewMerkleTree(leaves, BridgeCommitteeDepth)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := make([]byte, 32)
+	root.FillBytes(msg)
+	var sigs []BridgeSignature
+	for _, i := range indices {
+		sig, err := members[i].Sign(msg, hash.MIMC_BN254.New())
+		if err != nil {
+			t.Fatalf("Signing failed: %v", err)
+		}
+		sigs = append(sigs, BridgeSignature{
+			PublicKey: members[i].PublicKey.Bytes(),
+			Signature: sig,
+			Index:     i,
+			Path:      tree.Path(i),
+		})
+	}
+	return tree.Root(), sigs
+}
+
+func TestBridgeProofGeneration(t *testing.T) {
+	members := make([]*eddsa.PrivateKey, 4)
+	for i := range members {
+		members[i], _ = eddsa.GenerateKey(rand.Reader)
+	}
+
+	payloadHash := FieldElement([]byte("transfer 100 USDC to 0xabc"))
+	var leaves []*big.Int
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		leaves = append(leaves, BridgeLeaf(1, 42161, nonce, payloadHash))
+	}
+	batch, err := NewMerkleTree(leaves, BridgeBatchDepth)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	committeeRoot, sigs := signBatch(t, members, batch.Root(), 0, 2, 3)
+	w := BridgeWitness{
+		BatchRoot:     batch.Root(),
+		CommitteeRoot: committeeRoot,
+		SourceChain:   1,
+		TargetChain:   42161,
+		Nonce:         2,
+		PayloadHash:   payloadHash,
+		MessageIndex:  2,
+		MessagePath:   batch.Path(2),
+		Signatures:    sigs,
+	}
+
+	proof, err := GenerateBridgeProof(w)
+	if err != nil {
+		t.Fatalf("Failed to generate bridge proof: %v", err)
+	}
+	if err := VerifyBridgeProof(proof, w); err != nil {
+		t.Errorf("Valid bridge proof rejected: %v", err)
+	}
+
+	// The nonce is public, so a proof cannot be replayed for another message
+	replayed := w
+	replayed.Nonce = 1
+	if err := VerifyBridgeProof(proof, replayed); err == nil {
+		t.Error("Bridge proof accepted for a different nonce")
+	}
+
+	// The same member cannot fill the quorum twice
+	_, dup := signBatch(t, members, batch.Root(), 0, 0, 3)
+	w.Signatures = dup
+	if _, err := GenerateBridgeProof(w); err == nil {
+		t.Error("Expected proof with a repeated signer to fail")
+	}
+}
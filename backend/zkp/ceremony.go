@@ -0,0 +1,535 @@
+package zkp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/groth16/bn254/mpcsetup"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+)
+
+// Ceremony phases
+const (
+	CeremonyPhase1    = "phase1"    // Circuit-independent powers of tau
+	CeremonyPhase2    = "phase2"    // Per-circuit contributions
+	CeremonyFinalized = "finalized" // Keys sealed and written
+)
+
+const ceremonyManifest = "ceremony.json"
+
+// minCeremonyDomain is the smallest domain the powers-of-tau update proofs support
+const minCeremonyDomain = 2
+
+// ContributionRecord identifies one contribution in the transcript
+type ContributionRecord struct {
+	Index       int       `json:"index"`
+	Contributor string    `json:"contributor"`
+	File        string    `json:"file"`
+	Hash        string    `json:"hash"`
+	At          time.Time `json:"at"`
+}
+
+// CeremonyCircuit tracks the phase-2 transcript of one circuit
+type CeremonyCircuit struct {
+	Name          string               `json:"name"`
+	CircuitHash   string               `json:"circuit_hash"`
+	DomainSize    uint64               `json:"domain_size"`
+	Initial       string               `json:"initial_hash,omitempty"`
+	Contributions []ContributionRecord `json:"contributions"`
+	VKHash        string               `json:"vk_hash,omitempty"`
+}
+
+// CeremonyManifest is the public record of a ceremony. Together with the
+// contribution files it lets anyone re-verify every step offline.
+type CeremonyManifest struct {
+	Version      int                  `json:"version"`
+	Phase        string               `json:"phase"`
+	DomainSize   uint64               `json:"domain_size"`
+	Phase1       []ContributionRecord `json:"phase1"`
+	Phase1Beacon string               `json:"phase1_beacon,omitempty"`
+	SRSHash      string               `json:"srs_hash,omitempty"`
+	Circuits     []CeremonyCircuit    `json:"circuits"`
+	Phase2Beacon string               `json:"phase2_beacon,omitempty"`
+}
+
+// Ceremony is a multi-party Groth16 setup stored as a directory of contributions.
+// Each contributor runs Contribute offline on a copy of the directory and
+// passes it on; the randomness they add never touches disk.
+type Ceremony struct {
+	dir      string
+	manifest CeremonyManifest
+}
+
+// InitCeremony starts a ceremony for the named circuits (all circuits when empty)
+func InitCeremony(dir string, circuits []string) (*Ceremony, error) {
+	if _, err := os.Stat(filepath.Join(dir, ceremonyManifest)); err == nil {
+		return nil, fmt.Errorf("a ceremony already exists in %s", dir)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, CeremonyPhase1), 0o755); err != nil {
+		return nil, err
+	}
+
+	defs, err := ceremonyCircuits(circuits)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Ceremony{dir: dir, manifest: CeremonyManifest{Version: KeyFormatVersion, Phase: CeremonyPhase1}}
+	for _, def := range defs {
+		ccs, err := compileR1CS(def)
+		if err != nil {
+			return nil, err
+		}
+		circuitHash, err := hashOf(ccs)
+		if err != nil {
+			return nil, err
+		}
+
+		// Phase 1 must be large enough for the biggest circuit; smaller ones use a prefix
+		size := ecc.NextPowerOfTwo(uint64(ccs.GetNbConstraints()))
+		if size < minCeremonyDomain {
+			size = minCeremonyDomain
+		}
+		if size > c.manifest.DomainSize {
+			c.manifest.DomainSize = size
+		}
+		c.manifest.Circuits = append(c.manifest.Circuits, CeremonyCircuit{
+			Name:        def.name,
+			CircuitHash: circuitHash,
+			DomainSize:  size,
+		})
+	}
+
+	return c, c.save()
+}
+
+// OpenCeremony loads an existing ceremony directory
+func OpenCeremony(dir string) (*Ceremony, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, ceremonyManifest))
+	if err != nil {
+		return nil, err
+	}
+	c := &Ceremony{dir: dir}
+	if err := json.Unmarshal(raw, &c.manifest); err != nil {
+		return nil, fmt.Errorf("invalid ceremony manifest: %w", err)
+	}
+	if c.manifest.Version != KeyFormatVersion {
+		return nil, fmt.Errorf("ceremony uses format version %d, expected %d", c.manifest.Version, KeyFormatVersion)
+	}
+	return c, nil
+}
+
+// Manifest returns the ceremony's public record
+func (c *Ceremony) Manifest() CeremonyManifest {
+	return c.manifest
+}
+
+// Contribute adds fresh randomness to the current phase. In phase 2 the
+// contributor covers every circuit in one run.
+func (c *Ceremony) Contribute(contributor string) ([]ContributionRecord, error) {
+	switch c.manifest.Phase {
+	case CeremonyPhase1:
+		p1, err := c.latestPhase1()
+		if err != nil {
+			return nil, err
+		}
+		p1.Contribute()
+
+		record, err := c.writeContribution(CeremonyPhase1, len(c.manifest.Phase1)+1, contributor, p1)
+		if err != nil {
+			return nil, err
+		}
+		c.manifest.Phase1 = append(c.manifest.Phase1, record)
+		return []ContributionRecord{record}, c.save()
+
+	case CeremonyPhase2:
+		var records []ContributionRecord
+		for i := range c.manifest.Circuits {
+			circuit := &c.manifest.Circuits[i]
+			p2, err := c.latestPhase2(circuit)
+			if err != nil {
+				return nil, err
+			}
+			p2.Contribute()
+
+			record, err := c.writeContribution(filepath.Join(CeremonyPhase2, circuit.Name), len(circuit.Contributions)+1, contributor, p2)
+			if err != nil {
+				return nil, err
+			}
+			circuit.Contributions = append(circuit.Contributions, record)
+			records = append(records, record)
+		}
+		return records, c.save()
+	}
+	return nil, fmt.Errorf("ceremony is %s; no further contributions accepted", c.manifest.Phase)
+}
+
+// SealPhase1 verifies the powers-of-tau transcript, applies the public random
+// beacon and prepares the phase-2 starting point of every circuit
+func (c *Ceremony) SealPhase1(beacon []byte) error {
+	if c.manifest.Phase != CeremonyPhase1 {
+		return fmt.Errorf("ceremony is %s, not %s", c.manifest.Phase, CeremonyPhase1)
+	}
+	if len(c.manifest.Phase1) == 0 {
+		return fmt.Errorf("phase 1 has no contributions")
+	}
+
+	c.manifest.Phase1Beacon = hex.EncodeToString(beacon)
+	commons, err := c.verifyPhase1()
+	if err != nil {
+		return err
+	}
+
+	srsHash, err := c.writeFile("srs.bin", &commons)
+	if err != nil {
+		return err
+	}
+	c.manifest.SRSHash = srsHash
+
+	for i := range c.manifest.Circuits {
+		circuit := &c.manifest.Circuits[i]
+		initial, _, err := initialPhase2(circuit, &commons)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Join(c.dir, CeremonyPhase2, circuit.Name), 0o755); err != nil {
+			return err
+		}
+		circuit.Initial, err = c.writeFile(filepath.Join(CeremonyPhase2, circuit.Name, "0000.bin"), initial)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.manifest.Phase = CeremonyPhase2
+	return c.save()
+}
+
+// Verify re-checks the whole transcript: file hashes, every contribution's
+// proof of update, both beacons and, once finalized, the resulting keys
+func (c *Ceremony) Verify() error {
+	if len(c.manifest.Phase1) == 0 {
+		return fmt.Errorf("phase 1 has no contributions")
+	}
+	for _, record := range c.manifest.Phase1 {
+		if err := c.checkHash(record.File, record.Hash); err != nil {
+			return err
+		}
+	}
+	if c.manifest.Phase == CeremonyPhase1 {
+		_, err := c.verifyPhase1Chain()
+		return err
+	}
+
+	commons, err := c.verifyPhase1()
+	if err != nil {
+		return err
+	}
+	srsHash, err := hashOf(&commons)
+	if err != nil {
+		return err
+	}
+	if srsHash != c.manifest.SRSHash {
+		return fmt.Errorf("sealed SRS does not match the recorded transcript")
+	}
+
+	for i := range c.manifest.Circuits {
+		circuit := c.manifest.Circuits[i]
+		pk, vk, err := c.verifyPhase2(&circuit, &commons)
+		if err != nil {
+			return err
+		}
+		if c.manifest.Phase == CeremonyFinalized && pk != nil {
+			vkHash, err := hashOf(vk)
+			if err != nil {
+				return err
+			}
+			if vkHash != circuit.VKHash {
+				return fmt.Errorf("%s: sealed verifying key does not match the recorded one", circuit.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Finalize verifies every phase-2 transcript, applies the closing beacon and
+// writes the resulting keys to keyDir for the node to load
+func (c *Ceremony) Finalize(beacon []byte, keyDir string) ([]KeyMetadata, error) {
+	if c.manifest.Phase != CeremonyPhase2 {
+		return nil, fmt.Errorf("ceremony is %s, not %s", c.manifest.Phase, CeremonyPhase2)
+	}
+
+	commons, err := c.loadSRS()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(keyDir, 0o700); err != nil {
+		return nil, err
+	}
+
+	c.manifest.Phase2Beacon = hex.EncodeToString(beacon)
+	var generated []KeyMetadata
+	for i := range c.manifest.Circuits {
+		circuit := &c.manifest.Circuits[i]
+		if len(circuit.Contributions) == 0 {
+			return generated, fmt.Errorf("%s: phase 2 has no contributions", circuit.Name)
+		}
+
+		pk, vk, err := c.verifyPhase2(circuit, &commons)
+		if err != nil {
+			return generated, err
+		}
+		def, _ := findCircuit(circuit.Name)
+		ccs, err := compileR1CS(def)
+		if err != nil {
+			return generated, err
+		}
+
+		meta, err := saveKeys(keyDir, KeyMetadata{
+			Circuit:       circuit.Name,
+			Source:        "ceremony",
+			Contributions: len(circuit.Contributions),
+		}, ccs, pk, vk)
+		if err != nil {
+			return generated, err
+		}
+		circuit.VKHash = meta.VKHash
+		generated = append(generated, meta)
+	}
+
+	c.manifest.Phase = CeremonyFinalized
+	return generated, c.save()
+}
+
+// verifyPhase1Chain checks each powers-of-tau contribution against its predecessor
+func (c *Ceremony) verifyPhase1Chain() (*mpcsetup.Phase1, error) {
+	prev := mpcsetup.NewPhase1(c.manifest.DomainSize)
+	for _, record := range c.manifest.Phase1 {
+		next := new(mpcsetup.Phase1)
+		if err := c.readFile(record.File, next); err != nil {
+			return nil, err
+		}
+		if err := prev.Verify(next); err != nil {
+			return nil, fmt.Errorf("phase 1 contribution %d (%s) is invalid: %w", record.Index, record.Contributor, err)
+		}
+		prev = next
+	}
+	return prev, nil
+}
+
+// verifyPhase1 verifies the chain and seals it with the recorded beacon
+func (c *Ceremony) verifyPhase1() (mpcsetup.SrsCommons, error) {
+	last, err := c.verifyPhase1Chain()
+	if err != nil {
+		return mpcsetup.SrsCommons{}, err
+	}
+	beacon, err := hex.DecodeString(c.manifest.Phase1Beacon)
+	if err != nil {
+		return mpcsetup.SrsCommons{}, fmt.Errorf("invalid phase 1 beacon: %w", err)
+	}
+	return last.Seal(beacon), nil
+}
+
+// verifyPhase2 replays a circuit's transcript from the recomputed starting point.
+// Keys are only sealed once the phase-2 beacon is known.
+func (c *Ceremony) verifyPhase2(circuit *CeremonyCircuit, commons *mpcsetup.SrsCommons) (groth16.ProvingKey, groth16.VerifyingKey, error) {
+	prev, evals, err := initialPhase2(circuit, commons)
+	if err != nil {
+		return nil, nil, err
+	}
+	initialHash, err := hashOf(prev)
+	if err != nil {
+		return nil, nil, err
+	}
+	if initialHash != circuit.Initial {
+		return nil, nil, fmt.Errorf("%s: phase 2 starting point does not match the sealed SRS", circuit.Name)
+	}
+
+	for _, record := range circuit.Contributions {
+		if err := c.checkHash(record.File, record.Hash); err != nil {
+			return nil, nil, err
+		}
+		next := new(mpcsetup.Phase2)
+		if err := c.readFile(record.File, next); err != nil {
+			return nil, nil, err
+		}
+		if err := prev.Verify(next); err != nil {
+			return nil, nil, fmt.Errorf("%s: phase 2 contribution %d (%s) is invalid: %w", circuit.Name, record.Index, record.Contributor, err)
+		}
+		prev = next
+	}
+
+	if c.manifest.Phase2Beacon == "" {
+		return nil, nil, nil
+	}
+	beacon, err := hex.DecodeString(c.manifest.Phase2Beacon)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid phase 2 beacon: %w", err)
+	}
+	pk, vk := prev.Seal(truncateSRS(commons, circuit.DomainSize), &evals, beacon)
+	return pk, vk, nil
+}
+
+func (c *Ceremony) latestPhase1() (*mpcsetup.Phase1, error) {
+	if len(c.manifest.Phase1) == 0 {
+		return mpcsetup.NewPhase1(c.manifest.DomainSize), nil
+	}
+	last := c.manifest.Phase1[len(c.manifest.Phase1)-1]
+	if err := c.checkHash(last.File, last.Hash); err != nil {
+		return nil, err
+	}
+	p1 := new(mpcsetup.Phase1)
+	return p1, c.readFile(last.File, p1)
+}
+
+func (c *Ceremony) latestPhase2(circuit *CeremonyCircuit) (*mpcsetup.Phase2, error) {
+	file, hash := filepath.Join(CeremonyPhase2, circuit.Name, "0000.bin"), circuit.Initial
+	if n := len(circuit.Contributions); n > 0 {
+		file, hash = circuit.Contributions[n-1].File, circuit.Contributions[n-1].Hash
+	}
+	if err := c.checkHash(file, hash); err != nil {
+		return nil, err
+	}
+	p2 := new(mpcsetup.Phase2)
+	return p2, c.readFile(file, p2)
+}
+
+func (c *Ceremony) loadSRS() (mpcsetup.SrsCommons, error) {
+	var commons mpcsetup.SrsCommons
+	if err := c.checkHash("srs.bin", c.manifest.SRSHash); err != nil {
+		return commons, err
+	}
+	return commons, c.readFile("srs.bin", &commons)
+}
+
+func (c *Ceremony) writeContribution(subdir string, index int, contributor string, obj io.WriterTo) (ContributionRecord, error) {
+	file := filepath.Join(subdir, fmt.Sprintf("%04d.bin", index))
+	hash, err := c.writeFile(file, obj)
+	if err != nil {
+		return ContributionRecord{}, err
+	}
+	return ContributionRecord{
+		Index:       index,
+		Contributor: contributor,
+		File:        file,
+		Hash:        hash,
+		At:          time.Now().UTC(),
+	}, nil
+}
+
+func (c *Ceremony) writeFile(name string, obj io.WriterTo) (string, error) {
+	var buf bytes.Buffer
+	if _, err := obj.WriteTo(&buf); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(c.dir, name), buf.Bytes(), 0o644); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (c *Ceremony) readFile(name string, obj io.ReaderFrom) error {
+	f, err := os.Open(filepath.Join(c.dir, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := obj.ReadFrom(f); err != nil {
+		return fmt.Errorf("malformed transcript file %s: %w", name, err)
+	}
+	return nil
+}
+
+func (c *Ceremony) checkHash(name, want string) error {
+	raw, err := os.ReadFile(filepath.Join(c.dir, name))
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(raw)
+	if hex.EncodeToString(sum[:]) != want {
+		return fmt.Errorf("transcript file %s does not match its recorded hash", name)
+	}
+	return nil
+}
+
+func (c *Ceremony) save() error {
+	raw, err := json.MarshalIndent(c.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.dir, ceremonyManifest), raw, 0o644)
+}
+
+// initialPhase2 deterministically derives a circuit's phase-2 starting point
+// from the sealed SRS, so verifiers can recompute it
+func initialPhase2(circuit *CeremonyCircuit, commons *mpcsetup.SrsCommons) (*mpcsetup.Phase2, mpcsetup.Phase2Evaluations, error) {
+	def, ok := findCircuit(circuit.Name)
+	if !ok {
+		return nil, mpcsetup.Phase2Evaluations{}, fmt.Errorf("unknown circuit: %s", circuit.Name)
+	}
+	ccs, err := compileR1CS(def)
+	if err != nil {
+		return nil, mpcsetup.Phase2Evaluations{}, err
+	}
+	circuitHash, err := hashOf(ccs)
+	if err != nil {
+		return nil, mpcsetup.Phase2Evaluations{}, err
+	}
+	if circuitHash != circuit.CircuitHash {
+		return nil, mpcsetup.Phase2Evaluations{}, fmt.Errorf("%s circuit changed since the ceremony started", circuit.Name)
+	}
+
+	p2 := new(mpcsetup.Phase2)
+	evals := p2.Initialize(ccs, truncateSRS(commons, circuit.DomainSize))
+	return p2, evals, nil
+}
+
+// truncateSRS takes the prefix of the powers of tau needed for a domain of size n
+func truncateSRS(commons *mpcsetup.SrsCommons, n uint64) *mpcsetup.SrsCommons {
+	if uint64(len(commons.G1.AlphaTau)) == n {
+		return commons
+	}
+	var t mpcsetup.SrsCommons
+	t.G1.Tau = commons.G1.Tau[:2*n-1]
+	t.G1.AlphaTau = commons.G1.AlphaTau[:n]
+	t.G1.BetaTau = commons.G1.BetaTau[:n]
+	t.G2.Tau = commons.G2.Tau[:n]
+	t.G2.Beta = commons.G2.Beta
+	return &t
+}
+
+// ceremonyCircuits resolves the circuits of a ceremony; all of them by default
+func ceremonyCircuits(names []string) ([]circuitDef, error) {
+	if len(names) == 0 {
+		return allCircuits(), nil
+	}
+	var defs []circuitDef
+	for _, name := range names {
+		def, ok := findCircuit(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown circuit: %s", name)
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+func compileR1CS(def circuitDef) (*cs.R1CS, error) {
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, def.circuit())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile %s circuit: %w", def.name, err)
+	}
+	return ccs.(*cs.R1CS), nil
+}
@@ -0,0 +1,99 @@
+package zkp
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+)
+
+func TestCeremonyProducesWorkingKeys(t *testing.T) {
+	dir := t.TempDir()
+	keyDir := filepath.Join(dir, "keys")
+
+	// VRF has the smaller domain, so it exercises the truncated SRS path
+	c, err := InitCeremony(filepath.Join(dir, "ceremony"), []string{"vrf", "por"})
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	for _, who := range []string{"alice", "bob"} {
+		if _, err := c.Contribute(who); err != nil {
+			t.Fatalf("Phase 1 contribution by %s failed: %v", who, err)
+		}
+	}
+	if err := c.SealPhase1([]byte("block 19000000")); err != nil {
+		t.Fatalf("Seal phase 1 failed: %v", err)
+	}
+
+	// Contributors work offline on the directory, so reopen between runs
+	for _, who := range []string{"carol", "dave"} {
+		reopened, err := OpenCeremony(filepath.Join(dir, "ceremony"))
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		if _, err := reopened.Contribute(who); err != nil {
+			t.Fatalf("Phase 2 contribution by %s failed: %v", who, err)
+		}
+		c = reopened
+	}
+
+	if err := c.Verify(); err != nil {
+		t.Fatalf("Transcript should verify before finalizing: %v", err)
+	}
+
+	generated, err := c.Finalize([]byte("block 19000100"), keyDir)
+	if err != nil {
+		t.Fatalf("Finalize failed: %v", err)
+	}
+	if len(generated) != 2 || generated[0].Source != "ceremony" || generated[0].Contributions != 2 {
+		t.Fatalf("Unexpected key metadata: %+v", generated)
+	}
+
+	final, _ := OpenCeremony(filepath.Join(dir, "ceremony"))
+	if err := final.Verify(); err != nil {
+		t.Fatalf("Finalized transcript should verify: %v", err)
+	}
+
+	// The ceremony keys must prove and verify like a regular setup
+	def, _ := findCircuit("vrf")
+	ccs, _ := compileR1CS(def)
+	pk, vk, err := loadKeys(keyDir, "vrf", ccs)
+	if err != nil {
+		t.Fatalf("Failed to load ceremony keys: %v", err)
+	}
+	witness, _ := frontend.NewWitness(&VRFCircuit{SecretKey: big.NewInt(7), Seed: big.NewInt(42), Randomness: big.NewInt(49)}, ecc.BN254.ScalarField())
+	proof, err := groth16.Prove(ccs, pk, witness)
+	if err != nil {
+		t.Fatalf("Prove with ceremony keys failed: %v", err)
+	}
+	public, _ := witness.Public()
+	if err := groth16.Verify(proof, vk, public); err != nil {
+		t.Errorf("Proof from ceremony keys did not verify: %v", err)
+	}
+}
+
+func TestCeremonyDetectsTamperedTranscript(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := InitCeremony(dir, []string{"vrf"})
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	c.Contribute("alice")
+	c.Contribute("bob")
+
+	// Replace bob's contribution with a copy of alice's
+	first := filepath.Join(dir, c.Manifest().Phase1[0].File)
+	second := filepath.Join(dir, c.Manifest().Phase1[1].File)
+	raw, _ := os.ReadFile(first)
+	os.WriteFile(second, raw, 0o644)
+
+	if err := c.Verify(); err == nil {
+		t.Error("Expected tampered transcript to fail verification")
+	}
+}
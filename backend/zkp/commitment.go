@@ -0,0 +1,179 @@
+package zkp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	edwards "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+)
+
+// Commit is the MiMC commitment to a value under a blinding factor, matching
+// the commitments checked by the reserves and selective-disclosure circuits
+func Commit(value, blinding *big.Int) *big.Int {
+	return MiMCHash(value, blinding)
+}
+
+// RandomFieldElement returns a uniformly random blinding factor
+func RandomFieldElement() (*big.Int, error) {
+	var e fr.Element
+	if _, err := e.SetRandom(); err != nil {
+		return nil, err
+	}
+	return e.BigInt(new(big.Int)), nil
+}
+
+// DisclosureKey is a BabyJubjub key pair that disclosed values are encrypted to
+type DisclosureKey struct {
+	Secret *big.Int
+	Public edwards.PointAffine
+}
+
+// NewDisclosureKey generates a key pair for receiving disclosed values
+func NewDisclosureKey() (*DisclosureKey, error) {
+	secret, err := randomScalar()
+	if err != nil {
+		return nil, err
+	}
+	k := &DisclosureKey{Secret: secret}
+	base := edwards.GetEdwardsCurve().Base
+	k.Public.ScalarMultiplication(&base, secret)
+	return k, nil
+}
+
+// Decrypt recovers a value encrypted with EncryptToKey
+func (k *DisclosureKey) Decrypt(ciphertext *big.Int, ephemeral edwards.PointAffine) *big.Int {
+	var shared edwards.PointAffine
+	shared.ScalarMultiplication(&ephemeral, k.Secret)
+	v := new(big.Int).Sub(ciphertext, disclosurePad(shared))
+	return v.Mod(v, fr.Modulus())
+}
+
+// EncryptToKey masks value for the holder of recipient's secret key, using
+// the ephemeral secret r; it returns the ciphertext and R = r*G
+func EncryptToKey(value *big.Int, recipient edwards.PointAffine, r *big.Int) (*big.Int, edwards.PointAffine) {
+	base := edwards.GetEdwardsCurve().Base
+	var ephemeral, shared edwards.PointAffine
+	ephemeral.ScalarMultiplication(&base, r)
+	shared.ScalarMultiplication(&recipient, r)
+
+	c := new(big.Int).Add(value, disclosurePad(shared))
+	return c.Mod(c, fr.Modulus()), ephemeral
+}
+
+// EncodeDisclosureKey returns the 0x-prefixed compressed form of a public key
+func EncodeDisclosureKey(pub edwards.PointAffine) string {
+	b := pub.Bytes()
+	return "0x" + hex.EncodeToString(b[:])
+}
+
+// DecodeDisclosureKey parses a key produced by EncodeDisclosureKey
+func DecodeDisclosureKey(s string) (edwards.PointAffine, error) {
+	var pub edwards.PointAffine
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return pub, fmt.Errorf("invalid disclosure key: %w", err)
+	}
+	if len(b) != 32 {
+		return pub, fmt.Errorf("disclosure key must be 32 bytes, got %d", len(b))
+	}
+	if _, err := pub.SetBytes(b); err != nil {
+		return pub, fmt.Errorf("invalid disclosure key: %w", err)
+	}
+	return pub, nil
+}
+
+func disclosurePad(shared edwards.PointAffine) *big.Int {
+	return MiMCHash(shared.X.BigInt(new(big.Int)), shared.Y.BigInt(new(big.Int)))
+}
+
+func randomScalar() (*big.Int, error) {
+	order := edwards.GetEdwardsCurve().Order
+	for {
+		r, err := rand.Int(rand.Reader, &order)
+		if err != nil {
+			return nil, err
+		}
+		if r.Sign() > 0 {
+			return r, nil
+		}
+	}
+}
+
+// SelectiveDisclosure holds the public inputs of a selective-disclosure proof
+type SelectiveDisclosure struct {
+	DataCommitment *big.Int
+	Recipient      edwards.PointAffine
+	Ephemeral      edwards.PointAffine
+	Ciphertext     *big.Int
+	RangeMin       *big.Int
+	RangeMax       *big.Int
+}
+
+func (d SelectiveDisclosure) publicAssignment() *SelectiveDisclosureCircuit {
+	return &SelectiveDisclosureCircuit{
+		DataCommitment:   d.DataCommitment,
+		AuthorizedPubKey: pointVar(d.Recipient),
+		EphemeralKey:     pointVar(d.Ephemeral),
+		EncryptedData:    d.Ciphertext,
+		RangeMin:         d.RangeMin,
+		RangeMax:         d.RangeMax,
+	}
+}
+
+// GenerateSelectiveDisclosureProof proves value lies in [min, max] and
+// encrypts it to recipient under a fresh ephemeral key
+func GenerateSelectiveDisclosureProof(value, blinding, min, max *big.Int, recipient edwards.PointAffine) (Proof, SelectiveDisclosure, error) {
+	assignment, d, err := disclosureAssignment(value, blinding, min, max, recipient)
+	if err != nil {
+		return nil, SelectiveDisclosure{}, err
+	}
+	proof, err := Prove("selective_disclosure", assignment)
+	if err != nil {
+		return nil, SelectiveDisclosure{}, err
+	}
+	return proof, d, nil
+}
+
+func disclosureAssignment(value, blinding, min, max *big.Int, recipient edwards.PointAffine) (*SelectiveDisclosureCircuit, SelectiveDisclosure, error) {
+	if !recipient.IsOnCurve() {
+		return nil, SelectiveDisclosure{}, fmt.Errorf("recipient key is not on the BabyJubjub curve")
+	}
+	r, err := randomScalar()
+	if err != nil {
+		return nil, SelectiveDisclosure{}, err
+	}
+	ciphertext, ephemeral := EncryptToKey(value, recipient, r)
+
+	d := SelectiveDisclosure{
+		DataCommitment: Commit(value, blinding),
+		Recipient:      recipient,
+		Ephemeral:      ephemeral,
+		Ciphertext:     ciphertext,
+		RangeMin:       min,
+		RangeMax:       max,
+	}
+	assignment := d.publicAssignment()
+	assignment.RawData = value
+	assignment.Randomness = blinding
+	assignment.EphemeralSecret = r
+	return assignment, d, nil
+}
+
+// PublicInputs returns the disclosure's public inputs in verifier order
+func (d SelectiveDisclosure) PublicInputs() ([]*big.Int, error) {
+	return PublicInputs(d.publicAssignment())
+}
+
+// VerifySelectiveDisclosureProof checks a selective-disclosure proof against its public inputs
+func VerifySelectiveDisclosureProof(proof Proof, d SelectiveDisclosure) error {
+	return Verify("selective_disclosure", proof, d.publicAssignment())
+}
+
+func pointVar(p edwards.PointAffine) twistededwards.Point {
+	return twistededwards.Point{X: p.X.BigInt(new(big.Int)), Y: p.Y.BigInt(new(big.Int))}
+}
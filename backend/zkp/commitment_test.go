@@ -0,0 +1,95 @@
+package zkp
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestProofOfReservesRejectsForgedCommitments(t *testing.T) {
+	reserves, liabilities := big.NewInt(1_000_000), big.NewInt(750_000)
+	rb, _ := RandomFieldElement()
+	lb, _ := RandomFieldElement()
+
+	proof, err := GenerateProofOfReserves(reserves, liabilities, rb, lb)
+	if err != nil {
+		t.Fatalf("Solvent reserves failed to prove: %v", err)
+	}
+	if err := VerifyProofOfReserves(proof, Commit(reserves, rb), Commit(liabilities, lb)); err != nil {
+		t.Errorf("Valid proof rejected: %v", err)
+	}
+	if err := VerifyProofOfReserves(proof, Commit(big.NewInt(2_000_000), rb), Commit(liabilities, lb)); err == nil {
+		t.Error("Proof accepted for a different reserve commitment")
+	}
+
+	// The old additive commitment can be opened to any amount; it must not verify
+	if _, err := Prove("por", &ProofOfReservesCircuit{
+		ReserveCommitment:   new(big.Int).Add(reserves, rb),
+		LiabilityCommitment: Commit(liabilities, lb),
+		SolvencyProof:       1,
+		ReserveAmount:       reserves,
+		ReserveBlinding:     rb,
+		LiabilityAmount:     liabilities,
+		LiabilityBlinding:   lb,
+	}); err == nil {
+		t.Error("Forged reserve commitment was accepted")
+	}
+
+	if _, err := GenerateProofOfReserves(liabilities, reserves, rb, lb); err == nil {
+		t.Error("Insolvent reserves produced a proof")
+	}
+}
+
+func TestSelectiveDisclosureEncryptsToRecipient(t *testing.T) {
+	recipient, err := NewDisclosureKey()
+	if err != nil {
+		t.Fatalf("Key generation failed: %v", err)
+	}
+	score, blinding := big.NewInt(742), big.NewInt(99)
+
+	proof, d, err := GenerateSelectiveDisclosureProof(score, blinding, big.NewInt(700), big.NewInt(850), recipient.Public)
+	if err != nil {
+		t.Fatalf("Disclosure proof failed: %v", err)
+	}
+	if err := VerifySelectiveDisclosureProof(proof, d); err != nil {
+		t.Errorf("Valid disclosure rejected: %v", err)
+	}
+	if got := recipient.Decrypt(d.Ciphertext, d.Ephemeral); got.Cmp(score) != 0 {
+		t.Errorf("Recipient decrypted %v, want %v", got, score)
+	}
+
+	other, _ := NewDisclosureKey()
+	if got := other.Decrypt(d.Ciphertext, d.Ephemeral); got.Cmp(score) == 0 {
+		t.Error("Another key decrypted the disclosed value")
+	}
+
+	forged := d
+	forged.Ciphertext = new(big.Int).Add(d.Ciphertext, big.NewInt(1))
+	if err := VerifySelectiveDisclosureProof(proof, forged); err == nil {
+		t.Error("Proof accepted for a different ciphertext")
+	}
+	forged = d
+	forged.Recipient = other.Public
+	if err := VerifySelectiveDisclosureProof(proof, forged); err == nil {
+		t.Error("Proof accepted for a different recipient")
+	}
+
+	if _, _, err := GenerateSelectiveDisclosureProof(big.NewInt(640), blinding, big.NewInt(700), big.NewInt(850), recipient.Public); err == nil {
+		t.Error("Out-of-range value produced a proof")
+	}
+
+	// A commitment that does not open to the encrypted value must not prove
+	r := big.NewInt(12345)
+	ciphertext, ephemeral := EncryptToKey(score, recipient.Public, r)
+	assignment := SelectiveDisclosure{
+		DataCommitment: Commit(big.NewInt(800), blinding),
+		Recipient:      recipient.Public,
+		Ephemeral:      ephemeral,
+		Ciphertext:     ciphertext,
+		RangeMin:       big.NewInt(700),
+		RangeMax:       big.NewInt(850),
+	}.publicAssignment()
+	assignment.RawData, assignment.Randomness, assignment.EphemeralSecret = score, blinding, r
+	if _, err := Prove("selective_disclosure", assignment); err == nil {
+		t.Error("Forged data commitment was accepted")
+	}
+}
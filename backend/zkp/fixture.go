@@ -0,0 +1,159 @@
+package zkp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+)
+
+// Fixture is a valid proof of a circuit and its public inputs, for testing
+// the circuit's exported verifier contract. Numbers are decimal strings.
+type Fixture struct {
+	Circuit string  `json:"circuit"`
+	Version int     `json:"version"`
+	Backend Backend `json:"backend"`
+	VKHash  string  `json:"vk_hash"`
+
+	// Proof is the uint256[8] of a Groth16 proof without commitments;
+	// ProofBytes is the proof as the verifier's bytes argument takes it
+	Proof        []string `json:"proof,omitempty"`
+	ProofBytes   string   `json:"proof_bytes"`
+	PublicInputs []string `json:"public_inputs"`
+}
+
+// GenerateFixture proves a fixed sample statement for circuit with its
+// current keys
+func GenerateFixture(circuit string) (Fixture, error) {
+	def, err := readyCircuit(circuit)
+	if err != nil {
+		return Fixture{}, err
+	}
+	assignment, err := sampleAssignment(circuit)
+	if err != nil {
+		return Fixture{}, fmt.Errorf("failed to build %s sample: %w", circuit, err)
+	}
+
+	proof, err := Prove(circuit, assignment)
+	if err != nil {
+		return Fixture{}, err
+	}
+	if err := Verify(circuit, proof, assignment); err != nil {
+		return Fixture{}, fmt.Errorf("%s sample proof does not verify: %w", circuit, err)
+	}
+	inputs, err := PublicInputs(assignment)
+	if err != nil {
+		return Fixture{}, err
+	}
+	raw, err := SerializeProofBytes(proof)
+	if err != nil {
+		return Fixture{}, err
+	}
+
+	f := Fixture{
+		Circuit:    circuit,
+		Version:    def.version,
+		Backend:    BackendFor(circuit),
+		ProofBytes: "0x" + hex.EncodeToString(raw),
+	}
+	if f.VKHash, err = VKFingerprint(circuit); err != nil {
+		return Fixture{}, err
+	}
+	if f.Backend == BackendGroth16 && len(raw) == groth16SolidityLen {
+		for i := 0; i < len(raw); i += 32 {
+			f.Proof = append(f.Proof, new(big.Int).SetBytes(raw[i:i+32]).String())
+		}
+	}
+	for _, in := range inputs {
+		f.PublicInputs = append(f.PublicInputs, in.String())
+	}
+	return f, nil
+}
+
+// sampleAssignment returns a satisfying assignment for each circuit
+func sampleAssignment(circuit string) (frontend.Circuit, error) {
+	switch circuit {
+	case "range":
+		return &RangeProofCircuit{Value: 150, Min: 100, Max: 200}, nil
+	case "vrf":
+		return &VRFCircuit{SecretKey: 7, Seed: 5, Randomness: 12}, nil
+	case "private":
+		return &PrivateComputationCircuit{SecretValue: 750, Threshold: 700, Satisfied: 1}, nil
+	case "bridge":
+		return sampleBridgeAssignment()
+	case "twap":
+		prices := []*big.Int{big.NewInt(300000), big.NewInt(301000), big.NewInt(299500)}
+		return twapAssignment(1000, 1180, big.NewInt(0), big.NewInt(1000000), prices, []uint64{1000, 1060, 1120})
+	case "por":
+		return reservesAssignment(big.NewInt(1000000), big.NewInt(950000), big.NewInt(11), big.NewInt(13)), nil
+	case "selective_disclosure":
+		key, err := NewDisclosureKey()
+		if err != nil {
+			return nil, err
+		}
+		assignment, _, err := disclosureAssignment(big.NewInt(742), big.NewInt(17), big.NewInt(701), big.NewInt(850), key.Public)
+		return assignment, err
+	case "aggregation":
+		padded, err := padUpdates([]RangeUpdate{{Value: big.NewInt(150), Min: big.NewInt(100), Max: big.NewInt(200)}}, AggregationBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		return aggregationAssignment(padded)
+	default:
+		return nil, fmt.Errorf("unknown circuit: %s", circuit)
+	}
+}
+
+// sampleBridgeAssignment signs a one-message batch with a throwaway committee
+func sampleBridgeAssignment() (frontend.Circuit, error) {
+	members := make([]*eddsa.PrivateKey, BridgeQuorum)
+	leaves := make([]*big.Int, BridgeQuorum)
+	for i := range members {
+		key, err := eddsa.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		members[i] = key
+		leaves[i] = MiMCHash(key.PublicKey.A.X.BigInt(new(big.Int)), key.PublicKey.A.Y.BigInt(new(big.Int)))
+	}
+	committee, err := NewMerkleTree(leaves, BridgeCommitteeDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	payloadHash := FieldElement([]byte("fixture payload"))
+	batch, err := NewMerkleTree([]*big.Int{BridgeLeaf(1, 42161, 0, payloadHash)}, BridgeBatchDepth)
+	if err != nil {
+		return nil, err
+	}
+	msg := make([]byte, 32)
+	batch.Root().FillBytes(msg)
+
+	w := BridgeWitness{
+		BatchRoot:     batch.Root(),
+		CommitteeRoot: committee.Root(),
+		SourceChain:   1,
+		TargetChain:   42161,
+		Nonce:         0,
+		PayloadHash:   payloadHash,
+		MessageIndex:  0,
+		MessagePath:   batch.Path(0),
+	}
+	for i, m := range members {
+		sig, err := m.Sign(msg, hash.MIMC_BN254.New())
+		if err != nil {
+			return nil, err
+		}
+		w.Signatures = append(w.Signatures, BridgeSignature{
+			PublicKey: m.PublicKey.Bytes(),
+			Signature: sig,
+			Index:     i,
+			Path:      committee.Path(i),
+		})
+	}
+	return w.assignment()
+}
@@ -0,0 +1,29 @@
+package zkp
+
+import (
+	"testing"
+)
+
+func TestGenerateFixtureVerifies(t *testing.T) {
+	for _, circuit := range []string{"range", "por", "bridge", "selective_disclosure"} {
+		f, err := GenerateFixture(circuit)
+		if err != nil {
+			t.Fatalf("GenerateFixture(%s) failed: %v", circuit, err)
+		}
+		if f.Version != 1 || f.Backend != BackendGroth16 || len(f.Proof) != 8 || len(f.PublicInputs) == 0 {
+			t.Errorf("Unexpected %s fixture: %+v", circuit, f)
+		}
+
+		// Both encodings must verify as the contract tests would submit them
+		for _, proof := range [][]string{f.Proof, {f.ProofBytes}} {
+			result, err := VerifyRequest{Circuit: circuit, Proof: proof, PublicInputs: f.PublicInputs}.Verify()
+			if err != nil || !result.Valid || result.VKFingerprint != f.VKHash {
+				t.Errorf("%s fixture does not verify: %+v, %v", circuit, result, err)
+			}
+		}
+	}
+
+	if _, err := GenerateFixture("oracle"); err == nil {
+		t.Error("Expected an unknown circuit to be rejected")
+	}
+}
@@ -0,0 +1,380 @@
+package zkp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/rs/zerolog/log"
+)
+
+// KeyFormatVersion is bumped whenever the on-disk key layout changes
+const KeyFormatVersion = 1
+
+// KeyMetadata is stored next to each key pair so stale keys are detected on load
+type KeyMetadata struct {
+	Circuit        string    `json:"circuit"`
+	CircuitVersion int       `json:"circuit_version,omitempty"`
+	Version        int       `json:"version"`
+	Curve          string    `json:"curve"`
+	CircuitHash    string    `json:"circuit_hash"`
+	VKHash         string    `json:"vk_hash"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Source is "setup" for a single-party setup or "ceremony" for MPC-generated keys
+	Source        string `json:"source"`
+	Contributions int    `json:"contributions,omitempty"`
+}
+
+// circuitDef binds a circuit to the package variables holding its compiled form and keys
+type circuitDef struct {
+	name string
+	// version is bumped whenever the circuit's constraints change, since its
+	// proofs then need a newly deployed verifier
+	version int
+	circuit func() frontend.Circuit
+	ccs     *constraint.ConstraintSystem
+	pk      *groth16.ProvingKey
+	vk      *groth16.VerifyingKey
+}
+
+var (
+	keyMu   sync.RWMutex
+	keyDir  string
+	keySets = make(map[string]bool) // circuit name -> keys loaded from disk
+)
+
+// SetKeyDir configures where proving and verifying keys are persisted.
+// Must be called before Init; an empty dir keeps the ephemeral setup.
+func SetKeyDir(dir string) {
+	keyMu.Lock()
+	defer keyMu.Unlock()
+	keyDir = dir
+}
+
+// PersistentKeys reports whether every initialized circuit loaded its keys from disk
+func PersistentKeys() bool {
+	keyMu.RLock()
+	defer keyMu.RUnlock()
+	if len(keySets) == 0 {
+		return false
+	}
+	for _, loaded := range keySets {
+		if !loaded {
+			return false
+		}
+	}
+	return true
+}
+
+func allCircuits() []circuitDef {
+	return append(append([]circuitDef{}, coreCircuits...), advancedCircuits...)
+}
+
+// CircuitNames lists every circuit the node can prove
+func CircuitNames() []string {
+	defs := allCircuits()
+	names := make([]string, len(defs))
+	for i, def := range defs {
+		names[i] = def.name
+	}
+	return names
+}
+
+func findCircuit(name string) (circuitDef, bool) {
+	for _, def := range allCircuits() {
+		if def.name == name {
+			return def, true
+		}
+	}
+	return circuitDef{}, false
+}
+
+// setupCircuit compiles a circuit and loads its keys from the key directory,
+// falling back to a fresh (ephemeral) setup when no keys were generated
+func setupCircuit(def circuitDef) error {
+	if BackendFor(def.name) == BackendPlonk {
+		return setupPlonkCircuit(def)
+	}
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, def.circuit())
+	if err != nil {
+		return fmt.Errorf("failed to compile %s circuit: %w", def.name, err)
+	}
+
+	keyMu.RLock()
+	dir := keyDir
+	keyMu.RUnlock()
+
+	var pk groth16.ProvingKey
+	var vk groth16.VerifyingKey
+	loaded := false
+
+	if dir != "" {
+		pk, vk, err = loadKeys(dir, def.name, ccs)
+		switch {
+		case err == nil:
+			loaded = true
+		case os.IsNotExist(err):
+			log.Warn().Str("circuit", def.name).Str("dir", dir).Msg("No persisted keys found; using ephemeral setup that will not match any deployed verifier")
+		default:
+			return err
+		}
+	}
+
+	if !loaded {
+		pk, vk, err = groth16.Setup(ccs)
+		if err != nil {
+			return fmt.Errorf("setup failed for %s circuit: %w", def.name, err)
+		}
+	}
+
+	*def.ccs, *def.pk, *def.vk = ccs, pk, vk
+
+	keyMu.Lock()
+	keySets[def.name] = loaded
+	keyMu.Unlock()
+	return nil
+}
+
+// GenerateKeys runs the setup for every circuit once and writes the keys to dir.
+// Existing keys are kept unless force is set, since replacing them invalidates
+// every deployed verifier.
+func GenerateKeys(dir string, force bool) ([]KeyMetadata, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	var generated []KeyMetadata
+	for _, def := range allCircuits() {
+		if _, err := os.Stat(metaPath(dir, def.name)); err == nil && !force {
+			return generated, fmt.Errorf("keys for %s already exist in %s (use force to overwrite)", def.name, dir)
+		}
+
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, def.circuit())
+		if err != nil {
+			return generated, fmt.Errorf("failed to compile %s circuit: %w", def.name, err)
+		}
+		pk, vk, err := groth16.Setup(ccs)
+		if err != nil {
+			return generated, fmt.Errorf("setup failed for %s circuit: %w", def.name, err)
+		}
+
+		meta, err := saveKeys(dir, KeyMetadata{Circuit: def.name, Source: "setup"}, ccs, pk, vk)
+		if err != nil {
+			return generated, err
+		}
+		generated = append(generated, meta)
+
+		// Circuits that embed this verifying key must compile against the new one
+		*def.ccs, *def.pk, *def.vk = ccs, pk, vk
+	}
+	return generated, nil
+}
+
+// saveKeys writes a key pair; meta supplies the circuit name and provenance
+func saveKeys(dir string, meta KeyMetadata, ccs constraint.ConstraintSystem, pk groth16.ProvingKey, vk groth16.VerifyingKey) (KeyMetadata, error) {
+	name := meta.Circuit
+	circuitHash, err := hashOf(ccs)
+	if err != nil {
+		return KeyMetadata{}, err
+	}
+	vkHash, err := hashOf(vk)
+	if err != nil {
+		return KeyMetadata{}, err
+	}
+
+	if err := writeKey(filepath.Join(dir, name+".pk"), pk); err != nil {
+		return KeyMetadata{}, err
+	}
+	if err := writeKey(filepath.Join(dir, name+".vk"), vk); err != nil {
+		return KeyMetadata{}, err
+	}
+
+	meta.Version = KeyFormatVersion
+	if def, ok := findCircuit(name); ok {
+		meta.CircuitVersion = def.version
+	}
+	meta.Curve = ecc.BN254.String()
+	meta.CircuitHash = circuitHash
+	meta.VKHash = vkHash
+	meta.CreatedAt = time.Now().UTC()
+
+	raw, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return KeyMetadata{}, err
+	}
+	return meta, os.WriteFile(metaPath(dir, name), raw, 0o600)
+}
+
+// loadKeys reads a key pair and rejects it if it was generated for a different
+// circuit or format version. A missing key set returns an os.IsNotExist error.
+func loadKeys(dir, name string, ccs constraint.ConstraintSystem) (groth16.ProvingKey, groth16.VerifyingKey, error) {
+	raw, err := os.ReadFile(metaPath(dir, name))
+	if err != nil {
+		return nil, nil, err
+	}
+	var meta KeyMetadata
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return nil, nil, fmt.Errorf("invalid key metadata for %s: %w", name, err)
+	}
+	if meta.Version != KeyFormatVersion {
+		return nil, nil, fmt.Errorf("keys for %s use format version %d, expected %d", name, meta.Version, KeyFormatVersion)
+	}
+
+	circuitHash, err := hashOf(ccs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if meta.CircuitHash != circuitHash {
+		return nil, nil, fmt.Errorf("keys for %s were generated for a different circuit (have %s, want %s); regenerate keys and redeploy the verifier", name, meta.CircuitHash[:12], circuitHash[:12])
+	}
+
+	pk := groth16.NewProvingKey(ecc.BN254)
+	if err := readKey(filepath.Join(dir, name+".pk"), pk); err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s proving key: %w", name, err)
+	}
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	if err := readKey(filepath.Join(dir, name+".vk"), vk); err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s verifying key: %w", name, err)
+	}
+
+	vkHash, err := hashOf(vk)
+	if err != nil {
+		return nil, nil, err
+	}
+	if meta.VKHash != vkHash {
+		return nil, nil, fmt.Errorf("verifying key for %s does not match its metadata", name)
+	}
+
+	log.Info().Str("circuit", name).Str("source", meta.Source).Str("vk_hash", vkHash[:12]).Msg("Loaded persisted ZK keys")
+	return pk, vk, nil
+}
+
+// solidityConstant matches the key-derived constants in a gnark-exported
+// Groth16 or PLONK verifier
+var solidityConstant = regexp.MustCompile(`uint256 (?:private )?constant ((?:ALPHA|BETA|GAMMA|DELTA|CONSTANT|PUB|VK|G1_SRS|G2_SRS)_[A-Z0-9_]*) = (\w+);`)
+
+// VerifyAgainstSolidity fails if the circuit's verifying key differs from the
+// one embedded in a deployed gnark-exported Verifier.sol
+func VerifyAgainstSolidity(circuit, path string) error {
+	def, ok := findCircuit(circuit)
+	if !ok {
+		return fmt.Errorf("unknown circuit: %s", circuit)
+	}
+	if !circuitReady(def) {
+		return fmt.Errorf("%s circuit is not initialized", circuit)
+	}
+
+	deployed, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var exported bytes.Buffer
+	if err := ExportVerifier(circuit, &exported); err != nil {
+		return err
+	}
+
+	want := solidityConstants(exported.Bytes())
+	have := solidityConstants(deployed)
+	if len(have) == 0 {
+		return fmt.Errorf("%s does not look like a gnark-exported verifier", path)
+	}
+	if len(have) != len(want) {
+		return fmt.Errorf("verifying key for %s does not match %s: different number of public inputs", circuit, path)
+	}
+	for name, value := range want {
+		if have[name] != value {
+			return fmt.Errorf("verifying key for %s does not match %s (%s differs); re-export and redeploy the verifier or restore the original keys", circuit, path, name)
+		}
+	}
+	return nil
+}
+
+// VerifyAgainstBytecode fails if the circuit's verifying key is not the one
+// compiled into a deployed verifier's runtime bytecode. Solidity inlines each
+// key-derived constant as a PUSH immediate, so every constant of the exported
+// verifier must appear in the code.
+func VerifyAgainstBytecode(circuit string, code []byte) error {
+	if len(code) == 0 {
+		return fmt.Errorf("no contract deployed for the %s verifier", circuit)
+	}
+	if _, ok := findCircuit(circuit); !ok {
+		return fmt.Errorf("unknown circuit: %s", circuit)
+	}
+
+	var exported bytes.Buffer
+	if err := ExportVerifier(circuit, &exported); err != nil {
+		return err
+	}
+	for name, literal := range solidityConstants(exported.Bytes()) {
+		value, ok := new(big.Int).SetString(literal, 0)
+		if !ok {
+			return fmt.Errorf("unparsable constant %s in exported %s verifier", name, circuit)
+		}
+		if value.Sign() == 0 {
+			continue
+		}
+		raw := value.Bytes()
+		push := append([]byte{byte(0x5f + len(raw))}, raw...)
+		if !bytes.Contains(code, push) {
+			return fmt.Errorf("verifying key for %s does not match the deployed verifier (%s differs); re-export and redeploy the verifier or restore the original keys", circuit, name)
+		}
+	}
+	return nil
+}
+
+func solidityConstants(src []byte) map[string]string {
+	constants := make(map[string]string)
+	for _, m := range solidityConstant.FindAllSubmatch(src, -1) {
+		constants[string(m[1])] = string(m[2])
+	}
+	return constants
+}
+
+func metaPath(dir, name string) string {
+	return filepath.Join(dir, name+".meta.json")
+}
+
+func hashOf(w io.WriterTo) (string, error) {
+	h := sha256.New()
+	if _, err := w.WriteTo(h); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func writeKey(path string, key io.WriterTo) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = key.WriteTo(f)
+	return err
+}
+
+func readKey(path string, key io.ReaderFrom) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = key.ReadFrom(f)
+	return err
+}
@@ -0,0 +1,127 @@
+package zkp
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+)
+
+func TestPersistedKeysSurviveRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &RangeProofCircuit{})
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	if _, err := saveKeys(dir, KeyMetadata{Circuit: "range"}, ccs, pk, vk); err != nil {
+		t.Fatalf("Failed to save keys: %v", err)
+	}
+
+	// Simulate a restart: the range circuit must come back with the same keys
+	SetKeyDir(dir)
+	defer SetKeyDir("")
+	if err := setupCircuit(coreCircuits[0]); err != nil {
+		t.Fatalf("Failed to load persisted keys: %v", err)
+	}
+
+	origHash, _ := hashOf(vk)
+	loadedHash, _ := hashOf(rangeVK)
+	if origHash != loadedHash {
+		t.Fatal("Loaded verifying key differs from the persisted one")
+	}
+
+	proof, err := GenerateRangeProof(big.NewInt(150), big.NewInt(100), big.NewInt(200))
+	if err != nil {
+		t.Fatalf("Proof with loaded keys failed: %v", err)
+	}
+	publicWitness, _ := frontend.NewWitness(&RangeProofCircuit{Min: big.NewInt(100), Max: big.NewInt(200)}, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	if err := groth16.Verify(proof.(groth16.Proof), vk, publicWitness); err != nil {
+		t.Errorf("Proof from loaded keys rejected by original verifying key: %v", err)
+	}
+
+	// The exported verifier matches; one from a different setup does not
+	solPath := filepath.Join(dir, "Verifier.sol")
+	f, _ := os.Create(solPath)
+	vk.ExportSolidity(f)
+	f.Close()
+	if err := VerifyAgainstSolidity("range", solPath); err != nil {
+		t.Errorf("Expected exported verifier to match: %v", err)
+	}
+
+	src, _ := os.ReadFile(solPath)
+	if err := VerifyAgainstBytecode("range", pushBytecode(src)); err != nil {
+		t.Errorf("Expected deployed bytecode to match: %v", err)
+	}
+
+	_, otherVK, _ := groth16.Setup(ccs)
+	f, _ = os.Create(solPath)
+	otherVK.ExportSolidity(f)
+	f.Close()
+	if err := VerifyAgainstSolidity("range", solPath); err == nil {
+		t.Error("Expected mismatch against a verifier from a different setup")
+	}
+	src, _ = os.ReadFile(solPath)
+	if err := VerifyAgainstBytecode("range", pushBytecode(src)); err == nil {
+		t.Error("Expected mismatch against bytecode from a different setup")
+	}
+	if err := VerifyAgainstBytecode("range", nil); err == nil {
+		t.Error("Expected an error when no verifier is deployed")
+	}
+}
+
+// pushBytecode mimics solc output: every constant of a verifier source
+// becomes a PUSH of its minimal big-endian encoding between other opcodes
+func pushBytecode(src []byte) []byte {
+	code := []byte{0x60, 0x80, 0x60, 0x40, 0x52}
+	for _, literal := range solidityConstants(src) {
+		value, _ := new(big.Int).SetString(literal, 0)
+		raw := value.Bytes()
+		code = append(code, byte(0x5f+len(raw)))
+		code = append(code, raw...)
+		code = append(code, 0x52)
+	}
+	return code
+}
+
+func TestStaleKeysRejected(t *testing.T) {
+	dir := t.TempDir()
+
+	ccs, _ := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &RangeProofCircuit{})
+	pk, vk, _ := groth16.Setup(ccs)
+	if _, err := saveKeys(dir, KeyMetadata{Circuit: "range"}, ccs, pk, vk); err != nil {
+		t.Fatalf("Failed to save keys: %v", err)
+	}
+
+	// Keys generated for the range circuit must not load for the VRF circuit
+	vrf, _ := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &VRFCircuit{})
+	if _, _, err := loadKeys(dir, "range", vrf); err == nil || !strings.Contains(err.Error(), "different circuit") {
+		t.Errorf("Expected circuit hash mismatch, got %v", err)
+	}
+
+	raw, _ := os.ReadFile(metaPath(dir, "range"))
+	var meta KeyMetadata
+	json.Unmarshal(raw, &meta)
+	meta.Version = KeyFormatVersion + 1
+	raw, _ = json.Marshal(meta)
+	os.WriteFile(metaPath(dir, "range"), raw, 0o600)
+
+	if _, _, err := loadKeys(dir, "range", ccs); err == nil {
+		t.Error("Expected keys with an unknown format version to be rejected")
+	}
+
+	if _, _, err := loadKeys(dir, "vrf", vrf); !os.IsNotExist(err) {
+		t.Errorf("Expected missing keys to report not-exist, got %v", err)
+	}
+}
@@ -0,0 +1,454 @@
+package zkp
+
+import (
+	"container/heap"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrProverQueueFull is returned when a proof is shed because the queue is at capacity
+	ErrProverQueueFull = errors.New("prover queue full")
+	// ErrProverStopped is returned for proofs requested or still queued after shutdown
+	ErrProverStopped = errors.New("prover stopped")
+)
+
+// ProverMetrics receives proving telemetry
+type ProverMetrics interface {
+	ObserveProvingTime(circuit string, d time.Duration)
+	IncrementProofCacheHits(circuit string)
+	SetProverQueueDepth(depth int)
+}
+
+// ProverConfig controls the proving worker pool
+type ProverConfig struct {
+	// Workers is the number of proofs generated concurrently
+	Workers int
+
+	// QueueSize is the maximum number of proofs waiting for a worker
+	QueueSize int
+
+	// CacheSize is the number of proofs kept for reuse by identical witnesses; 0 disables caching
+	CacheSize int
+
+	// CacheTTL is how long a cached proof may be reused
+	CacheTTL time.Duration
+
+	// Priorities orders waiting proofs by circuit, highest first; unlisted circuits get 0
+	Priorities map[string]int
+}
+
+// DefaultProverConfig sizes the pool to the machine and puts latency-sensitive
+// fulfillments ahead of periodic attestations
+func DefaultProverConfig() ProverConfig {
+	return ProverConfig{
+		Workers:   runtime.NumCPU(),
+		QueueSize: 256,
+		CacheSize: 128,
+		CacheTTL:  10 * time.Minute,
+		Priorities: map[string]int{
+			"range":                10, // data feed fulfillments
+			"private":              8,
+			"vrf":                  8,
+			"selective_disclosure": 5,
+			"bridge":               4,
+			"twap":                 3,
+			"aggregation":          2,
+			"por":                  1,
+		},
+	}
+}
+
+// proofTask is one queued witness, shared by every caller requesting it
+type proofTask struct {
+	circuit  string
+	key      [sha256.Size]byte
+	witness  witness.Witness
+	priority int
+	seq      uint64
+	index    int // position in the queue, -1 once taken by a worker
+	waiters  int
+
+	done  chan struct{}
+	proof Proof
+	err   error
+}
+
+// proofQueue is a heap of tasks by priority, then arrival
+type proofQueue []*proofTask
+
+func (q proofQueue) Len() int { return len(q) }
+
+func (q proofQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q proofQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *proofQueue) Push(x any) {
+	t := x.(*proofTask)
+	t.index = len(*q)
+	*q = append(*q, t)
+}
+
+func (q *proofQueue) Pop() any {
+	old := *q
+	t := old[len(old)-1]
+	old[len(old)-1] = nil
+	t.index = -1
+	*q = old[:len(old)-1]
+	return t
+}
+
+// ProverService generates proofs on a bounded worker pool so bursts of proving
+// queue up instead of competing with the rest of the node for CPU
+type ProverService struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	config   ProverConfig
+	queue    proofQueue
+	inflight map[[sha256.Size]byte]*proofTask
+	cache    *proofCache
+	seq      uint64
+	closed   bool
+	metrics  ProverMetrics
+	remote   *RemoteProver
+
+	// prove generates the proof for a task; replaced in tests
+	prove func(circuit string, w witness.Witness) (Proof, error)
+}
+
+// NewProverService creates a prover; workers are started by Start
+func NewProverService(config ProverConfig) *ProverService {
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1
+	}
+
+	p := &ProverService{
+		config:   config,
+		inflight: make(map[[sha256.Size]byte]*proofTask),
+		cache:    newProofCache(config.CacheSize, config.CacheTTL),
+		prove:    proveWitness,
+	}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+// SetMetrics reports proving times, cache hits and queue depth
+func (p *ProverService) SetMetrics(m ProverMetrics) {
+	p.metrics = m
+}
+
+// SetRemote offloads proving to remote workers, falling back to local proving
+// when none of them returns a valid proof. Must be called before Start.
+func (p *ProverService) SetRemote(r *RemoteProver) {
+	p.remote = r
+}
+
+// Prove queues a proof of assignment and waits for it. A witness identical to
+// one already queued or recently proven shares that proof. If ctx is done
+// first, the proof is dropped from the queue unless another caller waits on it.
+func (p *ProverService) Prove(ctx context.Context, circuit string, assignment frontend.Circuit) (Proof, error) {
+	w, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	return p.ProveWitness(ctx, circuit, w)
+}
+
+// ProveWitness is Prove for an already built full witness
+func (p *ProverService) ProveWitness(ctx context.Context, circuit string, w witness.Witness) (Proof, error) {
+	if _, err := readyCircuit(circuit); err != nil {
+		return nil, err
+	}
+	key, err := witnessKey(circuit, w)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, ErrProverStopped
+	}
+	if proof, ok := p.cache.get(key, time.Now()); ok {
+		p.mu.Unlock()
+		p.cacheHit(circuit)
+		return proof, nil
+	}
+	task, shared := p.inflight[key]
+	if !shared {
+		if len(p.queue) >= p.config.QueueSize {
+			p.mu.Unlock()
+			return nil, ErrProverQueueFull
+		}
+		p.seq++
+		task = &proofTask{
+			circuit:  circuit,
+			key:      key,
+			witness:  w,
+			priority: p.config.Priorities[circuit],
+			seq:      p.seq,
+			done:     make(chan struct{}),
+		}
+		heap.Push(&p.queue, task)
+		p.inflight[key] = task
+		p.notifyDepth()
+		p.cond.Signal()
+	}
+	task.waiters++
+	p.mu.Unlock()
+
+	if shared {
+		p.cacheHit(circuit)
+	}
+
+	select {
+	case <-task.done:
+		return task.proof, task.err
+	case <-ctx.Done():
+		p.abandon(task)
+		return nil, ctx.Err()
+	}
+}
+
+// Depth returns the number of proofs waiting for a worker
+func (p *ProverService) Depth() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.queue)
+}
+
+// Start runs the workers and blocks until ctx is cancelled and in-flight proofs
+// finish. Proofs still queued fail with ErrProverStopped.
+func (p *ProverService) Start(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+		p.mu.Lock()
+		p.closed = true
+		for p.queue.Len() > 0 {
+			task := heap.Pop(&p.queue).(*proofTask)
+			delete(p.inflight, task.key)
+			task.err = ErrProverStopped
+			close(task.done)
+		}
+		p.notifyDepth()
+		p.mu.Unlock()
+		p.cond.Broadcast()
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				task, ok := p.next()
+				if !ok {
+					return
+				}
+				p.run(task)
+			}
+		}()
+	}
+
+	log.Info().Int("workers", p.config.Workers).Int("queue_size", p.config.QueueSize).Int("cache_size", p.config.CacheSize).Msg("Prover service started")
+	wg.Wait()
+}
+
+// next blocks until a task is queued, highest priority first
+func (p *ProverService) next() (*proofTask, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for {
+		if p.closed {
+			return nil, false
+		}
+		if p.queue.Len() > 0 {
+			task := heap.Pop(&p.queue).(*proofTask)
+			p.notifyDepth()
+			return task, true
+		}
+		p.cond.Wait()
+	}
+}
+
+func (p *ProverService) run(task *proofTask) {
+	start := time.Now()
+	proof, err := p.generate(task)
+	elapsed := time.Since(start)
+
+	p.mu.Lock()
+	delete(p.inflight, task.key)
+	if err == nil {
+		p.cache.put(task.key, proof, time.Now())
+	}
+	task.proof, task.err = proof, err
+	task.witness = nil
+	close(task.done)
+	p.mu.Unlock()
+
+	if err != nil {
+		log.Warn().Err(err).Str("circuit", task.circuit).Msg("Proof generation failed")
+		return
+	}
+	if p.metrics != nil {
+		p.metrics.ObserveProvingTime(task.circuit, elapsed)
+	}
+}
+
+// generate proves a task on a remote worker when one is configured and
+// answers with a proof that verifies, otherwise locally
+func (p *ProverService) generate(task *proofTask) (Proof, error) {
+	if p.remote != nil {
+		proof, err := p.remote.Prove(context.Background(), task.circuit, task.witness)
+		if err == nil {
+			return proof, nil
+		}
+		log.Warn().Err(err).Str("circuit", task.circuit).Msg("No remote prover returned a valid proof, proving locally")
+	}
+	return p.prove(task.circuit, task.witness)
+}
+
+// abandon drops a caller's interest in a task, removing it from the queue
+// when nobody else is waiting. A task already being proven runs to completion
+// and its proof is cached.
+func (p *ProverService) abandon(task *proofTask) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	task.waiters--
+	if task.waiters > 0 || task.index < 0 {
+		return
+	}
+	heap.Remove(&p.queue, task.index)
+	delete(p.inflight, task.key)
+	p.notifyDepth()
+}
+
+func (p *ProverService) cacheHit(circuit string) {
+	if p.metrics != nil {
+		p.metrics.IncrementProofCacheHits(circuit)
+	}
+}
+
+// notifyDepth must be called with p.mu held
+func (p *ProverService) notifyDepth() {
+	if p.metrics != nil {
+		p.metrics.SetProverQueueDepth(p.queue.Len())
+	}
+}
+
+// witnessKey identifies a request by circuit, backend and full witness, so
+// only requests that would produce interchangeable proofs share one
+func witnessKey(circuit string, w witness.Witness) ([sha256.Size]byte, error) {
+	data, err := w.MarshalBinary()
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	h := sha256.New()
+	h.Write([]byte(circuit))
+	h.Write([]byte{0})
+	h.Write([]byte(BackendFor(circuit)))
+	h.Write([]byte{0})
+	h.Write(data)
+
+	var key [sha256.Size]byte
+	copy(key[:], h.Sum(nil))
+	return key, nil
+}
+
+// proofCache is a least-recently-used cache of proofs by witness key
+type proofCache struct {
+	size    int
+	ttl     time.Duration
+	order   *list.List // front is most recently used
+	entries map[[sha256.Size]byte]*list.Element
+}
+
+type cachedProof struct {
+	key     [sha256.Size]byte
+	proof   Proof
+	expires time.Time
+}
+
+func newProofCache(size int, ttl time.Duration) *proofCache {
+	return &proofCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[[sha256.Size]byte]*list.Element),
+	}
+}
+
+func (c *proofCache) get(key [sha256.Size]byte, now time.Time) (Proof, bool) {
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cachedProof)
+	if c.ttl > 0 && now.After(entry.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return entry.proof, true
+}
+
+func (c *proofCache) put(key [sha256.Size]byte, proof Proof, now time.Time) {
+	if c.size <= 0 {
+		return
+	}
+	entry := &cachedProof{key: key, proof: proof, expires: now.Add(c.ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedProof).key)
+	}
+}
+
+var (
+	proverMu      sync.RWMutex
+	defaultProver *ProverService
+)
+
+// SetProver routes Prove and every Generate* function through p; nil proves inline
+func SetProver(p *ProverService) {
+	proverMu.Lock()
+	defer proverMu.Unlock()
+	defaultProver = p
+}
+
+func currentProver() *ProverService {
+	proverMu.RLock()
+	defer proverMu.RUnlock()
+	return defaultProver
+}
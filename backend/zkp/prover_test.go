@@ -0,0 +1,158 @@
+package zkp
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+)
+
+type proverMetricsRecorder struct {
+	mu        sync.Mutex
+	observed  map[string]int
+	cacheHits map[string]int
+}
+
+func (m *proverMetricsRecorder) ObserveProvingTime(circuit string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.observed[circuit]++
+}
+
+func (m *proverMetricsRecorder) IncrementProofCacheHits(circuit string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cacheHits[circuit]++
+}
+
+func (m *proverMetricsRecorder) SetProverQueueDepth(int) {}
+
+func rangeAssignment(value int64) frontend.Circuit {
+	return &RangeProofCircuit{Value: value, Min: 0, Max: 1000}
+}
+
+func TestProverServiceCachesIdenticalWitness(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	metrics := &proverMetricsRecorder{observed: map[string]int{}, cacheHits: map[string]int{}}
+	p := NewProverService(ProverConfig{Workers: 2, QueueSize: 4, CacheSize: 4, CacheTTL: time.Minute})
+	p.SetMetrics(metrics)
+	go p.Start(ctx)
+
+	first, err := p.Prove(ctx, "range", rangeAssignment(150))
+	if err != nil {
+		t.Fatalf("Prove failed: %v", err)
+	}
+	if ok, _ := VerifyRangeProof(first, big.NewInt(0), big.NewInt(1000)); !ok {
+		t.Fatal("Proof from the prover service does not verify")
+	}
+
+	second, err := p.Prove(ctx, "range", rangeAssignment(150))
+	if err != nil {
+		t.Fatalf("Prove failed: %v", err)
+	}
+	if second != first {
+		t.Error("Expected an identical witness to be served from the cache")
+	}
+	if _, err := p.Prove(ctx, "range", rangeAssignment(151)); err != nil {
+		t.Fatalf("Prove failed: %v", err)
+	}
+	if metrics.observed["range"] != 2 || metrics.cacheHits["range"] != 1 {
+		t.Errorf("Expected 2 proofs and 1 cache hit, got %d and %d", metrics.observed["range"], metrics.cacheHits["range"])
+	}
+}
+
+func TestProverServicePrioritiesAndCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := NewProverService(ProverConfig{
+		Workers:    1,
+		QueueSize:  3,
+		Priorities: map[string]int{"range": 1, "private": 3, "vrf": 5},
+	})
+	started := make(chan string, 8)
+	release := make(chan struct{})
+	p.prove = func(circuit string, w witness.Witness) (Proof, error) {
+		started <- circuit
+		<-release
+		return nil, nil
+	}
+	go p.Start(ctx)
+
+	var wg sync.WaitGroup
+	submit := func(circuit string, assignment frontend.Circuit, depth int) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := p.Prove(ctx, circuit, assignment); err != nil {
+				t.Errorf("Prove(%s) failed: %v", circuit, err)
+			}
+		}()
+		waitFor(t, func() bool { return p.Depth() == depth })
+	}
+
+	// The first request occupies the only worker; the rest queue behind it
+	submit("range", rangeAssignment(1), 0)
+	if got := <-started; got != "range" {
+		t.Fatalf("Expected the blocking range proof to start, got %s", got)
+	}
+	submit("range", rangeAssignment(2), 1)
+	submit("private", &PrivateComputationCircuit{SecretValue: 10, Threshold: 5, Satisfied: 1}, 2)
+	submit("vrf", &VRFCircuit{SecretKey: 7, Seed: 5, Randomness: 12}, 3)
+
+	if _, err := p.Prove(ctx, "range", rangeAssignment(3)); !errors.Is(err, ErrProverQueueFull) {
+		t.Errorf("Expected ErrProverQueueFull, got %v", err)
+	}
+
+	// A cancelled request leaves the queue without being proven
+	cancelled, cancelWait := context.WithCancel(ctx)
+	go func() {
+		waitFor(t, func() bool { return p.Depth() == 4 })
+		cancelWait()
+	}()
+	p.mu.Lock()
+	p.config.QueueSize = 4
+	p.mu.Unlock()
+	if _, err := p.Prove(cancelled, "range", rangeAssignment(4)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if depth := p.Depth(); depth != 3 {
+		t.Errorf("Expected the cancelled proof to leave the queue, depth %d", depth)
+	}
+
+	close(release)
+	wg.Wait()
+	close(started)
+
+	var order []string
+	for circuit := range started {
+		order = append(order, circuit)
+	}
+	want := []string{"vrf", "private", "range"}
+	if len(order) != len(want) {
+		t.Fatalf("Expected proofs %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("Expected proofs in priority order %v, got %v", want, order)
+		}
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the prover queue")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,138 @@
+package zkp
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrUnknownVerifier is returned for a circuit version with no verifier
+// registered on the target chain
+var ErrUnknownVerifier = errors.New("no verifier registered")
+
+// CircuitVersion returns the version of a circuit the node currently proves
+func CircuitVersion(circuit string) (int, error) {
+	def, ok := findCircuit(circuit)
+	if !ok {
+		return 0, fmt.Errorf("unknown circuit: %s", circuit)
+	}
+	return def.version, nil
+}
+
+// CircuitInfo describes a circuit version and the verifiers deployed for it.
+// Retired versions only carry their name, version and verifiers.
+type CircuitInfo struct {
+	Name        string            `json:"name"`
+	Version     int               `json:"version"`
+	Current     bool              `json:"current"`
+	Backend     Backend           `json:"backend,omitempty"`
+	Constraints int               `json:"constraints,omitempty"`
+	VKHash      string            `json:"vk_hash,omitempty"`
+	Verifiers   map[uint64]string `json:"verifiers"` // chain ID -> verifier address
+}
+
+type verifierKey struct {
+	circuit string
+	version int
+	chainID uint64
+}
+
+// VerifierRegistry records the verifier deployed for each circuit version on
+// each chain. Verifiers of earlier versions stay registered after an upgrade so
+// consumers can move to the new version on their own schedule.
+type VerifierRegistry struct {
+	mu        sync.RWMutex
+	verifiers map[verifierKey]string
+}
+
+// NewVerifierRegistry creates an empty registry
+func NewVerifierRegistry() *VerifierRegistry {
+	return &VerifierRegistry{verifiers: make(map[verifierKey]string)}
+}
+
+// Register records the verifier for a circuit version on a chain, replacing
+// any earlier address
+func (r *VerifierRegistry) Register(circuit string, version int, chainID uint64, address string) error {
+	def, ok := findCircuit(circuit)
+	if !ok {
+		return fmt.Errorf("unknown circuit: %s", circuit)
+	}
+	if version < 1 || version > def.version {
+		return fmt.Errorf("%s circuit has no version %d (current is %d)", circuit, version, def.version)
+	}
+	if !common.IsHexAddress(address) {
+		return fmt.Errorf("verifier address %q is not a hex address", address)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.verifiers[verifierKey{circuit, version, chainID}] = common.HexToAddress(address).Hex()
+	return nil
+}
+
+// Verifier returns the verifier for a circuit version on a chain, or
+// ErrUnknownVerifier when none is registered
+func (r *VerifierRegistry) Verifier(circuit string, version int, chainID uint64) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	address, ok := r.verifiers[verifierKey{circuit, version, chainID}]
+	if !ok {
+		return "", fmt.Errorf("%w for %s v%d on chain %d", ErrUnknownVerifier, circuit, version, chainID)
+	}
+	return address, nil
+}
+
+// Circuits lists every circuit at its current version, followed by earlier
+// versions that still have verifiers registered. Constraint counts and VK
+// hashes are only known once a circuit has been set up.
+func (r *VerifierRegistry) Circuits() []CircuitInfo {
+	r.mu.RLock()
+	versions := make(map[verifierKey]map[uint64]string)
+	for key, address := range r.verifiers {
+		vk := verifierKey{circuit: key.circuit, version: key.version}
+		if versions[vk] == nil {
+			versions[vk] = make(map[uint64]string)
+		}
+		versions[vk][key.chainID] = address
+	}
+	r.mu.RUnlock()
+
+	var infos []CircuitInfo
+	for _, def := range allCircuits() {
+		info := CircuitInfo{
+			Name:      def.name,
+			Version:   def.version,
+			Current:   true,
+			Backend:   BackendFor(def.name),
+			Verifiers: versions[verifierKey{circuit: def.name, version: def.version}],
+		}
+		if info.Verifiers == nil {
+			info.Verifiers = map[uint64]string{}
+		}
+		if circuitReady(def) {
+			info.Constraints = constraintCount(def)
+			info.VKHash, _ = VKFingerprint(def.name)
+		}
+		infos = append(infos, info)
+
+		var retired []CircuitInfo
+		for key, verifiers := range versions {
+			if key.circuit == def.name && key.version < def.version {
+				retired = append(retired, CircuitInfo{Name: def.name, Version: key.version, Verifiers: verifiers})
+			}
+		}
+		sort.Slice(retired, func(i, j int) bool { return retired[i].Version > retired[j].Version })
+		infos = append(infos, retired...)
+	}
+	return infos
+}
+
+func constraintCount(def circuitDef) int {
+	if keys := plonkKeysFor(def.name); keys != nil {
+		return keys.ccs.GetNbConstraints()
+	}
+	return (*def.ccs).GetNbConstraints()
+}
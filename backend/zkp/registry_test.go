@@ -0,0 +1,51 @@
+package zkp
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestVerifierRegistryTracksVersionsPerChain(t *testing.T) {
+	if _, err := GenerateRangeProof(big.NewInt(5), big.NewInt(0), big.NewInt(10)); err != nil {
+		t.Fatalf("GenerateRangeProof failed: %v", err)
+	}
+	r := NewVerifierRegistry()
+	if err := r.Register("range", 1, 1, "0x00000000000000000000000000000000000000aa"); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	if addr, err := r.Verifier("range", 1, 1); err != nil || addr != "0x00000000000000000000000000000000000000AA" {
+		t.Errorf("Expected the registered verifier, got %s, %v", addr, err)
+	}
+	if _, err := r.Verifier("range", 1, 10); !errors.Is(err, ErrUnknownVerifier) {
+		t.Errorf("Expected ErrUnknownVerifier on another chain, got %v", err)
+	}
+	if err := r.Register("range", 2, 1, "0x00000000000000000000000000000000000000bb"); err == nil {
+		t.Error("Expected a version newer than the circuit to be rejected")
+	}
+	if err := r.Register("oracle", 1, 1, "0x00000000000000000000000000000000000000bb"); err == nil {
+		t.Error("Expected an unknown circuit to be rejected")
+	}
+	if err := r.Register("range", 1, 1, "verifier"); err == nil {
+		t.Error("Expected an invalid address to be rejected")
+	}
+
+	var found bool
+	for _, info := range r.Circuits() {
+		if info.Name != "range" {
+			continue
+		}
+		found = true
+		fingerprint, _ := VKFingerprint("range")
+		if !info.Current || info.Version != 1 || info.Constraints == 0 || info.VKHash != fingerprint {
+			t.Errorf("Unexpected range circuit info: %+v", info)
+		}
+		if info.Verifiers[1] == "" || len(info.Verifiers) != 1 {
+			t.Errorf("Expected one verifier on chain 1, got %v", info.Verifiers)
+		}
+	}
+	if !found {
+		t.Error("Expected the range circuit to be listed")
+	}
+}
@@ -0,0 +1,234 @@
+package zkp
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/rs/zerolog/log"
+)
+
+// Remote prover protocol endpoints
+const (
+	RemoteProvePath  = "/v1/prove"
+	RemoteHealthPath = "/v1/health"
+)
+
+// maxRemoteBody bounds witness uploads and proof responses
+const maxRemoteBody = 16 << 20
+
+// RemoteProveRequest asks a prover worker for a proof of a full witness.
+// Witness is gnark's binary witness encoding and includes the secret inputs.
+type RemoteProveRequest struct {
+	Circuit string  `json:"circuit"`
+	Backend Backend `json:"backend"`
+	Witness []byte  `json:"witness"`
+}
+
+// RemoteProveResponse carries the proof in the backend's binary encoding, or
+// why none was produced
+type RemoteProveResponse struct {
+	Proof []byte `json:"proof,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// RemoteProver offloads proving to prover workers. Every proof is verified
+// against the witness's public inputs before it is returned, so a faulty or
+// misconfigured worker can cost time but never an invalid fulfillment.
+type RemoteProver struct {
+	workers []string
+	token   string
+	client  *http.Client
+	next    atomic.Uint32
+}
+
+// NewRemoteProver creates a client for the given worker base URLs. token is
+// sent as a bearer token; timeout bounds each attempt.
+func NewRemoteProver(workers []string, token string, timeout time.Duration) (*RemoteProver, error) {
+	if len(workers) == 0 {
+		return nil, fmt.Errorf("no prover workers configured")
+	}
+	urls := make([]string, len(workers))
+	for i, w := range workers {
+		if !strings.HasPrefix(w, "http://") && !strings.HasPrefix(w, "https://") {
+			return nil, fmt.Errorf("prover worker %q must be an http(s) URL", w)
+		}
+		if strings.HasPrefix(w, "http://") {
+			log.Warn().Str("worker", w).Msg("Prover worker is not using TLS; witnesses include private inputs")
+		}
+		urls[i] = strings.TrimRight(w, "/")
+	}
+	return &RemoteProver{
+		workers: urls,
+		token:   token,
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Prove tries each worker in turn, starting after the one used last, and
+// returns the first proof that verifies
+func (r *RemoteProver) Prove(ctx context.Context, circuit string, w witness.Witness) (Proof, error) {
+	if _, err := readyCircuit(circuit); err != nil {
+		return nil, err
+	}
+	data, err := w.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	public, err := w.Public()
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(RemoteProveRequest{Circuit: circuit, Backend: BackendFor(circuit), Witness: data})
+	if err != nil {
+		return nil, err
+	}
+
+	start := int(r.next.Add(1))
+	var errs []error
+	for i := range r.workers {
+		worker := r.workers[(start+i)%len(r.workers)]
+		proof, err := r.proveAt(ctx, worker, circuit, body)
+		if err == nil {
+			if err = verifyWitness(circuit, proof, public); err != nil {
+				err = fmt.Errorf("returned an invalid proof: %w", err)
+			}
+		}
+		if err == nil {
+			return proof, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", worker, err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (r *RemoteProver) proveAt(ctx context.Context, worker, circuit string, body []byte) (Proof, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, worker+RemoteProvePath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out RemoteProveResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxRemoteBody)).Decode(&out); err != nil {
+		return nil, fmt.Errorf("status %d: %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, out.Error)
+	}
+
+	proof := newProof(circuit)
+	if _, err := proof.ReadFrom(bytes.NewReader(out.Proof)); err != nil {
+		return nil, fmt.Errorf("undecodable proof: %w", err)
+	}
+	return proof, nil
+}
+
+// ProverHandler serves the remote prover protocol, proving on p. When token
+// is set, requests must carry it as a bearer token.
+func ProverHandler(p *ProverService, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+RemoteProvePath, func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r, token) {
+			writeRemoteError(w, http.StatusUnauthorized, "missing or invalid prover token")
+			return
+		}
+
+		var req RemoteProveRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRemoteBody)).Decode(&req); err != nil {
+			writeRemoteError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		if _, ok := findCircuit(req.Circuit); !ok {
+			writeRemoteError(w, http.StatusBadRequest, fmt.Sprintf("unknown circuit: %s", req.Circuit))
+			return
+		}
+		if backend := BackendFor(req.Circuit); req.Backend != backend {
+			writeRemoteError(w, http.StatusConflict, fmt.Sprintf("%s circuit is proven with %s here, node asked for %s", req.Circuit, backend, req.Backend))
+			return
+		}
+		full, err := witness.New(ecc.BN254.ScalarField())
+		if err != nil {
+			writeRemoteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if err := full.UnmarshalBinary(req.Witness); err != nil {
+			writeRemoteError(w, http.StatusBadRequest, "invalid witness encoding")
+			return
+		}
+
+		proof, err := p.ProveWitness(r.Context(), req.Circuit, full)
+		switch {
+		case errors.Is(err, ErrProverQueueFull), errors.Is(err, ErrProverStopped):
+			writeRemoteError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		case r.Context().Err() != nil:
+			return
+		case err != nil:
+			writeRemoteError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		var buf bytes.Buffer
+		if _, err := proof.WriteTo(&buf); err != nil {
+			writeRemoteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(RemoteProveResponse{Proof: buf.Bytes()})
+	})
+
+	mux.HandleFunc("GET "+RemoteHealthPath, func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r, token) {
+			writeRemoteError(w, http.StatusUnauthorized, "missing or invalid prover token")
+			return
+		}
+		backends := make(map[string]Backend)
+		for _, name := range CircuitNames() {
+			backends[name] = BackendFor(name)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":      "ok",
+			"queue_depth": p.Depth(),
+			"backends":    backends,
+		})
+	})
+	return mux
+}
+
+func authorized(r *http.Request, token string) bool {
+	if token == "" {
+		return true
+	}
+	got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+func writeRemoteError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(RemoteProveResponse{Error: msg})
+}
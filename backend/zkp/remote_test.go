@@ -0,0 +1,81 @@
+package zkp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+func TestRemoteProverOffloadsAndVerifies(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	worker := NewProverService(ProverConfig{Workers: 1, QueueSize: 4})
+	go worker.Start(ctx)
+	server := httptest.NewServer(ProverHandler(worker, "prover-secret"))
+	defer server.Close()
+
+	// The first worker is down; the client moves on to the next
+	remote, err := NewRemoteProver([]string{"http://127.0.0.1:1", server.URL}, "prover-secret", 30*time.Second)
+	if err != nil {
+		t.Fatalf("NewRemoteProver failed: %v", err)
+	}
+	w, err := frontend.NewWitness(rangeAssignment(150), ecc.BN254.ScalarField())
+	if err != nil {
+		t.Fatalf("Failed to build witness: %v", err)
+	}
+	proof, err := remote.Prove(ctx, "range", w)
+	if err != nil {
+		t.Fatalf("Remote Prove failed: %v", err)
+	}
+	if ok, _ := VerifyRangeProof(proof, big.NewInt(0), big.NewInt(1000)); !ok {
+		t.Error("Remote proof does not verify")
+	}
+
+	unauthorized, _ := NewRemoteProver([]string{server.URL}, "wrong", 30*time.Second)
+	if _, err := unauthorized.Prove(ctx, "range", w); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected the worker to reject a bad token, got %v", err)
+	}
+	if _, err := NewRemoteProver([]string{"prover.internal:9090"}, "", time.Second); err == nil {
+		t.Error("Expected a worker address without a scheme to be rejected")
+	}
+}
+
+func TestProverServiceFallsBackOnInvalidRemoteProof(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A worker that answers every request with a proof of a different statement
+	bogus, err := GenerateRangeProof(big.NewInt(1), big.NewInt(0), big.NewInt(10))
+	if err != nil {
+		t.Fatalf("GenerateRangeProof failed: %v", err)
+	}
+	var buf bytes.Buffer
+	bogus.WriteTo(&buf)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(RemoteProveResponse{Proof: buf.Bytes()})
+	}))
+	defer server.Close()
+
+	remote, _ := NewRemoteProver([]string{server.URL}, "", 30*time.Second)
+	p := NewProverService(ProverConfig{Workers: 1, QueueSize: 4})
+	p.SetRemote(remote)
+	go p.Start(ctx)
+
+	proof, err := p.Prove(ctx, "range", rangeAssignment(150))
+	if err != nil {
+		t.Fatalf("Prove failed: %v", err)
+	}
+	if ok, _ := VerifyRangeProof(proof, big.NewInt(0), big.NewInt(1000)); !ok {
+		t.Error("Expected a locally generated proof after the remote proof was rejected")
+	}
+}
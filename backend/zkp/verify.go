@@ -0,0 +1,182 @@
+package zkp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// groth16SolidityLen is Ar | Bs | Krs as uint256 words, the uint256[8] proof
+const groth16SolidityLen = 8 * 32
+
+// DeserializeProof decodes the uint256[8] Groth16 proof submitted on-chain
+// (see SerializeProof). Points must be on the curve and in the right subgroup.
+func DeserializeProof(words [8]*big.Int) (Proof, error) {
+	raw := make([]byte, groth16SolidityLen)
+	for i, w := range words {
+		if w == nil || w.Sign() < 0 || w.BitLen() > 256 {
+			return nil, fmt.Errorf("proof word %d is not a uint256", i)
+		}
+		w.FillBytes(raw[i*32 : (i+1)*32])
+	}
+	return decodeGroth16Solidity(raw)
+}
+
+// DeserializeProofBytes decodes Groth16 proof bytes as returned by
+// SerializeProofBytes, including any commitments appended after the uint256[8]
+func DeserializeProofBytes(circuit string, data []byte) (Proof, error) {
+	if _, err := readyCircuit(circuit); err != nil {
+		return nil, err
+	}
+	if BackendFor(circuit) == BackendPlonk {
+		// The Solidity encoding drops the linearised polynomial opening, which
+		// only the exported verifier contract recomputes
+		return nil, fmt.Errorf("%s circuit uses plonk; its Solidity proof bytes can only be checked by the exported verifier contract", circuit)
+	}
+	return decodeGroth16Solidity(data)
+}
+
+// decodeGroth16Solidity reads Ar, Bs and Krs, and the commitments that
+// MarshalSolidity appends for circuits that use them
+func decodeGroth16Solidity(data []byte) (Proof, error) {
+	if len(data) < groth16SolidityLen {
+		return nil, fmt.Errorf("groth16 proof must be at least %d bytes, got %d", groth16SolidityLen, len(data))
+	}
+	proof := new(groth16_bn254.Proof)
+	if len(data) > groth16SolidityLen {
+		if _, err := proof.ReadFrom(bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("invalid groth16 proof: %w", err)
+		}
+		return proof, nil
+	}
+
+	dec := bn254.NewDecoder(bytes.NewReader(data))
+	for _, v := range []interface{}{&proof.Ar, &proof.Bs, &proof.Krs} {
+		if err := dec.Decode(v); err != nil {
+			return nil, fmt.Errorf("invalid groth16 proof: %w", err)
+		}
+	}
+	return proof, nil
+}
+
+// VerifyPublicInputs checks a proof against the public inputs in the order
+// the circuit's Solidity verifier takes them
+func VerifyPublicInputs(circuit string, proof Proof, inputs []*big.Int) error {
+	public, err := witness.New(ecc.BN254.ScalarField())
+	if err != nil {
+		return err
+	}
+	values := make(chan any, len(inputs))
+	for _, in := range inputs {
+		values <- in
+	}
+	close(values)
+	if err := public.Fill(len(inputs), 0, values); err != nil {
+		return fmt.Errorf("invalid public inputs: %w", err)
+	}
+	return verifyWitness(circuit, proof, public)
+}
+
+// VKFingerprint is the SHA-256 of the circuit's verifying key, as recorded in
+// its key metadata (see KeyMetadata.VKHash)
+func VKFingerprint(circuit string) (string, error) {
+	def, err := readyCircuit(circuit)
+	if err != nil {
+		return "", err
+	}
+	if keys := plonkKeysFor(circuit); keys != nil {
+		return hashOf(keys.vk)
+	}
+	return hashOf(*def.vk)
+}
+
+// VerifyRequest is a proof and its public inputs as published on-chain. Proof
+// is either the eight uint256 words of a Groth16 proof or a single hex string
+// of the backend's proof bytes; numbers are decimal or 0x-prefixed hex.
+type VerifyRequest struct {
+	Circuit      string   `json:"circuit"`
+	Proof        []string `json:"proof"`
+	PublicInputs []string `json:"public_inputs"`
+}
+
+// VerificationResult reports whether a proof verified and which key it was
+// checked against, so auditors can match it to the deployed verifier
+type VerificationResult struct {
+	Circuit       string  `json:"circuit"`
+	Backend       Backend `json:"backend"`
+	Valid         bool    `json:"valid"`
+	Error         string  `json:"error,omitempty"`
+	VKFingerprint string  `json:"vk_fingerprint"`
+}
+
+// Verify decodes and checks the request. A malformed request is an error; a
+// proof that does not verify is a result with Valid false.
+func (r VerifyRequest) Verify() (VerificationResult, error) {
+	if _, ok := findCircuit(r.Circuit); !ok {
+		return VerificationResult{}, fmt.Errorf("unknown circuit %q (expected one of %s)", r.Circuit, strings.Join(CircuitNames(), ", "))
+	}
+	fingerprint, err := VKFingerprint(r.Circuit)
+	if err != nil {
+		return VerificationResult{}, err
+	}
+	result := VerificationResult{Circuit: r.Circuit, Backend: BackendFor(r.Circuit), VKFingerprint: fingerprint}
+
+	proof, err := r.decodeProof()
+	if err != nil {
+		return result, err
+	}
+	inputs := make([]*big.Int, len(r.PublicInputs))
+	for i, s := range r.PublicInputs {
+		if inputs[i], err = parseUint256(s); err != nil {
+			return result, fmt.Errorf("public input %d: %w", i, err)
+		}
+	}
+
+	if err := VerifyPublicInputs(r.Circuit, proof, inputs); err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+	result.Valid = true
+	return result, nil
+}
+
+func (r VerifyRequest) decodeProof() (Proof, error) {
+	switch len(r.Proof) {
+	case 8:
+		if BackendFor(r.Circuit) != BackendGroth16 {
+			return nil, fmt.Errorf("%s circuit uses %s; pass the proof bytes as one hex string", r.Circuit, BackendFor(r.Circuit))
+		}
+		var words [8]*big.Int
+		for i, s := range r.Proof {
+			w, err := parseUint256(s)
+			if err != nil {
+				return nil, fmt.Errorf("proof word %d: %w", i, err)
+			}
+			words[i] = w
+		}
+		return DeserializeProof(words)
+	case 1:
+		data, err := hex.DecodeString(strings.TrimPrefix(r.Proof[0], "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("proof bytes: %w", err)
+		}
+		return DeserializeProofBytes(r.Circuit, data)
+	default:
+		return nil, fmt.Errorf("proof must be 8 uint256 words or one hex string, got %d values", len(r.Proof))
+	}
+}
+
+func parseUint256(s string) (*big.Int, error) {
+	v, ok := new(big.Int).SetString(strings.TrimSpace(s), 0)
+	if !ok || v.Sign() < 0 || v.BitLen() > 256 {
+		return nil, fmt.Errorf("%q is not a uint256", s)
+	}
+	return v, nil
+}
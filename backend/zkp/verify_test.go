@@ -0,0 +1,88 @@
+package zkp
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestDeserializeProofRoundTripsWireFormat(t *testing.T) {
+	proof, err := GenerateRangeProof(big.NewInt(150), big.NewInt(100), big.NewInt(200))
+	if err != nil {
+		t.Fatalf("GenerateRangeProof failed: %v", err)
+	}
+	words, err := SerializeProof(proof)
+	if err != nil {
+		t.Fatalf("SerializeProof failed: %v", err)
+	}
+
+	decoded, err := DeserializeProof(words)
+	if err != nil {
+		t.Fatalf("DeserializeProof failed: %v", err)
+	}
+	if ok, _ := VerifyRangeProof(decoded, big.NewInt(100), big.NewInt(200)); !ok {
+		t.Error("Decoded proof does not verify")
+	}
+
+	req := VerifyRequest{Circuit: "range", PublicInputs: []string{"100", "0xc8"}}
+	for _, w := range words {
+		req.Proof = append(req.Proof, w.String())
+	}
+	result, err := req.Verify()
+	if err != nil || !result.Valid {
+		t.Fatalf("Expected a valid result, got %+v, %v", result, err)
+	}
+	fingerprint, _ := VKFingerprint("range")
+	if result.VKFingerprint != fingerprint || len(fingerprint) != 64 {
+		t.Errorf("Expected the range key fingerprint %s, got %s", fingerprint, result.VKFingerprint)
+	}
+
+	req.PublicInputs = []string{"100", "201"}
+	if result, err := req.Verify(); err != nil || result.Valid || result.Error == "" {
+		t.Errorf("Expected an invalid result for the wrong range, got %+v, %v", result, err)
+	}
+
+	words[0] = new(big.Int).Add(words[0], big.NewInt(1))
+	if _, err := DeserializeProof(words); err == nil {
+		t.Error("Expected a point off the curve to be rejected")
+	}
+}
+
+func TestVerifyRequestAcceptsProofBytes(t *testing.T) {
+	reserve, liability := Commit(big.NewInt(10), big.NewInt(5)), Commit(big.NewInt(7), big.NewInt(2))
+	proof, err := GenerateProofOfReserves(big.NewInt(10), big.NewInt(7), big.NewInt(5), big.NewInt(2))
+	if err != nil {
+		t.Fatalf("GenerateProofOfReserves failed: %v", err)
+	}
+	raw, _ := SerializeProofBytes(proof)
+
+	result, err := VerifyRequest{
+		Circuit:      "por",
+		Proof:        []string{"0x" + hex.EncodeToString(raw)},
+		PublicInputs: []string{reserve.String(), liability.String(), "1"},
+	}.Verify()
+	if err != nil || !result.Valid {
+		t.Errorf("Expected reserves proof bytes to verify, got %+v, %v", result, err)
+	}
+
+	if _, err := (VerifyRequest{Circuit: "oracle", Proof: []string{"0x00"}}).Verify(); err == nil || !strings.Contains(err.Error(), "unknown circuit") {
+		t.Errorf("Expected an unknown circuit to be rejected, got %v", err)
+	}
+}
+
+func TestDeserializeRejectsPlonkSolidityBytes(t *testing.T) {
+	if err := LoadPlonkSRS(writeTestSRS(t, 1<<13+3)); err != nil {
+		t.Fatalf("LoadPlonkSRS failed: %v", err)
+	}
+	usePlonk(t, "vrf")
+
+	proof, err := GenerateVRFProof(big.NewInt(7), big.NewInt(5), big.NewInt(12))
+	if err != nil {
+		t.Fatalf("PLONK proof failed: %v", err)
+	}
+	raw, _ := SerializeProofBytes(proof)
+	if _, err := DeserializeProofBytes("vrf", raw); err == nil || !strings.Contains(err.Error(), "exported verifier") {
+		t.Errorf("Expected PLONK Solidity bytes to be refused, got %v", err)
+	}
+}
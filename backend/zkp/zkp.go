@@ -1,17 +1,16 @@
 package zkp
 
 import (
-	"fmt"
+	"bytes"
+	"context"
+	"errors"
 	"math/big"
 	"os"
 	"sync"
 
-	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend/groth16"
-	gnarkproof "github.com/consensys/gnark/backend/groth16/bn254"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/frontend/cs/r1cs"
 )
 
 // RangeProofCircuit proves Value is in [Min, Max]
@@ -27,19 +26,6 @@ func (circuit *RangeProofCircuit) Define(api frontend.API) error {
 	return nil
 }
 
-// BridgeProofCircuit proves a message has been processed correctly for cross-chain relay
-type BridgeProofCircuit struct {
-	MessageHash frontend.Variable `gnark:",public"`
-	OriginChain frontend.Variable `gnark:",public"`
-	SecretKey   frontend.Variable `gnark:",secret"`
-}
-
-func (circuit *BridgeProofCircuit) Define(api frontend.API) error {
-	// Simple validity check (Logic placeholder for production MiMC/Poseidon hash)
-	api.AssertIsEqual(circuit.MessageHash, api.Add(circuit.OriginChain, circuit.SecretKey))
-	return nil
-}
-
 // VRFCircuit proves randomness = Hash(SecretKey, Seed)
 type VRFCircuit struct {
 	SecretKey  frontend.Variable `gnark:",secret"`
@@ -54,16 +40,21 @@ func (circuit *VRFCircuit) Define(api frontend.API) error {
 	return nil
 }
 
-// PrivateComputationCircuit proves SecretValue matches a specific logic (e.g., above threshold)
+// PrivateComputationCircuit proves whether a secret result is at least a
+// public threshold. Satisfied is 1 when SecretValue >= Threshold and 0
+// otherwise, so either outcome is posted with a proof; greater-or-equal is the
+// only comparison supported.
 type PrivateComputationCircuit struct {
 	SecretValue frontend.Variable `gnark:",secret"`
 	Threshold   frontend.Variable `gnark:",public"`
-	LogicType   frontend.Variable `gnark:",public"` // 0: GreaterThan, 1: LessThan, 2: Equal
+	Satisfied   frontend.Variable `gnark:",public"`
 }
 
 func (circuit *PrivateComputationCircuit) Define(api frontend.API) error {
-	// Simplified branching logic for Phase 2 MVP
-	api.AssertIsLessOrEqual(circuit.Threshold, circuit.SecretValue)
+	api.AssertIsBoolean(circuit.Satisfied)
+	// Cmp is -1 only when SecretValue < Threshold
+	below := api.IsZero(api.Add(api.Cmp(circuit.SecretValue, circuit.Threshold), 1))
+	api.AssertIsEqual(circuit.Satisfied, api.Sub(1, below))
 	return nil
 }
 
@@ -74,151 +65,91 @@ var (
 	rangeCCS, vrfCCS, bridgeCCS, privateCCS            constraint.ConstraintSystem
 )
 
-// Init sets up the proving system (Trusted Setup simulation)
+// coreCircuits are the circuits used by the node's built-in job types
+var coreCircuits = []circuitDef{
+	{"range", 1, func() frontend.Circuit { return &RangeProofCircuit{} }, &rangeCCS, &rangePK, &rangeVK},
+	{"vrf", 1, func() frontend.Circuit { return &VRFCircuit{} }, &vrfCCS, &vrfPK, &vrfVK},
+	{"bridge", 1, func() frontend.Circuit { return &BridgeProofCircuit{} }, &bridgeCCS, &bridgePK, &bridgeVK},
+	{"private", 2, func() frontend.Circuit { return &PrivateComputationCircuit{} }, &privateCCS, &privatePK, &privateVK},
+}
+
+// Init compiles the core circuits and loads their keys from the key directory
+// (see SetKeyDir), falling back to a fresh trusted setup when none exist
 func Init() error {
 	var err error
 	once.Do(func() {
-		// 1. Range Proof
-		var rCircuit RangeProofCircuit
-		rangeCCS, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &rCircuit)
-		if err != nil { return }
-		rangePK, rangeVK, err = groth16.Setup(rangeCCS)
-		if err != nil { return }
-
-		// 2. VRF Proof
-		var vCircuit VRFCircuit
-		vrfCCS, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &vCircuit)
-		if err != nil { return }
-		vrfPK, vrfVK, err = groth16.Setup(vrfCCS)
-		if err != nil { return }
-
-		// 3. Bridge Proof
-		var bCircuit BridgeProofCircuit
-		bridgeCCS, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &bCircuit)
-		if err != nil { return }
-		bridgePK, bridgeVK, err = groth16.Setup(bridgeCCS)
-		if err != nil { return }
-
-		// 4. Private Computation Proof
-		var pCircuit PrivateComputationCircuit
-		privateCCS, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &pCircuit)
-		if err != nil { return }
-		privatePK, privateVK, err = groth16.Setup(privateCCS)
+		for _, def := range coreCircuits {
+			if err = setupCircuit(def); err != nil {
+				return
+			}
+		}
 	})
 	return err
 }
 
 // GenerateRangeProof creates a ZK proof for the given values
-func GenerateRangeProof(value, min, max *big.Int) (groth16.Proof, error) {
-	if rangeCCS == nil {
-		if err := Init(); err != nil {
-			return nil, err
-		}
-	}
+func GenerateRangeProof(value, min, max *big.Int) (Proof, error) {
+	return GenerateRangeProofContext(context.Background(), value, min, max)
+}
 
-	witness, err := frontend.NewWitness(&RangeProofCircuit{
+// GenerateRangeProofContext is GenerateRangeProof, giving up when ctx is done
+func GenerateRangeProofContext(ctx context.Context, value, min, max *big.Int) (Proof, error) {
+	return ProveContext(ctx, "range", &RangeProofCircuit{
 		Value: value,
 		Min:   min,
 		Max:   max,
-	}, ecc.BN254.ScalarField())
-	if err != nil {
-		return nil, err
-	}
-
-	proof, err := groth16.Prove(rangeCCS, rangePK, witness)
-	if err != nil {
-		return nil, err
-	}
-
-	return proof, nil
+	})
 }
 
-// VerifyRangeProof verifies a ZK proof for the given public inputs [Min, Max]
-func VerifyRangeProof(proof groth16.Proof, min, max *big.Int) (bool, error) {
-	if rangeVK == nil {
-		if err := Init(); err != nil {
-			return false, err
-		}
-	}
-
-	publicWitness, err := frontend.NewWitness(&RangeProofCircuit{
+// VerifyRangeProof verifies a ZK proof for the given public inputs [Min, Max].
+// A proof that does not verify is reported with the reason it failed.
+func VerifyRangeProof(proof Proof, min, max *big.Int) (bool, error) {
+	err := Verify("range", proof, &RangeProofCircuit{
 		Min: min,
 		Max: max,
-	}, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	})
 	if err != nil {
 		return false, err
 	}
-
-	err = groth16.Verify(proof, rangeVK, publicWitness)
-	return err == nil, nil
+	return true, nil
 }
 
 // GenerateVRFProof creates a ZK proof for randomness generation
-func GenerateVRFProof(secretKey, seed, randomness *big.Int) (groth16.Proof, error) {
-	if vrfCCS == nil {
-		if err := Init(); err != nil {
-			return nil, err
-		}
-	}
-
-	witness, err := frontend.NewWitness(&VRFCircuit{
+func GenerateVRFProof(secretKey, seed, randomness *big.Int) (Proof, error) {
+	return Prove("vrf", &VRFCircuit{
 		SecretKey:  secretKey,
 		Seed:       seed,
 		Randomness: randomness,
-	}, ecc.BN254.ScalarField())
-	if err != nil {
-		return nil, err
-	}
-
-	return groth16.Prove(vrfCCS, vrfPK, witness)
+	})
 }
 
-// GenerateBridgeProof creates a ZK proof for cross-chain message relay
-func GenerateBridgeProof(msgHash, originChain, secretKey *big.Int) (groth16.Proof, error) {
-	if bridgeCCS == nil {
-		if err := Init(); err != nil {
-			return nil, err
-		}
-	}
-
-	witness, err := frontend.NewWitness(&BridgeProofCircuit{
-		MessageHash: msgHash,
-		OriginChain: originChain,
-		SecretKey:   secretKey,
-	}, ecc.BN254.ScalarField())
-	if err != nil {
-		return nil, err
-	}
-
-	return groth16.Prove(bridgeCCS, bridgePK, witness)
+// GeneratePrivateComputationProof proves whether secret >= threshold; the
+// outcome is the public input after the threshold
+func GeneratePrivateComputationProof(secret, threshold *big.Int) (Proof, error) {
+	return GeneratePrivateComputationProofContext(context.Background(), secret, threshold)
 }
 
-// GeneratePrivateComputationProof creates a ZK proof for confidential data processing
-func GeneratePrivateComputationProof(secret, threshold *big.Int, logicType int) (groth16.Proof, error) {
-	if privateCCS == nil {
-		if err := Init(); err != nil {
-			return nil, err
-		}
+// GeneratePrivateComputationProofContext is GeneratePrivateComputationProof,
+// giving up when ctx is done
+func GeneratePrivateComputationProofContext(ctx context.Context, secret, threshold *big.Int) (Proof, error) {
+	satisfied := 0
+	if secret.Cmp(threshold) >= 0 {
+		satisfied = 1
 	}
-
-	witness, err := frontend.NewWitness(&PrivateComputationCircuit{
+	return ProveContext(ctx, "private", &PrivateComputationCircuit{
 		SecretValue: secret,
 		Threshold:   threshold,
-		LogicType:   logicType,
-	}, ecc.BN254.ScalarField())
-	if err != nil {
-		return nil, err
-	}
-
-	return groth16.Prove(privateCCS, privatePK, witness)
+		Satisfied:   satisfied,
+	})
 }
 
-// SerializeProof converts Groth16 proof to Solidity-compatible uint256[8]
-func SerializeProof(proof groth16.Proof) ([8]*big.Int, error) {
+// SerializeProof converts Groth16 proof to Solidity-compatible uint256[8].
+// PLONK proofs are variable length; see SerializeProofBytes.
+func SerializeProof(proof Proof) ([8]*big.Int, error) {
 	var res [8]*big.Int
-	p, ok := proof.(*gnarkproof.Proof)
-	if !ok {
-		return res, fmt.Errorf("invalid proof type")
+	p, err := groth16Proof(proof)
+	if err != nil {
+		return res, err
 	}
 
 	res[0] = p.Ar.X.BigInt(new(big.Int))
@@ -233,17 +164,33 @@ func SerializeProof(proof groth16.Proof) ([8]*big.Int, error) {
 	return res, nil
 }
 
-// ExportSolidityContract generates the Verifier.sol file
-func ExportSolidityContract(path string) error {
-	if rangeVK == nil {
-		if err := Init(); err != nil {
-			return err
-		}
-	}
-	f, err := os.Create(path)
+// SerializeProofCommitments returns the commitment arguments that the
+// exported Groth16 verifier of a circuit with commitments takes after the
+// uint256[8] proof: uint256[2*n] commitments and uint256[2] commitmentPok
+func SerializeProofCommitments(proof Proof) ([]*big.Int, [2]*big.Int, error) {
+	var pok [2]*big.Int
+	p, err := groth16Proof(proof)
 	if err != nil {
+		return nil, pok, err
+	}
+	if len(p.Commitments) == 0 {
+		return nil, pok, errors.New("proof has no commitments")
+	}
+
+	commitments := make([]*big.Int, 0, 2*len(p.Commitments))
+	for _, c := range p.Commitments {
+		commitments = append(commitments, c.X.BigInt(new(big.Int)), c.Y.BigInt(new(big.Int)))
+	}
+	pok[0] = p.CommitmentPok.X.BigInt(new(big.Int))
+	pok[1] = p.CommitmentPok.Y.BigInt(new(big.Int))
+	return commitments, pok, nil
+}
+
+// ExportSolidityContract writes the Verifier.sol of a circuit's configured backend
+func ExportSolidityContract(circuit, path string) error {
+	var buf bytes.Buffer
+	if err := ExportVerifier(circuit, &buf); err != nil {
 		return err
 	}
-	defer f.Close()
-	return rangeVK.ExportSolidity(f)
+	return os.WriteFile(path, buf.Bytes(), 0o644)
 }
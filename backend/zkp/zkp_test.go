@@ -3,6 +3,9 @@ package zkp
 import (
 	"math/big"
 	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
 )
 
 func TestRangeProofGeneration(t *testing.T) {
@@ -114,24 +117,23 @@ func TestVRFProofGeneration(t *testing.T) {
 	t.Log("✅ VRF proof generation successful")
 }
 
-func TestBridgeProofGeneration(t *testing.T) {
-	if err := Init(); err != nil {
-		t.Fatalf("Failed to initialize ZKP: %v", err)
-	}
-
-	originChain := big.NewInt(1) // Ethereum
-	secretKey := big.NewInt(99999)
-	// For simplified bridge circuit: messageHash = originChain + secretKey
-	msgHash := new(big.Int).Add(originChain, secretKey)
-
-	proof, err := GenerateBridgeProof(msgHash, originChain, secretKey)
-	if err != nil {
-		t.Fatalf("Failed to generate bridge proof: %v", err)
-	}
-
-	if proof == nil {
-		t.Fatal("Bridge proof is nil")
+func TestPrivateComputationProvesEitherOutcome(t *testing.T) {
+	cases := []struct {
+		value, threshold, satisfied int
+		solved                      bool
+	}{
+		{750, 700, 1, true},
+		{700, 700, 1, true},
+		{650, 700, 0, true},
+		{650, 700, 1, false},
+		{750, 700, 0, false},
+		{750, 700, 2, false},
+	}
+	for _, c := range cases {
+		assignment := &PrivateComputationCircuit{SecretValue: c.value, Threshold: c.threshold, Satisfied: c.satisfied}
+		err := test.IsSolved(&PrivateComputationCircuit{}, assignment, ecc.BN254.ScalarField())
+		if (err == nil) != c.solved {
+			t.Errorf("value %d, threshold %d, satisfied %d: expected solved=%v, got %v", c.value, c.threshold, c.satisfied, c.solved, err)
+		}
 	}
-
-	t.Log("✅ Bridge proof generation successful")
 }
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "@openzeppelin/contracts/access/AccessControl.sol";
+import "@openzeppelin/contracts/utils/Pausable.sol";
+import "@openzeppelin/contracts/utils/ReentrancyGuard.sol";
+
+interface IBridgeVerifier {
+    function verifyProof(
+        uint256[8] calldata proof,
+        uint256[6] calldata input
+    ) external view;
+}
+
+interface IObscuraReceiver {
+    function onObscuraMessage(
+        uint64 sourceChain,
+        address sender,
+        bytes calldata payload
+    ) external;
+}
+
+/// @title ObscuraBridge
+/// @notice Bridge endpoint deployed on every chain CrossLink relays between.
+/// Outgoing messages are emitted as BridgeMessage events; incoming messages are
+/// accepted with a proof that they belong to a batch root signed by a quorum
+/// of the Obscura bridge committee.
+contract ObscuraBridge is AccessControl, Pausable, ReentrancyGuard {
+    bytes32 public constant ADMIN_ROLE = keccak256("ADMIN_ROLE");
+
+    // BN254 scalar field; public inputs of the bridge circuit live below it
+    uint256 internal constant SNARK_SCALAR_FIELD =
+        21888242871839275222246405745257275088548364400416034343698204186575808495617;
+
+    IBridgeVerifier public verifier;
+
+    // MiMC root of the committee members' EdDSA public keys
+    uint256 public committeeRoot;
+
+    uint64 public nextNonce;
+
+    // sourceChain => nonce => delivered
+    mapping(uint64 => mapping(uint64 => bool)) public delivered;
+
+    event BridgeMessage(
+        uint64 indexed nonce,
+        uint64 indexed targetChain,
+        address indexed sender,
+        address receiver,
+        bytes payload
+    );
+    event MessageDelivered(
+        uint64 indexed sourceChain,
+        uint64 indexed nonce,
+        address indexed receiver,
+        bool success
+    );
+    event CommitteeRootUpdated(uint256 oldRoot, uint256 newRoot);
+    event VerifierUpdated(address oldVerifier, address newVerifier);
+
+    constructor(address _verifier, uint256 _committeeRoot) {
+        require(_verifier != address(0), "Verifier required");
+        require(_committeeRoot < SNARK_SCALAR_FIELD, "Root outside field");
+        verifier = IBridgeVerifier(_verifier);
+        committeeRoot = _committeeRoot;
+        _grantRole(DEFAULT_ADMIN_ROLE, msg.sender);
+        _grantRole(ADMIN_ROLE, msg.sender);
+    }
+
+    /// @notice Emit a message for the relayers to deliver to targetChain
+    function sendMessage(
+        uint64 targetChain,
+        address receiver,
+        bytes calldata payload
+    ) external whenNotPaused returns (uint64 nonce) {
+        require(targetChain != block.chainid, "Target is this chain");
+        require(receiver != address(0), "Invalid receiver");
+
+        nonce = nextNonce++;
+        emit BridgeMessage(nonce, targetChain, msg.sender, receiver, payload);
+    }
+
+    /// @notice Deliver a message proven to be in a committee-signed batch.
+    /// The receiver's failure does not revert delivery, so a message cannot
+    /// be retried into a different outcome.
+    function receiveMessage(
+        uint64 sourceChain,
+        uint64 nonce,
+        address sender,
+        address receiver,
+        bytes calldata payload,
+        uint256 batchRoot,
+        uint256 _committeeRoot,
+        uint256[8] calldata proof
+    ) external whenNotPaused nonReentrant {
+        require(_committeeRoot == committeeRoot, "Unknown committee");
+        require(!delivered[sourceChain][nonce], "Already delivered");
+
+        // Same order as the public inputs of zkp.BridgeProofCircuit
+        uint256[6] memory input = [
+            batchRoot,
+            _committeeRoot,
+            uint256(sourceChain),
+            block.chainid,
+            uint256(nonce),
+            payloadHash(sender, receiver, payload)
+        ];
+        verifier.verifyProof(proof, input);
+
+        delivered[sourceChain][nonce] = true;
+
+        (bool success, ) = receiver.call(
+            abi.encodeCall(
+                IObscuraReceiver.onObscuraMessage,
+                (sourceChain, sender, payload)
+            )
+        );
+        emit MessageDelivered(sourceChain, nonce, receiver, success);
+    }
+
+    /// @notice The field element the committee's batch leaf commits to; it
+    /// binds the sender and receiver as well as the payload
+    function payloadHash(
+        address sender,
+        address receiver,
+        bytes calldata payload
+    ) public pure returns (uint256) {
+        return
+            uint256(keccak256(abi.encode(sender, receiver, payload))) %
+            SNARK_SCALAR_FIELD;
+    }
+
+    // --- Admin Functions ---
+
+    function setCommitteeRoot(uint256 _root) external onlyRole(ADMIN_ROLE) {
+        require(_root < SNARK_SCALAR_FIELD, "Root outside field");
+        emit CommitteeRootUpdated(committeeRoot, _root);
+        committeeRoot = _root;
+    }
+
+    function setVerifier(address _verifier) external onlyRole(ADMIN_ROLE) {
+        require(_verifier != address(0), "Verifier required");
+        emit VerifierUpdated(address(verifier), _verifier);
+        verifier = IBridgeVerifier(_verifier);
+    }
+
+    function pause() external onlyRole(ADMIN_ROLE) {
+        _pause();
+    }
+
+    function unpause() external onlyRole(ADMIN_ROLE) {
+        _unpause();
+    }
+}
@@ -32,6 +32,17 @@ interface IVerifier {
     ) external view;
 }
 
+// Exported verifier of zkp.AggregationCircuit; its keccak gadget makes gnark
+// add a commitment and its proof of knowledge to the Groth16 proof
+interface IAggregationVerifier {
+    function verifyProof(
+        uint256[8] calldata proof,
+        uint256[2] calldata commitments,
+        uint256[2] calldata commitmentPok,
+        uint256[17] calldata input
+    ) external view;
+}
+
 contract ObscuraOracle is AccessControl, Pausable, ReentrancyGuard {
     bytes32 public constant ADMIN_ROLE = keccak256("ADMIN_ROLE");
     bytes32 public constant SLASHER_ROLE = keccak256("SLASHER_ROLE");
@@ -39,6 +50,9 @@ contract ObscuraOracle is AccessControl, Pausable, ReentrancyGuard {
     IERC20 public obscuraToken;
     IStakeGuard public stakeGuard;
     IVerifier public verifier;
+    IAggregationVerifier public aggregationVerifier;
+    // Exported verifier of zkp.PrivateComputationCircuit
+    IVerifier public computeVerifier;
 
     // Configuration
     uint256 public paymentFee = 1 * 10 ** 18; // 1 OBSCURA per request
@@ -47,6 +61,11 @@ contract ObscuraOracle is AccessControl, Pausable, ReentrancyGuard {
     uint256 public constant REWARD_PERCENT = 90; // 90% goes to nodes
     uint256 public constant MAX_DEVIATION = 50; // 50% max deviation
     uint256 public constant SLASH_AMOUNT = 10 * 10 ** 18; // 10 OBSCURA penalty
+    uint256 public constant AGGREGATION_BATCH_SIZE = 8; // zkp.AggregationBatchSize
+
+    // BN254 scalar field; public inputs of the circuits live below it
+    uint256 internal constant SNARK_SCALAR_FIELD =
+        21888242871839275222246405745257275088548364400416034343698204186575808495617;
 
     mapping(address => bool) public whitelistedNodes;
     mapping(address => uint256) public nodeRewards;
@@ -93,6 +112,8 @@ contract ObscuraOracle is AccessControl, Pausable, ReentrancyGuard {
 
     uint256 public nextRequestId;
     mapping(uint256 => Request) public requests;
+    // Compute requests are settled by fulfillCompute and never become rounds
+    mapping(uint256 => bool) public isComputeRequest;
 
     event RequestData(
         uint256 indexed requestId,
@@ -135,6 +156,21 @@ contract ObscuraOracle is AccessControl, Pausable, ReentrancyGuard {
     );
     event RandomnessFulfilled(uint256 indexed requestId, uint256 randomness);
 
+    event ComputeRequested(
+        uint256 indexed requestId,
+        bytes32 functionHash,
+        string[] inputUrls,
+        string[] inputPaths,
+        uint8 decimals,
+        uint256 threshold,
+        address indexed requester
+    );
+    event ComputeFulfilled(
+        uint256 indexed requestId,
+        bool satisfied,
+        address indexed node
+    );
+
     struct RandomnessRequest {
         string seed;
         address requester;
@@ -172,6 +208,18 @@ contract ObscuraOracle is AccessControl, Pausable, ReentrancyGuard {
         verifier = IVerifier(_verifier);
     }
 
+    function setAggregationVerifier(
+        address _verifier
+    ) external onlyRole(ADMIN_ROLE) {
+        aggregationVerifier = IAggregationVerifier(_verifier);
+    }
+
+    function setComputeVerifier(
+        address _verifier
+    ) external onlyRole(ADMIN_ROLE) {
+        computeVerifier = IVerifier(_verifier);
+    }
+
     function setNodeWhitelist(
         address _node,
         bool _status
@@ -254,6 +302,52 @@ contract ObscuraOracle is AccessControl, Pausable, ReentrancyGuard {
         return requestId;
     }
 
+    /**
+     * @notice Confidential compute: runs the registered WASM function whose
+     * module hashes to functionHash over private inputs. A node settles it
+     * through fulfillCompute with whether the result is at least threshold;
+     * the result itself is never posted.
+     * @param functionHash SHA-256 of the WASM module
+     */
+    function requestCompute(
+        bytes32 functionHash,
+        string[] calldata inputUrls,
+        string[] calldata inputPaths,
+        uint8 decimals,
+        uint256 threshold,
+        string calldata metadata
+    ) external whenNotPaused nonReentrant returns (uint256) {
+        require(functionHash != bytes32(0), "Invalid function hash");
+        require(
+            inputUrls.length == inputPaths.length && inputUrls.length <= 16,
+            "Invalid inputs"
+        );
+        require(
+            obscuraToken.transferFrom(msg.sender, address(this), paymentFee),
+            "Fee payment failed"
+        );
+
+        uint256 requestId = nextRequestId++;
+        Request storage req = requests[requestId];
+        req.id = requestId;
+        req.requester = msg.sender;
+        req.createdAt = block.timestamp;
+        req.minThreshold = threshold;
+        req.metadata = metadata;
+        isComputeRequest[requestId] = true;
+
+        emit ComputeRequested(
+            requestId,
+            functionHash,
+            inputUrls,
+            inputPaths,
+            decimals,
+            threshold,
+            msg.sender
+        );
+        return requestId;
+    }
+
     function fulfillData(
         uint256 requestId,
         uint256 value,
@@ -280,11 +374,109 @@ contract ObscuraOracle is AccessControl, Pausable, ReentrancyGuard {
         _fulfillDataInternal(requestId, value, zkpProof, publicInputs, oevBid);
     }
 
+    /**
+     * @notice Fulfill up to AGGREGATION_BATCH_SIZE requests with one
+     * aggregated proof that every value lies within its request's
+     * [minThreshold, maxThreshold]. Requests resolved or already answered by
+     * the sender in the meantime are skipped.
+     */
+    function fulfillDataBatch(
+        uint256[] calldata requestIds,
+        uint256[] calldata values,
+        uint256[8] calldata zkpProof,
+        uint256[2] calldata commitments,
+        uint256[2] calldata commitmentPok
+    ) external whenNotPaused nonReentrant {
+        require(
+            address(aggregationVerifier) != address(0),
+            "Batching disabled"
+        );
+        _requireActiveNode();
+
+        aggregationVerifier.verifyProof(
+            zkpProof,
+            commitments,
+            commitmentPok,
+            batchPublicInputs(requestIds, values)
+        );
+
+        for (uint256 i = 0; i < requestIds.length; i++) {
+            Request storage req = requests[requestIds[i]];
+            if (req.resolved || req.hasResponded[msg.sender]) {
+                continue;
+            }
+            _recordResponse(requestIds[i], values[i]);
+        }
+    }
+
+    /**
+     * @notice Settle a compute request with a private-circuit proof of
+     * whether the function's result is at least the request's threshold. The
+     * threshold is taken from the request, so a proof for any other threshold
+     * fails. The outcome is recorded as finalValue 1 or 0 and is not a price
+     * round.
+     */
+    function fulfillCompute(
+        uint256 requestId,
+        bool satisfied,
+        uint256[8] calldata zkpProof
+    ) external whenNotPaused nonReentrant {
+        require(address(computeVerifier) != address(0), "Compute disabled");
+        _requireActiveNode();
+        require(isComputeRequest[requestId], "Not a compute request");
+
+        Request storage req = requests[requestId];
+        require(!req.resolved, "Request already resolved");
+
+        uint256 outcome = satisfied ? 1 : 0;
+        computeVerifier.verifyProof(zkpProof, [req.minThreshold, outcome]);
+
+        req.responses.push(Response({node: msg.sender, value: outcome}));
+        req.hasResponded[msg.sender] = true;
+        req.finalValue = outcome;
+        req.resolved = true;
+        nodeRewards[msg.sender] += (paymentFee * REWARD_PERCENT) / 100;
+
+        emit ComputeFulfilled(requestId, satisfied, msg.sender);
+        emit RequestFulfilled(requestId, outcome);
+    }
+
+    /**
+     * @notice Public inputs of an aggregated proof over a batch: the values
+     * hash, then each request's thresholds. Short batches are padded with
+     * their last entry, as the node pads the proof.
+     */
+    function batchPublicInputs(
+        uint256[] calldata requestIds,
+        uint256[] calldata values
+    ) public view returns (uint256[17] memory input) {
+        uint256 n = requestIds.length;
+        require(
+            n > 0 && n <= AGGREGATION_BATCH_SIZE && values.length == n,
+            "Invalid batch"
+        );
+
+        uint256[AGGREGATION_BATCH_SIZE] memory padded;
+        for (uint256 i = 0; i < AGGREGATION_BATCH_SIZE; i++) {
+            uint256 j = i < n ? i : n - 1;
+            require(!isComputeRequest[requestIds[j]], "Use fulfillCompute");
+            Request storage req = requests[requestIds[j]];
+            padded[i] = values[j];
+            input[1 + 2 * i] = req.minThreshold;
+            input[2 + 2 * i] = req.maxThreshold;
+        }
+        // Same commitment as zkp.AggregationCircuit.ValuesHash
+        input[0] =
+            uint256(keccak256(abi.encodePacked(padded))) %
+            SNARK_SCALAR_FIELD;
+    }
+
     function fulfillDataOptimistic(
         uint256 requestId,
         uint256 value
     ) external whenNotPaused nonReentrant {
         require(whitelistedNodes[msg.sender], "Not whitelisted");
+        require(!isComputeRequest[requestId], "Use fulfillCompute");
         Request storage req = requests[requestId];
         require(!req.isOptimistic && req.finalValue == 0, "Already fulfilled");
 
@@ -370,10 +562,9 @@ contract ObscuraOracle is AccessControl, Pausable, ReentrancyGuard {
         uint256 /* oevBid */
     ) internal {
         // 1. Authorization Check
-        require(whitelistedNodes[msg.sender], "Not whitelisted");
-        (, , , bool isActive) = stakeGuard.stakers(msg.sender);
-        require(isActive, "Node not active in StakeGuard");
+        _requireActiveNode();
 
+        require(!isComputeRequest[requestId], "Use fulfillCompute");
         Request storage req = requests[requestId];
         require(!req.resolved, "Request already resolved");
         require(!req.hasResponded[msg.sender], "Already responded");
@@ -383,13 +574,23 @@ contract ObscuraOracle is AccessControl, Pausable, ReentrancyGuard {
             verifier.verifyProof(zkpProof, publicInputs);
         }
 
-        // 3. Record Response
+        // 3. Record Response and try to aggregate
+        _recordResponse(requestId, value);
+    }
+
+    function _requireActiveNode() internal view {
+        require(whitelistedNodes[msg.sender], "Not whitelisted");
+        (, , , bool isActive) = stakeGuard.stakers(msg.sender);
+        require(isActive, "Node not active in StakeGuard");
+    }
+
+    function _recordResponse(uint256 requestId, uint256 value) internal {
+        Request storage req = requests[requestId];
         req.responses.push(Response({node: msg.sender, value: value}));
         req.hasResponded[msg.sender] = true;
 
         emit DataSubmitted(requestId, msg.sender, value);
 
-        // 4. Try to Aggregate
         if (req.responses.length >= minResponses) {
             _aggregateAndFinalize(requestId);
         }
@@ -485,6 +686,7 @@ contract ObscuraOracle is AccessControl, Pausable, ReentrancyGuard {
 
     // Force finalize if stuck (by admin)
     function forceFinalize(uint256 requestId) external onlyRole(ADMIN_ROLE) {
+        require(!isComputeRequest[requestId], "Use fulfillCompute");
         _aggregateAndFinalize(requestId);
     }
 
@@ -0,0 +1,19 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+/// @notice Records the last message ObscuraBridge delivered to it
+contract MockReceiver {
+    uint64 public lastSourceChain;
+    address public lastSender;
+    bytes public lastPayload;
+
+    function onObscuraMessage(
+        uint64 sourceChain,
+        address sender,
+        bytes calldata payload
+    ) external {
+        lastSourceChain = sourceChain;
+        lastSender = sender;
+        lastPayload = payload;
+    }
+}
@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+/// @notice Test double for the exported Groth16 verifiers. It answers any
+/// verifyProof(uint256[8], uint256[N]) call and reverts while rejecting, or
+/// when the words after the proof do not hash to expectedInputs once set.
+contract MockVerifier {
+    bool public rejecting;
+    bytes32 public expectedInputs;
+
+    function setRejecting(bool _rejecting) external {
+        rejecting = _rejecting;
+    }
+
+    function setExpectedInputs(bytes32 _expected) external {
+        expectedInputs = _expected;
+    }
+
+    fallback() external {
+        require(!rejecting, "ProofInvalid");
+        if (expectedInputs != bytes32(0)) {
+            // Selector and eight proof words
+            require(keccak256(msg.data[260:]) == expectedInputs, "ProofInvalid");
+        }
+    }
+}
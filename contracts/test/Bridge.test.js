@@ -0,0 +1,90 @@
+const { expect } = require("chai");
+const { ethers } = require("hardhat");
+
+describe("ObscuraBridge", function () {
+    let bridge, verifier, receiver;
+    let owner, sender, relayer;
+    const COMMITTEE_ROOT = 12345n;
+    const SOURCE_CHAIN = 1n;
+    const PROOF = Array(8).fill(0n);
+    const PAYLOAD = ethers.toUtf8Bytes("mint 10");
+
+    beforeEach(async function () {
+        [owner, sender, relayer] = await ethers.getSigners();
+
+        const MockVerifier = await ethers.getContractFactory("MockVerifier");
+        verifier = await MockVerifier.deploy();
+        await verifier.waitForDeployment();
+
+        const MockReceiver = await ethers.getContractFactory("MockReceiver");
+        receiver = await MockReceiver.deploy();
+        await receiver.waitForDeployment();
+
+        const ObscuraBridge = await ethers.getContractFactory("ObscuraBridge");
+        bridge = await ObscuraBridge.deploy(await verifier.getAddress(), COMMITTEE_ROOT);
+        await bridge.waitForDeployment();
+    });
+
+    function deliver(nonce, root = COMMITTEE_ROOT) {
+        return bridge.connect(relayer).receiveMessage(
+            SOURCE_CHAIN, nonce, sender.address, receiver.getAddress(), PAYLOAD, 99n, root, PROOF
+        );
+    }
+
+    it("Should emit BridgeMessage with increasing nonces", async function () {
+        const to = await receiver.getAddress();
+        await expect(bridge.connect(sender).sendMessage(42161, to, PAYLOAD))
+            .to.emit(bridge, "BridgeMessage")
+            .withArgs(0, 42161, sender.address, to, ethers.hexlify(PAYLOAD));
+        await expect(bridge.connect(sender).sendMessage(42161, to, PAYLOAD))
+            .to.emit(bridge, "BridgeMessage")
+            .withArgs(1, 42161, sender.address, to, ethers.hexlify(PAYLOAD));
+    });
+
+    it("Should reject messages to its own chain", async function () {
+        const { chainId } = await ethers.provider.getNetwork();
+        await expect(bridge.sendMessage(chainId, await receiver.getAddress(), PAYLOAD))
+            .to.be.revertedWith("Target is this chain");
+    });
+
+    it("Should deliver a proven message once", async function () {
+        await expect(deliver(7))
+            .to.emit(bridge, "MessageDelivered")
+            .withArgs(SOURCE_CHAIN, 7, await receiver.getAddress(), true);
+
+        expect(await receiver.lastSourceChain()).to.equal(SOURCE_CHAIN);
+        expect(await receiver.lastSender()).to.equal(sender.address);
+        expect(await receiver.lastPayload()).to.equal(ethers.hexlify(PAYLOAD));
+        expect(await bridge.delivered(SOURCE_CHAIN, 7)).to.equal(true);
+
+        await expect(deliver(7)).to.be.revertedWith("Already delivered");
+    });
+
+    it("Should reject unknown committees and invalid proofs", async function () {
+        await expect(deliver(1, 777n)).to.be.revertedWith("Unknown committee");
+
+        await verifier.setRejecting(true);
+        await expect(deliver(1)).to.be.revertedWith("ProofInvalid");
+        expect(await bridge.delivered(SOURCE_CHAIN, 1)).to.equal(false);
+    });
+
+    it("Should bind sender and receiver into the payload hash", async function () {
+        const to = await receiver.getAddress();
+        const encoded = ethers.AbiCoder.defaultAbiCoder().encode(
+            ["address", "address", "bytes"], [sender.address, to, PAYLOAD]
+        );
+        const field = 21888242871839275222246405745257275088548364400416034343698204186575808495617n;
+        const expected = BigInt(ethers.keccak256(encoded)) % field;
+
+        expect(await bridge.payloadHash(sender.address, to, PAYLOAD)).to.equal(expected);
+        expect(await bridge.payloadHash(relayer.address, to, PAYLOAD)).to.not.equal(expected);
+    });
+
+    it("Should only let admins rotate the committee", async function () {
+        await expect(bridge.connect(relayer).setCommitteeRoot(1n)).to.be.reverted;
+        await expect(bridge.setCommitteeRoot(1n))
+            .to.emit(bridge, "CommitteeRootUpdated")
+            .withArgs(COMMITTEE_ROOT, 1n);
+        await expect(deliver(3)).to.be.revertedWith("Unknown committee");
+    });
+});
@@ -62,6 +62,19 @@ describe("ObscuraOracle Production Logic", function () {
         expect(startBal - endBal).to.equal(PAYMENT_FEE);
     });
 
+    it("Should emit compute requests referencing the function hash", async function () {
+        const functionHash = ethers.sha256("0x0061736d01000000");
+        await expect(oracle.connect(requester).requestCompute(
+            functionHash, ["bank.com/balance"], ["checking"], 2, 200000, "meta"
+        ))
+            .to.emit(oracle, "ComputeRequested")
+            .withArgs(0, functionHash, ["bank.com/balance"], ["checking"], 2, 200000, requester.address);
+
+        await expect(oracle.connect(requester).requestCompute(
+            functionHash, ["bank.com/balance"], [], 2, 200000, "meta"
+        )).to.be.revertedWith("Invalid inputs");
+    });
+
     it("Should aggregate multiple node responses using median", async function () {
         await oracle.setMinResponses(3);
         const tx = await oracle.connect(requester).requestData("api.com/price", 0, 1000, "meta");
@@ -148,4 +161,140 @@ describe("ObscuraOracle Production Logic", function () {
         const endStaked = (await stakeGuard.stakers(node3.address)).balance;
         expect(startStaked - endStaked).to.equal(ethers.parseEther("10")); // SLASH_AMOUNT
     });
+
+    describe("Aggregated batches", function () {
+        const FIELD = 21888242871839275222246405745257275088548364400416034343698204186575808495617n;
+        const proof = Array(8).fill(0);
+        const commitments = Array(2).fill(0);
+        const pok = Array(2).fill(0);
+        let aggVerifier;
+
+        beforeEach(async function () {
+            const MockVerifier = await ethers.getContractFactory("MockVerifier");
+            aggVerifier = await MockVerifier.deploy();
+            await aggVerifier.waitForDeployment();
+            await oracle.setAggregationVerifier(await aggVerifier.getAddress());
+
+            await oracle.connect(requester).requestData("api.com/btc", 100, 200, "meta");
+            await oracle.connect(requester).requestData("api.com/eth", 10, 20, "meta");
+        });
+
+        it("Should commit to the padded values and bind each request's thresholds", async function () {
+            const input = await oracle.batchPublicInputs([0, 1], [150, 15]);
+
+            const padded = [150n, 15n, 15n, 15n, 15n, 15n, 15n, 15n];
+            const packed = ethers.solidityPacked(Array(8).fill("uint256"), padded);
+            expect(input[0]).to.equal(BigInt(ethers.keccak256(packed)) % FIELD);
+            expect(input.slice(1, 5)).to.deep.equal([100n, 200n, 10n, 20n]);
+            expect(input[15]).to.equal(10n);
+            expect(input[16]).to.equal(20n);
+
+            await expect(oracle.batchPublicInputs([], [])).to.be.revertedWith("Invalid batch");
+            await expect(oracle.batchPublicInputs([0, 1], [150])).to.be.revertedWith("Invalid batch");
+        });
+
+        it("Should fulfill every request of a verified batch", async function () {
+            await expect(oracle.connect(node1).fulfillDataBatch([0, 1], [150, 15], proof, commitments, pok))
+                .to.emit(oracle, "RequestFulfilled").withArgs(0, 150)
+                .and.to.emit(oracle, "RequestFulfilled").withArgs(1, 15);
+
+            expect((await oracle.requests(0)).finalValue).to.equal(150);
+            expect((await oracle.requests(1)).finalValue).to.equal(15);
+            expect(await oracle.latestAnswer()).to.equal(15);
+
+            // Resolved requests are skipped rather than failing the batch
+            await oracle.connect(requester).requestData("api.com/sol", 1, 5, "meta");
+            await expect(oracle.connect(node2).fulfillDataBatch([0, 2], [150, 3], proof, commitments, pok))
+                .to.emit(oracle, "RequestFulfilled").withArgs(2, 3);
+        });
+
+        it("Should reject batches with an invalid proof or from unknown nodes", async function () {
+            await aggVerifier.setRejecting(true);
+            await expect(oracle.connect(node1).fulfillDataBatch([0, 1], [150, 15], proof, commitments, pok))
+                .to.be.revertedWith("ProofInvalid");
+            expect((await oracle.requests(0)).resolved).to.be.false;
+
+            await aggVerifier.setRejecting(false);
+            await expect(oracle.connect(owner).fulfillDataBatch([0, 1], [150, 15], proof, commitments, pok))
+                .to.be.revertedWith("Not whitelisted");
+
+            await oracle.setAggregationVerifier(ethers.ZeroAddress);
+            await expect(oracle.connect(node1).fulfillDataBatch([0, 1], [150, 15], proof, commitments, pok))
+                .to.be.revertedWith("Batching disabled");
+        });
+    });
+
+    describe("Compute fulfillment", function () {
+        const proof = Array(8).fill(0);
+        const functionHash = ethers.sha256("0x0061736d01000000");
+        const inputs = (threshold, outcome) => ethers.keccak256(
+            ethers.AbiCoder.defaultAbiCoder().encode(["uint256", "uint256"], [threshold, outcome])
+        );
+        let computeVerifier;
+
+        beforeEach(async function () {
+            const MockVerifier = await ethers.getContractFactory("MockVerifier");
+            computeVerifier = await MockVerifier.deploy();
+            await computeVerifier.waitForDeployment();
+            await oracle.setComputeVerifier(await computeVerifier.getAddress());
+
+            await oracle.connect(requester).requestCompute(
+                functionHash, ["bank.com/balance"], ["checking"], 2, 200000, "meta"
+            );
+        });
+
+        it("Should settle either outcome against the request's threshold without a round", async function () {
+            await computeVerifier.setExpectedInputs(inputs(200000, 0));
+            await expect(oracle.connect(node1).fulfillCompute(0, false, proof))
+                .to.emit(oracle, "ComputeFulfilled").withArgs(0, false, node1.address)
+                .and.to.emit(oracle, "RequestFulfilled").withArgs(0, 0);
+
+            const req = await oracle.requests(0);
+            expect(req.resolved).to.be.true;
+            expect(req.finalValue).to.equal(0);
+            expect(await oracle.latestRoundId()).to.equal(0);
+            expect(await oracle.nodeRewards(node1.address)).to.equal(PAYMENT_FEE * 90n / 100n);
+
+            await expect(oracle.connect(node2).fulfillCompute(0, false, proof))
+                .to.be.revertedWith("Request already resolved");
+
+            await oracle.connect(requester).requestCompute(
+                functionHash, ["bank.com/balance"], ["checking"], 2, 150000, "meta"
+            );
+            await computeVerifier.setExpectedInputs(inputs(150000, 1));
+            await expect(oracle.connect(node1).fulfillCompute(1, true, proof))
+                .to.emit(oracle, "ComputeFulfilled").withArgs(1, true, node1.address);
+            expect((await oracle.requests(1)).finalValue).to.equal(1);
+            expect(await oracle.latestRoundId()).to.equal(0);
+        });
+
+        it("Should reject proofs for another threshold or outcome", async function () {
+            await computeVerifier.setExpectedInputs(inputs(100000, 1));
+            await expect(oracle.connect(node1).fulfillCompute(0, true, proof))
+                .to.be.revertedWith("ProofInvalid");
+
+            await computeVerifier.setExpectedInputs(inputs(200000, 0));
+            await expect(oracle.connect(node1).fulfillCompute(0, true, proof))
+                .to.be.revertedWith("ProofInvalid");
+            expect((await oracle.requests(0)).resolved).to.be.false;
+        });
+
+        it("Should keep compute requests out of the data fulfillment paths", async function () {
+            await expect(oracle.connect(node1).fulfillData(0, 1, proof, [200000, 0]))
+                .to.be.revertedWith("Use fulfillCompute");
+            await expect(oracle.connect(node1).fulfillDataOptimistic(0, 1))
+                .to.be.revertedWith("Use fulfillCompute");
+            await expect(oracle.forceFinalize(0)).to.be.revertedWith("Use fulfillCompute");
+
+            await oracle.connect(requester).requestData("api.com/btc", 100, 200, "meta");
+            await expect(oracle.connect(node1).fulfillCompute(1, true, proof))
+                .to.be.revertedWith("Not a compute request");
+            await expect(oracle.connect(owner).fulfillCompute(0, true, proof))
+                .to.be.revertedWith("Not whitelisted");
+
+            await oracle.setComputeVerifier(ethers.ZeroAddress);
+            await expect(oracle.connect(node1).fulfillCompute(0, true, proof))
+                .to.be.revertedWith("Compute disabled");
+        });
+    });
 });